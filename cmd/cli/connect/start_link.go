@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/api/connect"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+)
+
+// Prints a signed link that lets an artist connect their SoundCloud account, e.g.
+//
+//	go run . -artist 4f0c6a0e-5d6b-4a53-9d7c-2f8c0b9f3a11 -ttl 24h
+//
+// Hand the link only to the artist, whoever opens it can connect an account to them until it expires.
+func main() {
+	err := godotenv.Load("../../../.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	artist := flag.String("artist", "", "ID of the artist the link is for")
+	ttl := flag.Duration("ttl", 24*time.Hour, "how long the link stays valid")
+	baseURL := flag.String("base-url", "http://localhost:8080", "URL the service is reachable at")
+	flag.Parse()
+
+	artistID, err := uuid.Parse(*artist)
+	if err != nil {
+		log.Fatal("You must pass the ID of the artist with -artist.")
+	}
+	secret := os.Getenv("SOUNDCLOUD_CONNECT_SECRET")
+	if secret == "" {
+		log.Fatal("SOUNDCLOUD_CONNECT_SECRET is not set.")
+	}
+
+	query := connect.NewStartLinks(secret).Sign(artistID, time.Now().Add(*ttl))
+	fmt.Printf("%s/connect/soundcloud/start?%s\n", *baseURL, query.Encode())
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi/fakesoundcloud"
+)
+
+// Serves the fake SoundCloud API. Run the sync CLI against it with SOUNDCLOUD_BASE_URL=http://localhost:8089,
+// SOUNDCLOUD_TOKEN_URL=http://localhost:8089/oauth2/token and SOUNDCLOUD_OEMBED_URL=http://localhost:8089/oembed.
+func main() {
+	addr := flag.String("addr", ":8089", "address to listen on")
+	fixturesPath := flag.String("fixtures", "", "JSON fixtures file, defaults to the built-in fixtures")
+	mode := flag.String("mode", "", "failure mode: unauthorized, not_found, rate_limited, server_error, malformed or slow")
+	pageSize := flag.Int("page-size", 0, "maximum tracks per page, 0 for no cap")
+	flag.Parse()
+
+	fixtures := fakesoundcloud.DefaultFixtures()
+	if *fixturesPath != "" {
+		loaded, err := fakesoundcloud.LoadFixtures(*fixturesPath)
+		if err != nil {
+			log.Fatalf("Failed to load fixtures: %v", err)
+		}
+		fixtures = loaded
+	}
+
+	server := fakesoundcloud.New(fixtures, os.Getenv("SOUNDCLOUD_CLIENT_ID"), os.Getenv("SOUNDCLOUD_CLIENT_SECRET"))
+	server.PageSize = *pageSize
+	server.SetMode(fakesoundcloud.Mode(*mode))
+
+	log.Printf("Fake SoundCloud API listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, server.Handler()); err != nil {
+		log.Fatalf("Fake SoundCloud API stopped: %v", err)
+	}
+}
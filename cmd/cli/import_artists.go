@@ -73,8 +73,9 @@ func loadArtistsFromFile(filePath string) ([]artist.Artist, error) {
 		artistEntry.Name = ad.Name
 
 		if ad.SoundcloudLink != "" {
-			artistEntry.SCPermalink = new(string)
-			*artistEntry.SCPermalink = ad.SoundcloudLink
+			if err := artistEntry.SetPermalink(&ad.SoundcloudLink); err != nil {
+				return nil, fmt.Errorf("artist %s: %w", ad.Name, err)
+			}
 		}
 
 		artists = append(artists, artistEntry)
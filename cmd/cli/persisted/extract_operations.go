@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/blnto/blnto_service/internal/api/graphql/persisted"
+	"github.com/blnto/blnto_service/internal/infrastructure/graphql"
+)
+
+// Extracts the named operations of client code and of the request log into the persisted query registry, e.g. from
+// the repository root
+//
+//	go run ./cmd/cli/persisted -logs logs.json ../blnto_app/src
+//
+// Operations are validated against the schema; the registry files of operations already registered are left as is.
+func main() {
+	logs := flag.String("logs", "", "read the queries of this request log, e.g. logs.json")
+	out := flag.String("out", persisted.DefaultConfig().Dir, "write the registry files to this directory")
+	dryRun := flag.Bool("dry-run", false, "print the operations without writing any file")
+	flag.Parse()
+
+	if *logs == "" && flag.NArg() == 0 {
+		log.Fatal("You must pass a request log with -logs or at least one client source directory.")
+	}
+
+	var docs []string
+	for _, root := range flag.Args() {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if name := d.Name(); path != root && (name == "node_modules" || name[0] == '.') {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			switch filepath.Ext(path) {
+			case ".graphql", ".gql", ".js", ".jsx", ".ts", ".tsx":
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				docs = append(docs, persisted.ExtractDocuments(path, content)...)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", root, err)
+		}
+	}
+
+	if *logs != "" {
+		file, err := os.Open(*logs)
+		if err != nil {
+			log.Fatalf("Failed to open request log: %v", err)
+		}
+		queries, err := persisted.ExtractLogQueries(file)
+		file.Close()
+		if err != nil {
+			log.Fatalf("Failed to read request log: %v", err)
+		}
+		docs = append(docs, queries...)
+	}
+
+	schema := graphql.NewExecutableSchema(graphql.Config{}).Schema()
+	extractions, errs := persisted.SplitOperations(docs, schema)
+	for _, err := range errs {
+		fmt.Printf("skipped: %v\n", err)
+	}
+
+	registry, err := persisted.LoadRegistry(*out, schema)
+	if err != nil {
+		log.Fatalf("Failed to load the registry in %s: %v", *out, err)
+	}
+
+	taken := make(map[string]bool)
+	if entries, err := os.ReadDir(*out); err == nil {
+		for _, entry := range entries {
+			taken[entry.Name()] = true
+		}
+	}
+
+	added := 0
+	for _, extraction := range extractions {
+		if _, ok := registry.LookupNormalized(extraction.Query); ok {
+			fmt.Printf("%s: already registered\n", extraction.Name)
+			continue
+		}
+
+		name := extraction.FileName(taken)
+		taken[name] = true
+		added++
+		if *dryRun {
+			fmt.Printf("%s: would write %s\n%s\n\n", extraction.Name, name, extraction.Query)
+			continue
+		}
+		if err := os.MkdirAll(*out, 0o755); err != nil {
+			log.Fatalf("Failed to create %s: %v", *out, err)
+		}
+		if err := os.WriteFile(filepath.Join(*out, name), []byte(extraction.Query+"\n"), 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", name, err)
+		}
+		fmt.Printf("%s: wrote %s\n", extraction.Name, name)
+	}
+
+	fmt.Printf("%d of %d operations added to %s.\n", added, len(extractions), *out)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/blnto/blnto_service/internal"
+	"github.com/blnto/blnto_service/internal/application/raimport"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/residentadvisor"
+	"github.com/joho/godotenv"
+)
+
+// Imports Resident Advisor artist and event pages given as arguments, e.g.
+//
+//	go run . -dry-run https://ra.co/dj/name https://ra.co/events/123456
+func main() {
+	err := godotenv.Load("../../../.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	// Flags override the RESIDENT_ADVISOR_* settings
+	raConfig := internal.ProvideResidentAdvisorConfig()
+	config := raimport.DefaultConfig()
+	flag.BoolVar(&config.DryRun, "dry-run", false, "print the matches without storing anything")
+	flag.StringVar(&raConfig.FixtureDir, "fixtures", raConfig.FixtureDir, "read saved pages from this directory instead of fetching them")
+	flag.StringVar(&raConfig.BaseURL, "base-url", raConfig.BaseURL, "fetch pages from this server instead of ra.co")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		log.Fatal("You must pass at least one artist or event page URL.")
+	}
+
+	// Initialize database connection
+	app, err := internal.InitializeDependencies()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := residentadvisor.NewClient(residentadvisor.NewFetcher(raConfig))
+	importer := raimport.NewImporter(app.ArtistRepository, app.ProfileRepository, client, config, app.Logger)
+
+	failed := 0
+	for _, pageURL := range flag.Args() {
+		if err := importPage(ctx, importer, pageURL); err != nil {
+			fmt.Printf("%s: %v\n", pageURL, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Fatalf("%d of %d pages failed to import", failed, flag.NArg())
+	}
+}
+
+func importPage(ctx context.Context, importer *raimport.Importer, pageURL string) error {
+	if _, ok := residentadvisor.EventID(pageURL); ok {
+		result, err := importer.ImportEvent(ctx, pageURL)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s at %s, %d acts\n", result.Page.URL, result.Page.Name, result.Page.Venue, len(result.Lineup))
+		for _, match := range result.Lineup {
+			printMatch(match)
+		}
+		return nil
+	}
+
+	if _, ok := residentadvisor.ArtistSlug(pageURL); ok {
+		result, err := importer.ImportArtist(ctx, pageURL)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s:\n", result.Page.URL)
+		printMatch(result.Match)
+		return nil
+	}
+	return fmt.Errorf("not a resident advisor artist or event page")
+}
+
+func printMatch(match raimport.Match) {
+	switch {
+	case !match.Matched():
+		fmt.Printf("  %s: not matched, %s\n", match.Name, match.Problem)
+	case match.LinkAdded:
+		fmt.Printf("  %s: artist %s by %s, RA link %s added\n", match.Name, match.ArtistID, match.By, match.URL)
+	default:
+		fmt.Printf("  %s: artist %s by %s\n", match.Name, match.ArtistID, match.By)
+	}
+}
@@ -2,51 +2,53 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/blnto/blnto_service/internal"
-	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/blnto/blnto_service/internal/application/artistsync"
 	"github.com/joho/godotenv"
 )
 
 func main() {
-	// Initialize database connection
 	err := godotenv.Load("../../../.env")
 	if err != nil {
 		log.Fatalf("Error loading .env file: %v", err)
 	}
-	app, err := internal.InitializeDependencies() // Adjust with your actual setup function
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
-	}
 
-	// Fetch artists with permalink
-	artists, err := app.ArtistRepository.FindAllWithPermalink(context.TODO())
+	// Flags override the ARTIST_SYNC_* settings
+	config := internal.ProvideArtistSyncConfig()
+	dryRun := flag.Bool("dry-run", false, "print the profile changes without storing anything")
+	flag.IntVar(&config.Workers, "workers", config.Workers, "number of artists synced concurrently")
+	flag.DurationVar(&config.StaleAfter, "stale-after", config.StaleAfter, "sync artists last synced longer ago than this")
+	flag.Parse()
+
+	// Initialize database connection
+	app, err := internal.InitializeDependencies()
 	if err != nil {
-		log.Fatalf("Failed to fetch artists: %v", err)
+		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Initialize SoundCloud API client
-	scClient := artistApi.NewClient(app.DB) // Adjust accordingly
-
-	for _, artist := range artists {
-		// Fetch artist info from SoundCloud
-		permalink := artist.SCPermalink
-		scInfo, err := scClient.FetchArtistByPermalink(*permalink)
-		if err != nil {
-			log.Printf("Failed to fetch SoundCloud info for %s: %v", artist.Name, err)
-			continue
-		}
+	// An interrupted run is resumed by the next invocation
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-		// Update artist with SoundCloud info
-		artist.UpdateWithSoundCloudInfo(*scInfo) // Implement this method on your Artist model
+	engine := artistsync.NewEngine(app.SyncRunRepository, app.EnrichmentService, config, app.Logger)
 
-		// Save updated artist to the database
-		if artist, err := app.ArtistRepository.Update(context.TODO(), &artist); err != nil {
-			log.Printf("Failed to update artist %s: %v", artist.Name, err)
+	if *dryRun {
+		if err := engine.DryRun(ctx, os.Stdout); err != nil {
+			log.Fatalf("Dry run failed: %v", err)
 		}
+		return
 	}
 
-	fmt.Println("Artist information updated successfully.")
+	run, err := engine.Sync(ctx)
+	if err != nil {
+		log.Fatalf("Artist sync failed: %v", err)
+	}
+	fmt.Printf("Artist sync %s %s: %d synced, %d failed.\n", run.ID, run.Status, run.Succeeded, run.Failed)
 }
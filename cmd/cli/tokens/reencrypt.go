@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/blnto/blnto_service/internal/infrastructure/secrets"
+	"github.com/joho/godotenv"
+)
+
+// Re-encrypts the stored OAuth tokens, the app's and those of the artists' connected accounts, with the current key. Run it after adding a new key and pointing
+// TOKEN_ENCRYPTION_KEY_ID at it; the old key can be removed once it has finished.
+func main() {
+	err := godotenv.Load("../../../.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	keyring, err := secrets.LoadKeyring()
+	if err != nil {
+		log.Fatalf("Failed to load encryption keys: %v", err)
+	}
+
+	db, err := repository.ProvideDatabase()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer repository.CloseDatabaseConnection(db)
+
+	count, err := artistApi.NewDBTokenStore(db, keyring).Reencrypt()
+	if err != nil {
+		log.Fatalf("Failed to re-encrypt tokens after %d rows: %v", count, err)
+	}
+
+	accounts, err := artistApi.NewDBAccountStore(db, keyring).Reencrypt(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to re-encrypt account tokens after %d rows: %v", accounts, err)
+	}
+
+	fmt.Printf("Re-encrypted %d tokens and %d account tokens with key %s.\n", count, accounts, keyring.CurrentKeyID())
+}
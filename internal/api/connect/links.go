@@ -0,0 +1,69 @@
+package connect
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidArtistID is returned for start links whose artistId is not an artist ID
+	ErrInvalidArtistID = errors.New("artistId must be an artist ID")
+	// ErrUnauthorizedLink is returned for start links that are unsigned, tampered with or expired
+	ErrUnauthorizedLink = errors.New("the link is not valid for this artist or has expired")
+)
+
+// StartLinks signs the links that start a connection, so only whoever a link was issued to can connect an account to
+// its artist. A link carries the artistId, its expiry and an HMAC of both.
+type StartLinks struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewStartLinks creates the signer of start links. Without a secret no link is valid.
+func NewStartLinks(secret string) *StartLinks {
+	return &StartLinks{secret: []byte(secret), now: time.Now}
+}
+
+// Sign returns the query parameters of a start link for the artist that is valid until expires.
+func (l *StartLinks) Sign(artistID uuid.UUID, expires time.Time) url.Values {
+	expiry := strconv.FormatInt(expires.Unix(), 10)
+	return url.Values{
+		"artistId":  {artistID.String()},
+		"expires":   {expiry},
+		"signature": {l.signature(artistID.String(), expiry)},
+	}
+}
+
+// Verify returns the artist of a signed start link.
+func (l *StartLinks) Verify(query url.Values) (uuid.UUID, error) {
+	artistID, err := uuid.Parse(query.Get("artistId"))
+	if err != nil {
+		return uuid.Nil, ErrInvalidArtistID
+	}
+	if len(l.secret) == 0 {
+		return uuid.Nil, ErrUnauthorizedLink
+	}
+
+	expiry := query.Get("expires")
+	expires, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || !l.now().Before(time.Unix(expires, 0)) {
+		return uuid.Nil, ErrUnauthorizedLink
+	}
+	if !hmac.Equal([]byte(query.Get("signature")), []byte(l.signature(artistID.String(), expiry))) {
+		return uuid.Nil, ErrUnauthorizedLink
+	}
+	return artistID, nil
+}
+
+func (l *StartLinks) signature(artistID, expiry string) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(artistID + "\n" + expiry))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,123 @@
+// Package connect serves the HTTP routes artists visit in the browser to connect their accounts on providers.
+package connect
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/blnto/blnto_service/internal/application/service"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SoundCloudHandler runs the browser side of connecting a SoundCloud account: Start sends the artist to
+// SoundCloud, SoundCloud sends them back to Callback.
+type SoundCloudHandler struct {
+	accounts *service.SoundCloudAccountService
+	// links authorizes starting a connection for an artist
+	links *StartLinks
+	// returnURL receives the artist after the callback with status, artistId and error query parameters. Without
+	// it the callback answers with JSON.
+	returnURL string
+	logger    *zap.Logger
+}
+
+func NewSoundCloudHandler(accounts *service.SoundCloudAccountService, links *StartLinks, returnURL string, logger *zap.Logger) *SoundCloudHandler {
+	return &SoundCloudHandler{accounts: accounts, links: links, returnURL: returnURL, logger: logger}
+}
+
+// Start handles GET /connect/soundcloud/start?artistId=<id>&expires=<unix time>&signature=<signature>, a link signed
+// by StartLinks for the artist.
+func (h *SoundCloudHandler) Start(c *gin.Context) {
+	artistID, err := h.links.Verify(c.Request.URL.Query())
+	switch {
+	case errors.Is(err, ErrInvalidArtistID):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorizationURL, err := h.accounts.Start(c.Request.Context(), artistID)
+	switch {
+	case errors.Is(err, artistApi.ErrConnectionsDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		h.logger.Error("failed to start soundcloud connection", zap.String("artist", artistID.String()), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Redirect(http.StatusFound, authorizationURL)
+}
+
+// Callback handles GET /connect/soundcloud/callback?code=<code>&state=<state>, or ?error=<reason> when the artist
+// declined.
+func (h *SoundCloudHandler) Callback(c *gin.Context) {
+	if reason := c.Query("error"); reason != "" {
+		h.finish(c, http.StatusBadRequest, uuid.Nil, "soundcloud authorization failed: "+reason)
+		return
+	}
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		h.finish(c, http.StatusBadRequest, uuid.Nil, "state and code are required")
+		return
+	}
+
+	artistID, err := h.accounts.Complete(c.Request.Context(), state, code)
+	switch {
+	case errors.Is(err, artistApi.ErrInvalidState):
+		h.finish(c, http.StatusBadRequest, uuid.Nil, err.Error())
+	case errors.Is(err, apperror.ErrConflict):
+		// The account is connected to another artist, or another artist has its SoundCloud ID
+		var appErr *apperror.Error
+		errors.As(err, &appErr)
+		h.finish(c, http.StatusConflict, uuid.Nil, appErr.Message)
+	case err != nil:
+		h.logger.Error("failed to complete soundcloud connection", zap.Error(err))
+		h.finish(c, http.StatusBadGateway, uuid.Nil, "the soundcloud account could not be connected")
+	default:
+		h.finish(c, http.StatusOK, artistID, "")
+	}
+}
+
+// finish sends the artist to the return URL, or answers with JSON when there is none.
+func (h *SoundCloudHandler) finish(c *gin.Context, status int, artistID uuid.UUID, message string) {
+	result := "connected"
+	if message != "" {
+		result = "error"
+	}
+
+	if h.returnURL == "" {
+		body := gin.H{"status": result}
+		if artistID != uuid.Nil {
+			body["artistId"] = artistID
+		}
+		if message != "" {
+			body["error"] = message
+		}
+		c.JSON(status, body)
+		return
+	}
+
+	target, err := url.Parse(h.returnURL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid return url"})
+		return
+	}
+	query := target.Query()
+	query.Set("status", result)
+	if artistID != uuid.Nil {
+		query.Set("artistId", artistID.String())
+	}
+	if message != "" {
+		query.Set("error", message)
+	}
+	target.RawQuery = query.Encode()
+	c.Redirect(http.StatusFound, target.String())
+}
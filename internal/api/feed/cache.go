@@ -0,0 +1,104 @@
+package feed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Version is a stored object a response shows, at the time it was last updated.
+type Version struct {
+	ID        uuid.UUID
+	UpdatedAt time.Time
+}
+
+// Validators identify a response for conditional requests.
+type Validators struct {
+	// ETag is weak, the same response is served gzipped or not
+	ETag string
+	// LastModified is the latest update of the shown objects. It is zero for lists, it cannot tell that an object
+	// was removed from them, and then neither Last-Modified nor If-Modified-Since are used.
+	LastModified time.Time
+}
+
+// NewValidators derives the validators of the single resource response at resource from the objects it shows. The
+// ETag changes whenever one of them is updated, added or removed, Last-Modified only moves with updates.
+func NewValidators(resource string, versions ...Version) Validators {
+	var lastModified time.Time
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n", APIVersion, resource)
+	for _, v := range versions {
+		fmt.Fprintf(hash, "%s@%d\n", v.ID, v.UpdatedAt.UnixNano())
+		lastModified = latest(lastModified, v.UpdatedAt)
+	}
+	return Validators{
+		ETag: `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`,
+		// HTTP dates have a resolution of seconds
+		LastModified: lastModified.UTC().Truncate(time.Second),
+	}
+}
+
+// NewListValidators derives the validators of the list response at resource. Lists only get an ETag, a removed item
+// leaves the latest update time of the others unchanged and If-Modified-Since would keep serving it.
+func NewListValidators(resource string, versions ...Version) Validators {
+	validators := NewValidators(resource, versions...)
+	validators.LastModified = time.Time{}
+	return validators
+}
+
+// notModified evaluates the conditional headers of a GET request. If-None-Match takes precedence over
+// If-Modified-Since, as RFC 9110 requires.
+func (v Validators) notModified(r *http.Request) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(v.ETag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" && !v.LastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !v.LastModified.After(since)
+	}
+	return false
+}
+
+// Cache is the caching policy of the feed's responses.
+type Cache struct {
+	// MaxAge is how long clients and shared caches reuse a response before revalidating it
+	MaxAge time.Duration
+	// StaleWhileRevalidate lets caches serve a stale response while they revalidate it in the background
+	StaleWhileRevalidate time.Duration
+}
+
+func (c Cache) header() string {
+	header := fmt.Sprintf("public, max-age=%d", int(c.MaxAge.Seconds()))
+	if c.StaleWhileRevalidate > 0 {
+		header += fmt.Sprintf(", stale-while-revalidate=%d", int(c.StaleWhileRevalidate.Seconds()))
+	}
+	return header
+}
+
+// Respond sends body as JSON with the caching headers, or 304 Not Modified when the client's copy is current.
+func (c Cache) Respond(ctx *gin.Context, validators Validators, body interface{}) {
+	header := ctx.Writer.Header()
+	header.Set("Cache-Control", c.header())
+	header.Set("ETag", validators.ETag)
+	if !validators.LastModified.IsZero() {
+		header.Set("Last-Modified", validators.LastModified.Format(http.TimeFormat))
+	}
+
+	if validators.notModified(ctx.Request) {
+		ctx.AbortWithStatus(http.StatusNotModified)
+		return
+	}
+	ctx.JSON(http.StatusOK, body)
+}
@@ -0,0 +1,258 @@
+// Package feed serves a read-only JSON feed of venues, upcoming events, timetables and artists for partners that do
+// not speak GraphQL. Responses carry an ETag derived from the UpdatedAt of the objects they show, single resources
+// also Last-Modified, so clients revalidate with conditional requests and get 304 Not Modified while nothing changed.
+package feed
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/application/service"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// APIVersion is the version in the feed's paths. Incompatible changes to the representations need a new version,
+// it is part of every ETag.
+const APIVersion = "v1"
+
+// Config controls the caching headers and page sizes of the feed.
+type Config struct {
+	Cache Cache
+	// DefaultLimit is the page size of requests without a limit parameter, MaxLimit the largest one accepted
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// DefaultConfig returns the default settings, responses are fresh for a minute.
+func DefaultConfig() Config {
+	return Config{
+		Cache:        Cache{MaxAge: time.Minute, StaleWhileRevalidate: 5 * time.Minute},
+		DefaultLimit: 50,
+		MaxLimit:     200,
+	}
+}
+
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.DefaultLimit <= 0 {
+		c.DefaultLimit = defaults.DefaultLimit
+	}
+	if c.MaxLimit < c.DefaultLimit {
+		c.MaxLimit = c.DefaultLimit
+	}
+	return c
+}
+
+// Handler serves the feed from the application services.
+type Handler struct {
+	venues  *service.VenueService
+	events  *service.EventService
+	artists *service.ArtistService
+	config  Config
+	logger  *zap.Logger
+}
+
+func NewHandler(venues *service.VenueService, events *service.EventService, artists *service.ArtistService, config Config, logger *zap.Logger) *Handler {
+	return &Handler{venues: venues, events: events, artists: artists, config: config.withDefaults(), logger: logger}
+}
+
+// Register adds the feed's routes under /feed/<APIVersion>.
+func (h *Handler) Register(router gin.IRouter) {
+	group := router.Group("/feed/"+APIVersion, Gzip())
+	group.GET("/venues", h.Venues)
+	group.GET("/venues/:id", h.Venue)
+	group.GET("/venues/:id/events", h.VenueEvents)
+	group.GET("/events/upcoming", h.UpcomingEvents)
+	group.GET("/events/:id/timetable", h.Timetable)
+	group.GET("/artists", h.Artists)
+	group.GET("/artists/:id", h.Artist)
+}
+
+// Venues handles GET /venues?cursor=<cursor>&limit=<n>
+func (h *Handler) Venues(c *gin.Context) {
+	cursor, limit, ok := h.page(c)
+	if !ok {
+		return
+	}
+	venues, nextCursor, err := h.venues.GetVenues(c.Request.Context(), cursor, limit)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	list := List[Venue]{Items: make([]Venue, 0, len(venues))}
+	var versions []Version
+	for i := range venues {
+		list.Items = append(list.Items, venueOf(&venues[i]))
+		versions = append(versions, venueVersions(&venues[i])...)
+	}
+	if len(venues) == limit {
+		list.NextCursor = nextCursor
+	}
+	h.config.Cache.Respond(c, NewListValidators(c.Request.URL.RequestURI(), versions...), list)
+}
+
+// Venue handles GET /venues/:id
+func (h *Handler) Venue(c *gin.Context) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+	v, err := h.venues.GetVenue(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	h.config.Cache.Respond(c, NewValidators(c.Request.URL.RequestURI(), venueVersions(v)...), venueOf(v))
+}
+
+// VenueEvents handles GET /venues/:id/events?limit=<n>, the upcoming events of the venue
+func (h *Handler) VenueEvents(c *gin.Context) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+	// An unknown venue is a 404, not an empty list
+	if _, err := h.venues.GetVenue(c.Request.Context(), id); err != nil {
+		h.fail(c, err)
+		return
+	}
+	h.upcomingEvents(c, &id)
+}
+
+// UpcomingEvents handles GET /events/upcoming?limit=<n>, the events of all venues that have not ended yet
+func (h *Handler) UpcomingEvents(c *gin.Context) {
+	h.upcomingEvents(c, nil)
+}
+
+func (h *Handler) upcomingEvents(c *gin.Context, venueID *uuid.UUID) {
+	limit, ok := h.limit(c)
+	if !ok {
+		return
+	}
+	events, err := h.events.GetUpcoming(c.Request.Context(), venueID, limit)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	// Upcoming events are not paged, the list is cut at the limit
+	list := List[Event]{Items: make([]Event, 0, len(events))}
+	var versions []Version
+	for _, e := range events {
+		list.Items = append(list.Items, eventOf(e))
+		versions = append(versions, eventVersions(e)...)
+	}
+	h.config.Cache.Respond(c, NewListValidators(c.Request.URL.RequestURI(), versions...), list)
+}
+
+// Timetable handles GET /events/:id/timetable
+func (h *Handler) Timetable(c *gin.Context) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+	e, err := h.events.GetTimetable(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	h.config.Cache.Respond(c, NewValidators(c.Request.URL.RequestURI(), timetableVersions(e)...), timetableOf(e))
+}
+
+// Artists handles GET /artists?cursor=<cursor>&limit=<n>. Listed artists leave out their social media links.
+func (h *Handler) Artists(c *gin.Context) {
+	cursor, limit, ok := h.page(c)
+	if !ok {
+		return
+	}
+	artists, nextCursor, err := h.artists.GetArtists(c.Request.Context(), cursor, limit)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	list := List[Artist]{Items: make([]Artist, 0, len(artists))}
+	var versions []Version
+	for i := range artists {
+		list.Items = append(list.Items, artistOf(&artists[i]))
+		versions = append(versions, artistVersions(&artists[i])...)
+	}
+	if len(artists) == limit {
+		list.NextCursor = nextCursor
+	}
+	h.config.Cache.Respond(c, NewListValidators(c.Request.URL.RequestURI(), versions...), list)
+}
+
+// Artist handles GET /artists/:id
+func (h *Handler) Artist(c *gin.Context) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+	a, err := h.artists.GetArtist(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	h.config.Cache.Respond(c, NewValidators(c.Request.URL.RequestURI(), artistVersions(a)...), artistOf(a))
+}
+
+func (h *Handler) id(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.reject(c, http.StatusBadRequest, "id must be a UUID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// page reads the cursor and limit parameters, cursors are the ID of the last item of the previous page
+func (h *Handler) page(c *gin.Context) (string, int, bool) {
+	cursor := c.Query("cursor")
+	if cursor != "" {
+		if _, err := uuid.Parse(cursor); err != nil {
+			h.reject(c, http.StatusBadRequest, "cursor must be the nextCursor of a previous page")
+			return "", 0, false
+		}
+	}
+	limit, ok := h.limit(c)
+	return cursor, limit, ok
+}
+
+func (h *Handler) limit(c *gin.Context) (int, bool) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return h.config.DefaultLimit, true
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > h.config.MaxLimit {
+		h.reject(c, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(h.config.MaxLimit))
+		return 0, false
+	}
+	return limit, true
+}
+
+// fail answers with the status of the error's kind, unexpected errors are logged and not shown
+func (h *Handler) fail(c *gin.Context, err error) {
+	var appErr *apperror.Error
+	switch {
+	case errors.As(err, &appErr) && appErr.Kind == apperror.KindNotFound:
+		h.reject(c, http.StatusNotFound, appErr.Message)
+	case errors.As(err, &appErr) && appErr.Kind == apperror.KindValidation:
+		h.reject(c, http.StatusBadRequest, appErr.Message)
+	default:
+		h.logger.Error("feed request failed", zap.String("path", c.Request.URL.Path), zap.Error(err))
+		h.reject(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func (h *Handler) reject(c *gin.Context, status int, message string) {
+	c.Header("Cache-Control", "no-store")
+	c.AbortWithStatusJSON(status, gin.H{"error": message})
+}
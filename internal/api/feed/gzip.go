@@ -0,0 +1,99 @@
+package feed
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// Gzip compresses the responses of clients accepting gzip. Responses without a body, such as 304 Not Modified, and
+// responses that already set a Content-Encoding are sent as they are.
+func Gzip() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(c.Request) {
+			c.Next()
+			return
+		}
+
+		gz := gzipWriters.Get().(*gzip.Writer)
+		gz.Reset(c.Writer)
+		writer := &gzipWriter{ResponseWriter: c.Writer, gz: gz}
+		c.Writer = writer
+		defer func() {
+			if writer.compressing {
+				_ = gz.Close()
+			}
+			gz.Reset(nil)
+			gzipWriters.Put(gz)
+		}()
+
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows gzip, or any encoding, with a non-zero quality
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(key) == "q" {
+				quality, _ = strconv.ParseFloat(strings.TrimSpace(value), 64)
+			}
+		}
+		return quality > 0
+	}
+	return false
+}
+
+// gzipWriter decides on the first write whether to compress, once the status and headers are final
+type gzipWriter struct {
+	gin.ResponseWriter
+	gz          *gzip.Writer
+	decided     bool
+	compressing bool
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		status := w.Status()
+		if status != http.StatusNoContent && status != http.StatusNotModified && w.Header().Get("Content-Encoding") == "" {
+			w.compressing = true
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Del("Content-Length")
+		}
+	}
+	if w.compressing {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends the data compressed so far before flushing the connection, otherwise it stays in the gzip buffer
+func (w *gzipWriter) Flush() {
+	if w.compressing {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,198 @@
+package feed
+
+import (
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/event"
+	"github.com/blnto/blnto_service/internal/domain/stage"
+	"github.com/blnto/blnto_service/internal/domain/venue"
+	"github.com/google/uuid"
+)
+
+// List is a page of items. NextCursor is set when there may be more, pass it as the cursor parameter to fetch them.
+type List[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type Venue struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	Stages      []Stage   `json:"stages,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type VenueSummary struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type Stage struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type Event struct {
+	ID        uuid.UUID     `json:"id"`
+	Venue     *VenueSummary `json:"venue,omitempty"`
+	StartDate time.Time     `json:"startDate"`
+	EndDate   time.Time     `json:"endDate"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+// Timetable is an event with its running order.
+type Timetable struct {
+	Event   Event            `json:"event"`
+	Entries []TimetableEntry `json:"entries"`
+}
+
+type TimetableEntry struct {
+	ID        uuid.UUID      `json:"id"`
+	Stage     *Stage         `json:"stage,omitempty"`
+	Artist    *ArtistSummary `json:"artist,omitempty"`
+	StartTime time.Time      `json:"startTime"`
+	EndTime   time.Time      `json:"endTime"`
+}
+
+type ArtistSummary struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// Artist shows the display profile of an artist, the same values the GraphQL API resolves.
+type Artist struct {
+	ID                  uuid.UUID         `json:"id"`
+	Name                string            `json:"name"`
+	Location            string            `json:"location,omitempty"`
+	City                string            `json:"city,omitempty"`
+	Country             string            `json:"country,omitempty"`
+	AvatarURL           string            `json:"avatarUrl,omitempty"`
+	Description         string            `json:"description,omitempty"`
+	SoundcloudPermalink *string           `json:"soundcloudPermalink,omitempty"`
+	SocialMedia         []SocialMediaLink `json:"socialMedia,omitempty"`
+	UpdatedAt           time.Time         `json:"updatedAt"`
+}
+
+type SocialMediaLink struct {
+	Platform string `json:"platform"`
+	Link     string `json:"link"`
+}
+
+func venueOf(v *venue.Venue) Venue {
+	result := Venue{ID: v.ID, Name: v.Name, Description: v.Description, UpdatedAt: v.UpdatedAt}
+	for _, s := range v.Stages {
+		result.Stages = append(result.Stages, stageOf(s))
+		result.UpdatedAt = latest(result.UpdatedAt, s.UpdatedAt)
+	}
+	return result
+}
+
+func venueVersions(v *venue.Venue) []Version {
+	versions := []Version{{ID: v.ID, UpdatedAt: v.UpdatedAt}}
+	for _, s := range v.Stages {
+		versions = append(versions, Version{ID: s.ID, UpdatedAt: s.UpdatedAt})
+	}
+	return versions
+}
+
+func stageOf(s *stage.Stage) Stage {
+	return Stage{ID: s.ID, Name: s.StageName}
+}
+
+func eventOf(e *event.Event) Event {
+	result := Event{ID: e.ID, StartDate: e.StartDate, EndDate: e.EndDate, UpdatedAt: e.UpdatedAt}
+	if e.Venue != nil {
+		result.Venue = &VenueSummary{ID: e.Venue.ID, Name: e.Venue.Name}
+		result.UpdatedAt = latest(result.UpdatedAt, e.Venue.UpdatedAt)
+	}
+	return result
+}
+
+func eventVersions(e *event.Event) []Version {
+	versions := []Version{{ID: e.ID, UpdatedAt: e.UpdatedAt}}
+	if e.Venue != nil {
+		versions = append(versions, Version{ID: e.Venue.ID, UpdatedAt: e.Venue.UpdatedAt})
+	}
+	return versions
+}
+
+// timetableOf shows the event with its preloaded timetable. Entries, stages and artists count as updates of the
+// timetable.
+func timetableOf(e *event.Event) Timetable {
+	result := Timetable{Event: eventOf(e), Entries: make([]TimetableEntry, 0, len(e.Timetable))}
+	for _, entry := range e.Timetable {
+		shown := TimetableEntry{ID: entry.ID, StartTime: entry.StartTime, EndTime: entry.EndTime}
+		result.Event.UpdatedAt = latest(result.Event.UpdatedAt, entry.UpdatedAt)
+		if entry.Stage != nil {
+			stage := stageOf(entry.Stage)
+			shown.Stage = &stage
+			result.Event.UpdatedAt = latest(result.Event.UpdatedAt, entry.Stage.UpdatedAt)
+		}
+		if entry.Artist != nil {
+			shown.Artist = &ArtistSummary{ID: entry.Artist.ID, Name: entry.Artist.Name}
+			result.Event.UpdatedAt = latest(result.Event.UpdatedAt, entry.Artist.UpdatedAt)
+		}
+		result.Entries = append(result.Entries, shown)
+	}
+	return result
+}
+
+func timetableVersions(e *event.Event) []Version {
+	versions := eventVersions(e)
+	for _, entry := range e.Timetable {
+		versions = append(versions, Version{ID: entry.ID, UpdatedAt: entry.UpdatedAt})
+		if entry.Stage != nil {
+			versions = append(versions, Version{ID: entry.Stage.ID, UpdatedAt: entry.Stage.UpdatedAt})
+		}
+		if entry.Artist != nil {
+			versions = append(versions, Version{ID: entry.Artist.ID, UpdatedAt: entry.Artist.UpdatedAt})
+		}
+	}
+	return versions
+}
+
+// artistOf shows the artist with the links, provider profiles and overrides that were loaded with it. All of them
+// count as updates of the artist, the display profile is resolved from them.
+func artistOf(a *artist.Artist) Artist {
+	profile := a.DisplayProfile()
+	result := Artist{
+		ID:                  a.ID,
+		Name:                a.Name,
+		Location:            a.Location,
+		City:                profile.City,
+		Country:             profile.Country,
+		AvatarURL:           profile.AvatarURL,
+		Description:         profile.Description,
+		SoundcloudPermalink: a.SCPermalink,
+	}
+	for _, link := range a.SocialMediaLinks {
+		result.SocialMedia = append(result.SocialMedia, SocialMediaLink{Platform: string(link.Platform), Link: link.Link})
+	}
+	for _, v := range artistVersions(a) {
+		result.UpdatedAt = latest(result.UpdatedAt, v.UpdatedAt)
+	}
+	return result
+}
+
+func artistVersions(a *artist.Artist) []Version {
+	versions := []Version{{ID: a.ID, UpdatedAt: a.UpdatedAt}}
+	for _, link := range a.SocialMediaLinks {
+		versions = append(versions, Version{ID: link.ID, UpdatedAt: link.UpdatedAt})
+	}
+	for _, profile := range a.ExternalProfiles {
+		versions = append(versions, Version{ID: profile.ID, UpdatedAt: profile.UpdatedAt})
+	}
+	for _, override := range a.FieldOverrides {
+		versions = append(versions, Version{ID: override.ArtistID, UpdatedAt: override.UpdatedAt})
+	}
+	return versions
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,292 @@
+// Package constraint enforces the @constraint directive of the schema. It checks the arguments of every field before
+// its resolver runs and reports all violations of the operation's input at once, as a validation error listing the
+// rejected fields.
+package constraint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/blnto/blnto_service/internal/domain/validation"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// DirectiveName is the name of the directive in the schema
+const DirectiveName = "constraint"
+
+const extensionName = "Constraint"
+
+// rule is a parsed @constraint, nil limits are not checked
+type rule struct {
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+	url       bool
+	min       *int
+	max       *int
+}
+
+// Validator is a gqlgen handler extension enforcing @constraint.
+type Validator struct {
+	schema *ast.Schema
+	// rules of input fields keyed by "Input.field" and of arguments keyed by "Object.field(argument)"
+	rules map[string]*rule
+	// constrained holds the "Object.field" whose arguments reach a rule, the others are not walked
+	constrained map[string]bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.FieldInterceptor
+} = &Validator{}
+
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+func (v *Validator) ExtensionName() string {
+	return extensionName
+}
+
+// Validate parses the constraints of the schema. Constraints that cannot apply to their type and patterns that do
+// not compile fail the handler setup.
+func (v *Validator) Validate(schema graphql.ExecutableSchema) error {
+	v.schema = schema.Schema()
+	v.rules = make(map[string]*rule)
+	v.constrained = make(map[string]bool)
+
+	for _, def := range v.schema.Types {
+		switch def.Kind {
+		case ast.InputObject:
+			for _, field := range def.Fields {
+				if err := v.addRule(def.Name+"."+field.Name, field.Type, field.Directives); err != nil {
+					return err
+				}
+			}
+		case ast.Object, ast.Interface:
+			for _, field := range def.Fields {
+				for _, arg := range field.Arguments {
+					if err := v.addRule(argumentKey(def.Name, field.Name, arg.Name), arg.Type, arg.Directives); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	for _, def := range v.schema.Types {
+		if def.Kind != ast.Object {
+			continue
+		}
+		for _, field := range def.Fields {
+			for _, arg := range field.Arguments {
+				if v.rules[argumentKey(def.Name, field.Name, arg.Name)] != nil || v.reachesRule(arg.Type.Name(), map[string]bool{}) {
+					v.constrained[def.Name+"."+field.Name] = true
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func argumentKey(object, field, argument string) string {
+	return object + "." + field + "(" + argument + ")"
+}
+
+func (v *Validator) addRule(key string, typ *ast.Type, directives ast.DirectiveList) error {
+	directive := directives.ForName(DirectiveName)
+	if directive == nil {
+		return nil
+	}
+
+	r := &rule{}
+	for _, arg := range directive.Arguments {
+		value, err := arg.Value.Value(nil)
+		if err != nil {
+			return fmt.Errorf("@%s on %s: %w", DirectiveName, key, err)
+		}
+		switch arg.Name {
+		case "minLength":
+			r.minLength = intValue(value)
+		case "maxLength":
+			r.maxLength = intValue(value)
+		case "min":
+			r.min = intValue(value)
+		case "max":
+			r.max = intValue(value)
+		case "url":
+			r.url, _ = value.(bool)
+		case "pattern":
+			pattern, _ := value.(string)
+			if r.pattern, err = regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("@%s on %s: invalid pattern: %w", DirectiveName, key, err)
+			}
+		}
+	}
+
+	switch typ.Name() {
+	case "String", "ID":
+		if r.min != nil || r.max != nil {
+			return fmt.Errorf("@%s on %s: min and max apply to numbers, not %s", DirectiveName, key, typ.Name())
+		}
+	case "Int", "Float":
+		if r.minLength != nil || r.maxLength != nil || r.pattern != nil || r.url {
+			return fmt.Errorf("@%s on %s: length, pattern and url apply to strings, not %s", DirectiveName, key, typ.Name())
+		}
+	default:
+		return fmt.Errorf("@%s on %s: %s values cannot be constrained", DirectiveName, key, typ.Name())
+	}
+	v.rules[key] = r
+	return nil
+}
+
+// reachesRule reports whether a value of the named type can hold a constrained field
+func (v *Validator) reachesRule(name string, visited map[string]bool) bool {
+	def := v.schema.Types[name]
+	if def == nil || def.Kind != ast.InputObject || visited[name] {
+		return false
+	}
+	visited[name] = true
+
+	for _, field := range def.Fields {
+		if v.rules[name+"."+field.Name] != nil || v.reachesRule(field.Type.Name(), visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// InterceptField checks the arguments of the field and fails it with every violation before the resolver runs.
+func (v *Validator) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Field.Field == nil || fc.Field.Definition == nil || fc.Field.ObjectDefinition == nil {
+		return next(ctx)
+	}
+	object := fc.Field.ObjectDefinition.Name
+	if !v.constrained[object+"."+fc.Field.Name] {
+		return next(ctx)
+	}
+
+	var vars map[string]interface{}
+	if graphql.HasOperationContext(ctx) {
+		vars = graphql.GetOperationContext(ctx).Variables
+	}
+	args := fc.Field.ArgumentMap(vars)
+
+	var errs validation.Errors
+	for _, arg := range fc.Field.Definition.Arguments {
+		v.check(&errs, arg.Name, arg.Type, v.rules[argumentKey(object, fc.Field.Name, arg.Name)], args[arg.Name])
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return next(ctx)
+}
+
+// check validates a value of the given type against its rule and walks into lists and input objects
+func (v *Validator) check(errs *validation.Errors, path string, typ *ast.Type, r *rule, value interface{}) {
+	if value == nil {
+		return
+	}
+
+	if typ.Elem != nil {
+		if items, ok := value.([]interface{}); ok {
+			for i, item := range items {
+				v.check(errs, validation.Path(path, i), typ.Elem, r, item)
+			}
+			return
+		}
+		// A single value is coerced to a list of one
+		v.check(errs, path, typ.Elem, r, value)
+		return
+	}
+
+	if fields, ok := value.(map[string]interface{}); ok {
+		def := v.schema.Types[typ.Name()]
+		if def == nil || def.Kind != ast.InputObject {
+			return
+		}
+		for _, field := range def.Fields {
+			v.check(errs, validation.Path(path, field.Name), field.Type, v.rules[def.Name+"."+field.Name], fields[field.Name])
+		}
+		return
+	}
+
+	if r != nil {
+		r.check(errs, path, value)
+	}
+}
+
+func (r *rule) check(errs *validation.Errors, path string, value interface{}) {
+	switch value := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(value)
+		if r.minLength != nil && length < *r.minLength {
+			errs.Add(path, "%s must be at least %d characters long", fieldName(path), *r.minLength)
+		}
+		if r.maxLength != nil && length > *r.maxLength {
+			errs.Add(path, "%s must be at most %d characters long", fieldName(path), *r.maxLength)
+		}
+		if r.pattern != nil && !r.pattern.MatchString(value) {
+			errs.Add(path, "%s must match %s", fieldName(path), r.pattern)
+		}
+		if r.url && value != "" && !validation.IsURL(value) {
+			errs.Add(path, "%s must be an http or https URL", fieldName(path))
+		}
+	default:
+		number, ok := floatValue(value)
+		if !ok {
+			return
+		}
+		if r.min != nil && number < float64(*r.min) {
+			errs.Add(path, "%s must be at least %d", fieldName(path), *r.min)
+		}
+		if r.max != nil && number > float64(*r.max) {
+			errs.Add(path, "%s must be at most %d", fieldName(path), *r.max)
+		}
+	}
+}
+
+// fieldName is the last segment of a field path that is not a list index, e.g. "link" of "input.socialMedia.1.link"
+func fieldName(path string) string {
+	segments := strings.Split(path, ".")
+	for i := len(segments) - 1; i > 0; i-- {
+		if _, err := strconv.Atoi(segments[i]); err != nil {
+			return segments[i]
+		}
+	}
+	return segments[0]
+}
+
+func intValue(v interface{}) *int {
+	n, ok := floatValue(v)
+	if !ok {
+		return nil
+	}
+	i := int(n)
+	return &i
+}
+
+// floatValue reads a number, literals are int64 or float64 while variables keep the type they were decoded with
+func floatValue(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,110 @@
+package limits
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Cost is what an operation is estimated to cost.
+type Cost struct {
+	Complexity int `json:"complexity"`
+	Depth      int `json:"depth"`
+}
+
+// Calculate scores the operation. Every field costs its configured cost plus the cost of its selection, which counts
+// once per requested item for fields with a first argument. Introspection fields are free and add no depth.
+func Calculate(op *ast.OperationDefinition, vars map[string]interface{}, config Config) Cost {
+	w := walker{vars: vars, config: config.withDefaults()}
+	return w.selectionSet(op.SelectionSet)
+}
+
+type walker struct {
+	vars   map[string]interface{}
+	config Config
+}
+
+func (w walker) selectionSet(selections ast.SelectionSet) Cost {
+	var cost Cost
+	for _, selection := range selections {
+		var child Cost
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			child = w.field(s)
+		case *ast.FragmentSpread:
+			if s.Definition == nil {
+				continue
+			}
+			child = w.selectionSet(s.Definition.SelectionSet)
+		case *ast.InlineFragment:
+			child = w.selectionSet(s.SelectionSet)
+		}
+		cost.Complexity = safeAdd(cost.Complexity, child.Complexity)
+		cost.Depth = max(cost.Depth, child.Depth)
+	}
+	return cost
+}
+
+func (w walker) field(f *ast.Field) Cost {
+	children := w.selectionSet(f.SelectionSet)
+
+	fieldCost := w.config.DefaultFieldCost
+	if f.ObjectDefinition != nil {
+		if c, ok := w.config.FieldCosts[f.ObjectDefinition.Name+"."+f.Name]; ok {
+			fieldCost = c
+		}
+	}
+
+	return Cost{
+		Complexity: safeAdd(fieldCost, safeMul(w.items(f), children.Complexity)),
+		Depth:      children.Depth + 1,
+	}
+}
+
+// items returns how many items a field is asked for, the first argument of connections and one otherwise
+func (w walker) items(f *ast.Field) int {
+	if f.Definition == nil || f.Definition.Arguments.ForName("first") == nil {
+		return 1
+	}
+	if first, ok := toInt(f.ArgumentMap(w.vars)["first"]); ok && first > 0 {
+		return first
+	}
+	return w.config.DefaultPageSize
+}
+
+// toInt reads an Int argument, literals are int64 while variables keep the type they were decoded with
+func toInt(v interface{}) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(min(v, int64(maxInt))), true
+	case float64:
+		return int(min(v, float64(maxInt))), true
+	case json.Number:
+		n, err := v.Int64()
+		return int(min(n, int64(maxInt))), err == nil
+	}
+	return 0, false
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// safeAdd and safeMul saturate instead of overflowing, huge arguments must not wrap around into a cheap query
+func safeAdd(a, b int) int {
+	if a > maxInt-b {
+		return maxInt
+	}
+	return a + b
+}
+
+func safeMul(a, b int) int {
+	if a != 0 && b > maxInt/a {
+		return maxInt
+	}
+	return a * b
+}
@@ -0,0 +1,184 @@
+// Package limits protects the GraphQL endpoint from expensive operations. It scores every operation before it runs,
+// rejects those over the complexity or depth budget, and cancels operations that run longer than the timeout.
+package limits
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Error codes set in the extensions of rejected operations
+const (
+	CodeComplexityLimit = "COMPLEXITY_LIMIT_EXCEEDED"
+	CodeDepthLimit      = "DEPTH_LIMIT_EXCEEDED"
+	CodeTimeout         = "OPERATION_TIMEOUT"
+)
+
+const extensionName = "QueryLimits"
+
+func init() {
+	// Rejected operations never run, the transports answer them with 422 like other invalid operations
+	errcode.RegisterErrorType(CodeComplexityLimit, errcode.KindProtocol)
+	errcode.RegisterErrorType(CodeDepthLimit, errcode.KindProtocol)
+}
+
+var queryComplexity = promauto.NewHistogram(prometheus.HistogramOpts{
+	Name:    "graphql_query_complexity",
+	Help:    "Computed complexity of GraphQL operations.",
+	Buckets: prometheus.ExponentialBuckets(10, 2, 12),
+})
+
+var queryDepth = promauto.NewHistogram(prometheus.HistogramOpts{
+	Name:    "graphql_query_depth",
+	Help:    "Selection depth of GraphQL operations.",
+	Buckets: prometheus.LinearBuckets(1, 1, 15),
+})
+
+var queryRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "graphql_query_rejections_total",
+	Help: "GraphQL operations rejected or cancelled by reason.",
+}, []string{"reason"})
+
+// Config holds the budget of a single operation.
+type Config struct {
+	// MaxComplexity and MaxDepth reject operations scoring above them, zero disables the check
+	MaxComplexity int
+	MaxDepth      int
+	// Timeout cancels the resolvers of operations running longer, zero disables it
+	Timeout time.Duration
+	// DefaultFieldCost is the cost of fields missing from FieldCosts
+	DefaultFieldCost int
+	// FieldCosts overrides the cost of single fields, keyed by "Type.field"
+	FieldCosts map[string]int
+	// DefaultPageSize is the number of items assumed for connections queried without first, as the resolvers do
+	DefaultPageSize int
+}
+
+// DefaultConfig returns the default budget.
+func DefaultConfig() Config {
+	return Config{
+		MaxComplexity:    2500,
+		MaxDepth:         10,
+		Timeout:          10 * time.Second,
+		DefaultFieldCost: 1,
+		DefaultPageSize:  10,
+	}
+}
+
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.DefaultFieldCost <= 0 {
+		c.DefaultFieldCost = defaults.DefaultFieldCost
+	}
+	if c.DefaultPageSize <= 0 {
+		c.DefaultPageSize = defaults.DefaultPageSize
+	}
+	return c
+}
+
+// Stats is the cost of an operation next to the budget it was checked against, reported in the response extensions.
+type Stats struct {
+	Cost
+	MaxComplexity int `json:"maxComplexity,omitempty"`
+	MaxDepth      int `json:"maxDepth,omitempty"`
+}
+
+// Limiter is a gqlgen handler extension enforcing a Config.
+type Limiter struct {
+	config Config
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+	graphql.ResponseInterceptor
+} = &Limiter{}
+
+func NewLimiter(config Config) *Limiter {
+	return &Limiter{config: config.withDefaults()}
+}
+
+func (l *Limiter) ExtensionName() string {
+	return extensionName
+}
+
+func (l *Limiter) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationContext scores the operation and rejects it when it is over budget.
+func (l *Limiter) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if rc.Operation == nil {
+		return nil
+	}
+	stats := &Stats{
+		Cost:          Calculate(rc.Operation, rc.Variables, l.config),
+		MaxComplexity: l.config.MaxComplexity,
+		MaxDepth:      l.config.MaxDepth,
+	}
+	rc.Stats.SetExtension(extensionName, stats)
+	queryComplexity.Observe(float64(stats.Complexity))
+	queryDepth.Observe(float64(stats.Depth))
+
+	if l.config.MaxDepth > 0 && stats.Depth > l.config.MaxDepth {
+		queryRejections.WithLabelValues("depth").Inc()
+		return l.reject(stats, CodeDepthLimit, "operation has depth %d, which exceeds the limit of %d", stats.Depth, l.config.MaxDepth)
+	}
+	if l.config.MaxComplexity > 0 && stats.Complexity > l.config.MaxComplexity {
+		queryRejections.WithLabelValues("complexity").Inc()
+		return l.reject(stats, CodeComplexityLimit, "operation has complexity %d, which exceeds the limit of %d", stats.Complexity, l.config.MaxComplexity)
+	}
+	return nil
+}
+
+func (l *Limiter) reject(stats *Stats, code string, format string, args ...interface{}) *gqlerror.Error {
+	err := gqlerror.Errorf(format, args...)
+	errcode.Set(err, code)
+	err.Extensions["cost"] = stats
+	return err
+}
+
+// InterceptResponse runs the operation under the timeout and adds its cost to the response extensions.
+func (l *Limiter) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if l.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.config.Timeout)
+		defer cancel()
+	}
+
+	response := next(ctx)
+	if response == nil {
+		return nil
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		queryRejections.WithLabelValues("timeout").Inc()
+		err := gqlerror.Errorf("operation did not finish within %s", l.config.Timeout)
+		errcode.Set(err, CodeTimeout)
+		response.Errors = append(response.Errors, err)
+	}
+
+	if stats := GetStats(ctx); stats != nil {
+		if response.Extensions == nil {
+			response.Extensions = make(map[string]interface{})
+		}
+		response.Extensions["cost"] = stats
+	}
+	return response
+}
+
+// GetStats returns the cost of the running operation, nil outside of an operation.
+func GetStats(ctx context.Context) *Stats {
+	if !graphql.HasOperationContext(ctx) {
+		return nil
+	}
+	stats, _ := graphql.GetOperationContext(ctx).Stats.GetExtension(extensionName).(*Stats)
+	return stats
+}
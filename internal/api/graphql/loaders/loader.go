@@ -0,0 +1,132 @@
+// Package loaders batches the lookups of GraphQL field resolvers. Every request gets its own set of loaders: keys
+// requested while a batch is open are fetched with one query, and results are cached until the request ends.
+package loaders
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FetchFunc loads the values of a batch of distinct keys. Keys without a value are left out of the map.
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader collects the keys requested within Wait of the first one, up to MaxBatch, and fetches them at once.
+type Loader[K comparable, V any] struct {
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*entry[V]
+	batch *batch[K, V]
+}
+
+type entry[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	entries []*entry[V]
+	timer   *time.Timer
+}
+
+// NewLoader creates a loader. A maxBatch below one does not limit the batch size.
+func NewLoader[K comparable, V any](fetch FetchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{fetch: fetch, wait: wait, maxBatch: maxBatch, cache: make(map[K]*entry[V])}
+}
+
+// Load returns the value of the key, the zero value if the fetch function returned none.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	e, ok := l.cache[key]
+	if !ok {
+		e = &entry[V]{done: make(chan struct{})}
+		l.cache[key] = e
+		l.enqueue(ctx, key, e)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-e.done:
+		return e.value, e.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// LoadAll returns the values of the keys in the order of the keys.
+func (l *Loader[K, V]) LoadAll(ctx context.Context, keys []K) ([]V, error) {
+	values := make([]V, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key K) {
+			defer wg.Done()
+			values[i], errs[i] = l.Load(ctx, key)
+		}(i, key)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// enqueue adds the key to the open batch, opening one if there is none. Callers hold mu.
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, e *entry[V]) {
+	if l.batch == nil {
+		b := &batch[K, V]{}
+		// The batch outlives the resolver that opened it, the other keys belong to the same request
+		b.timer = time.AfterFunc(l.wait, func() { l.dispatch(context.WithoutCancel(ctx), b) })
+		l.batch = b
+	}
+	l.batch.keys = append(l.batch.keys, key)
+	l.batch.entries = append(l.batch.entries, e)
+
+	if l.maxBatch > 0 && len(l.batch.keys) >= l.maxBatch {
+		b := l.batch
+		l.batch = nil
+		if b.timer.Stop() {
+			go l.dispatch(context.WithoutCancel(ctx), b)
+		}
+	}
+}
+
+// dispatch fetches a batch and hands the results to the waiting loads
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	l.mu.Lock()
+	if l.batch == b {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	values, err := l.safeFetch(ctx, b.keys)
+	for i, key := range b.keys {
+		if err != nil {
+			b.entries[i].err = err
+		} else {
+			b.entries[i].value = values[key]
+		}
+		close(b.entries[i].done)
+	}
+}
+
+// safeFetch turns a panicking fetch function into an error, the loads of the batch would wait forever otherwise
+func (l *Loader[K, V]) safeFetch(ctx context.Context, keys []K) (values map[K]V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("loader panicked: %v", r)
+		}
+	}()
+	return l.fetch(ctx, keys)
+}
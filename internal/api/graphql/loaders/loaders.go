@@ -0,0 +1,153 @@
+package loaders
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/application/service"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+type contextKey struct{}
+
+// Config tunes the batching of the loaders.
+type Config struct {
+	// Wait is how long a batch collects keys after the first one
+	Wait time.Duration
+	// MaxBatch is the most keys fetched in one query, Postgres limits the parameters of a statement
+	MaxBatch int
+}
+
+// DefaultConfig returns the default loader settings.
+func DefaultConfig() Config {
+	return Config{Wait: 2 * time.Millisecond, MaxBatch: 500}
+}
+
+// Loaders holds the loaders of one request.
+type Loaders struct {
+	Venue                    *Loader[uuid.UUID, *models.Venue]
+	Stage                    *Loader[uuid.UUID, *models.Stage]
+	StagesByVenue            *Loader[uuid.UUID, []*models.Stage]
+	Artist                   *Loader[uuid.UUID, *models.Artist]
+	Event                    *Loader[uuid.UUID, *models.Event]
+	TimetableEntry           *Loader[uuid.UUID, *models.TimetableEntry]
+	SocialMediaLinksByArtist *Loader[uuid.UUID, []*models.SocialMedia]
+	TimetableByEvent         *Loader[uuid.UUID, []*models.TimetableEntry]
+	PromotedSetByArtist      *Loader[uuid.UUID, *models.PromotedSet]
+	ResidencyByEntry         *Loader[uuid.UUID, *models.Residency]
+}
+
+// Factory creates the loaders of each request.
+type Factory struct {
+	artistService      *service.ArtistService
+	eventService       *service.EventService
+	stageService       *service.StageService
+	venueService       *service.VenueService
+	promotedSetService *service.PromotedSetService
+	residencyService   *service.ResidencyService
+	config             Config
+}
+
+func NewFactory(artistService *service.ArtistService, eventService *service.EventService, stageService *service.StageService, venueService *service.VenueService, promotedSetService *service.PromotedSetService, residencyService *service.ResidencyService, config Config) *Factory {
+	return &Factory{artistService: artistService, eventService: eventService, stageService: stageService, venueService: venueService, promotedSetService: promotedSetService, residencyService: residencyService, config: config}
+}
+
+// New returns a fresh set of loaders with empty caches.
+func (f *Factory) New() *Loaders {
+	return &Loaders{
+		Venue: newLoader(f.config, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Venue, error) {
+			venues, err := f.venueService.FindByIDs(ctx, ids)
+			return byID(venues, err, func(v *models.Venue) uuid.UUID { return v.ID })
+		}),
+		Stage: newLoader(f.config, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Stage, error) {
+			stages, err := f.stageService.FindByIDs(ctx, ids)
+			return byID(stages, err, func(s *models.Stage) uuid.UUID { return s.ID })
+		}),
+		StagesByVenue: newLoader(f.config, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*models.Stage, error) {
+			stages, err := f.stageService.FindByVenueIDs(ctx, ids)
+			return groupBy(ids, stages, err, func(s *models.Stage) uuid.UUID { return s.VenueID })
+		}),
+		Artist: newLoader(f.config, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Artist, error) {
+			artists, err := f.artistService.FindByIDs(ctx, ids)
+			return byID(artists, err, func(a *models.Artist) uuid.UUID { return a.ID })
+		}),
+		Event: newLoader(f.config, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Event, error) {
+			events, err := f.eventService.FindByIDs(ctx, ids)
+			return byID(events, err, func(e *models.Event) uuid.UUID { return e.ID })
+		}),
+		TimetableEntry: newLoader(f.config, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.TimetableEntry, error) {
+			entries, err := f.eventService.FindTimetableEntriesByIDs(ctx, ids)
+			return byID(entries, err, func(e *models.TimetableEntry) uuid.UUID { return e.ID })
+		}),
+		SocialMediaLinksByArtist: newLoader(f.config, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*models.SocialMedia, error) {
+			links, err := f.artistService.FindSocialMediaLinksByArtistIDs(ctx, ids)
+			return groupBy(ids, links, err, func(l *models.SocialMedia) uuid.UUID {
+				if l.ArtistID == nil {
+					return uuid.Nil
+				}
+				return *l.ArtistID
+			})
+		}),
+		TimetableByEvent: newLoader(f.config, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*models.TimetableEntry, error) {
+			entries, err := f.eventService.FindTimetablesByEventIDs(ctx, ids)
+			return groupBy(ids, entries, err, func(e *models.TimetableEntry) uuid.UUID { return e.EventID })
+		}),
+		PromotedSetByArtist: newLoader(f.config, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.PromotedSet, error) {
+			return f.promotedSetService.FindByArtistIDs(ctx, ids)
+		}),
+		ResidencyByEntry: newLoader(f.config, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Residency, error) {
+			return f.residencyService.FindActiveForTimetableEntries(ctx, ids)
+		}),
+	}
+}
+
+// Middleware puts fresh loaders into the context of every request, so caches never outlive a request.
+func (f *Factory) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), contextKey{}, f.New())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// For returns the loaders of the request. Outside of the middleware, e.g. in tests, it returns fresh loaders that
+// batch nothing across calls.
+func (f *Factory) For(ctx context.Context) *Loaders {
+	if l, ok := ctx.Value(contextKey{}).(*Loaders); ok {
+		return l
+	}
+	return f.New()
+}
+
+func newLoader[V any](config Config, fetch FetchFunc[uuid.UUID, V]) *Loader[uuid.UUID, V] {
+	return NewLoader(fetch, config.Wait, config.MaxBatch)
+}
+
+func byID[V any](values []V, err error, key func(V) uuid.UUID) (map[uuid.UUID]V, error) {
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uuid.UUID]V, len(values))
+	for _, v := range values {
+		result[key(v)] = v
+	}
+	return result, nil
+}
+
+// groupBy groups the values by key. Every requested key gets a non-nil slice, so "none" is cached as well.
+func groupBy[V any](keys []uuid.UUID, values []V, err error, key func(V) uuid.UUID) (map[uuid.UUID][]V, error) {
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uuid.UUID][]V, len(keys))
+	for _, k := range keys {
+		result[k] = []V{}
+	}
+	for _, v := range values {
+		if group, ok := result[key(v)]; ok {
+			result[key(v)] = append(group, v)
+		}
+	}
+	return result, nil
+}
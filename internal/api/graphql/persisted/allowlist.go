@@ -0,0 +1,132 @@
+package persisted
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// CodeNotAllowed is set in the extensions of operations rejected in strict mode
+const CodeNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+
+func init() {
+	errcode.RegisterErrorType(CodeNotAllowed, errcode.KindProtocol)
+}
+
+// Config controls how the endpoint treats operations that are not registered.
+type Config struct {
+	// Dir holds the registry files
+	Dir string
+	// Strict executes registered operations only
+	Strict bool
+	// BypassHeader carrying BypassToken lets a request run any operation in strict mode. An empty token disables it.
+	BypassHeader string
+	BypassToken  string
+	// CacheSize is the number of queries automatic persisted queries remember besides the registered ones
+	CacheSize int
+}
+
+// DefaultConfig returns the default settings, strict mode is off.
+func DefaultConfig() Config {
+	return Config{Dir: "operations", BypassHeader: "X-Persisted-Query-Bypass", CacheSize: 100}
+}
+
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.BypassHeader == "" {
+		c.BypassHeader = defaults.BypassHeader
+	}
+	if c.CacheSize <= 0 {
+		c.CacheSize = defaults.CacheSize
+	}
+	return c
+}
+
+// Cache is the cache of automatic persisted queries. Registered operations are always found by their hash; other
+// queries are remembered outside of strict mode only, where they could not run anyway.
+type Cache struct {
+	registry *Registry
+	recent   *lru.LRU
+	strict   bool
+}
+
+var _ graphql.Cache = &Cache{}
+
+func NewCache(registry *Registry, config Config) *Cache {
+	config = config.withDefaults()
+	return &Cache{registry: registry, recent: lru.New(config.CacheSize), strict: config.Strict}
+}
+
+func (c *Cache) Get(ctx context.Context, key string) (interface{}, bool) {
+	if op, ok := c.registry.Lookup(key); ok {
+		return op.Query, true
+	}
+	if c.strict {
+		return nil, false
+	}
+	return c.recent.Get(ctx, key)
+}
+
+func (c *Cache) Add(ctx context.Context, key string, value interface{}) {
+	if !c.strict {
+		c.recent.Add(ctx, key, value)
+	}
+}
+
+// AllowList is a gqlgen handler extension rejecting unregistered operations in strict mode.
+type AllowList struct {
+	registry *Registry
+	config   Config
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &AllowList{}
+
+func NewAllowList(registry *Registry, config Config) *AllowList {
+	return &AllowList{registry: registry, config: config.withDefaults()}
+}
+
+// Cache returns the automatic persisted query cache backed by the same registry.
+func (a *AllowList) Cache() *Cache {
+	return NewCache(a.registry, a.config)
+}
+
+func (a *AllowList) ExtensionName() string {
+	return "PersistedQueryAllowList"
+}
+
+func (a *AllowList) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (a *AllowList) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if !a.config.Strict || rc.Operation == nil || a.bypass(rc) {
+		return nil
+	}
+	if _, ok := a.registry.Match(rc.Operation, rc.Doc.Fragments); ok {
+		return nil
+	}
+
+	name := rc.Operation.Name
+	if name == "" {
+		name = "anonymous operation"
+	}
+	err := gqlerror.Errorf("%s is not a registered operation", name)
+	errcode.Set(err, CodeNotAllowed)
+	return err
+}
+
+// bypass reports whether the request carries the bypass token of trusted internal tooling
+func (a *AllowList) bypass(rc *graphql.OperationContext) bool {
+	if a.config.BypassToken == "" || rc.Headers == nil {
+		return false
+	}
+	token := rc.Headers.Get(a.config.BypassHeader)
+	return subtle.ConstantTimeCompare([]byte(token), []byte(a.config.BypassToken)) == 1
+}
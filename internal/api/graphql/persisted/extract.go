@@ -0,0 +1,140 @@
+package persisted
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// templateLiteral matches gql`...` and graphql`...` tagged templates in JavaScript and TypeScript
+var templateLiteral = regexp.MustCompile("(?s)\\b(?:gql|graphql)\\s*(?:\\(\\s*)?`(.*?)`")
+
+// interpolation matches ${...} in template literals, mostly fragments that are extracted on their own
+var interpolation = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// ExtractDocuments returns the GraphQL documents of a client source file: the whole file for .graphql and .gql
+// files, the gql and graphql tagged templates otherwise.
+func ExtractDocuments(path string, content []byte) []string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".graphql", ".gql":
+		return []string{string(content)}
+	}
+	var docs []string
+	for _, match := range templateLiteral.FindAllSubmatch(content, -1) {
+		docs = append(docs, interpolation.ReplaceAllString(string(match[1]), ""))
+	}
+	return docs
+}
+
+// ExtractLogQueries returns the queries of the "GraphQL Request" entries of a request log as written to logs.json,
+// in both the current format and the older one holding the request in formattedQuery.
+func ExtractLogQueries(r io.Reader) ([]string, error) {
+	var queries []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			Msg            string `json:"msg"`
+			Query          string `json:"query"`
+			FormattedQuery string `json:"formattedQuery"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Msg != "GraphQL Request" {
+			continue
+		}
+		if entry.Query == "" && entry.FormattedQuery != "" {
+			var request struct {
+				Query string `json:"query"`
+			}
+			if err := json.Unmarshal([]byte(entry.FormattedQuery), &request); err == nil {
+				entry.Query = request.Query
+			}
+		}
+		if strings.TrimSpace(entry.Query) != "" {
+			queries = append(queries, entry.Query)
+		}
+	}
+	return queries, scanner.Err()
+}
+
+// Extraction is an operation ready to be written to the registry.
+type Extraction struct {
+	Name string
+	// Query is the normalized operation with the fragments it uses
+	Query string
+}
+
+// SplitOperations splits the documents into their named operations. Fragments may be defined in any of the documents.
+// Operations that do not validate against the schema are returned as errors, duplicates are dropped.
+func SplitOperations(docs []string, schema *ast.Schema) ([]Extraction, []error) {
+	var (
+		operations ast.OperationList
+		fragments  ast.FragmentDefinitionList
+		errs       []error
+	)
+	for i, doc := range docs {
+		parsed, err := parser.ParseQuery(&ast.Source{Name: fmt.Sprintf("document %d", i+1), Input: doc})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		operations = append(operations, parsed.Operations...)
+		for _, fragment := range parsed.Fragments {
+			if fragments.ForName(fragment.Name) == nil {
+				fragments = append(fragments, fragment)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var extractions []Extraction
+	for _, op := range operations {
+		query := Normalize(op, fragments)
+		if seen[query] {
+			continue
+		}
+		seen[query] = true
+		if op.Name == "" {
+			errs = append(errs, fmt.Errorf("skipping anonymous %s operation, only named operations can be registered", op.Operation))
+			continue
+		}
+		if isIntrospection(op) {
+			errs = append(errs, fmt.Errorf("skipping %s, introspection stays with trusted tooling", op.Name))
+			continue
+		}
+		if _, validationErrs := gqlparser.LoadQuery(schema, query); validationErrs != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", op.Name, validationErrs))
+			continue
+		}
+		extractions = append(extractions, Extraction{Name: op.Name, Query: query})
+	}
+	sort.SliceStable(extractions, func(i, j int) bool { return extractions[i].Name < extractions[j].Name })
+	return extractions, errs
+}
+
+// isIntrospection reports whether the operation only queries the schema, as the playground does
+func isIntrospection(op *ast.OperationDefinition) bool {
+	for _, selection := range op.SelectionSet {
+		if field, ok := selection.(*ast.Field); !ok || !strings.HasPrefix(field.Name, "__") {
+			return false
+		}
+	}
+	return len(op.SelectionSet) > 0
+}
+
+// FileName returns the registry file name of the extraction. Further versions of an operation get their hash appended.
+func (e Extraction) FileName(taken map[string]bool) string {
+	name := e.Name + FileExtension
+	if taken[name] {
+		name = e.Name + "." + Hash(e.Query)[:8] + FileExtension
+	}
+	return name
+}
@@ -0,0 +1,171 @@
+// Package persisted keeps the registry of approved GraphQL operations. It backs automatic persisted queries, lets the
+// endpoint run in a strict mode that executes registered operations only, and extracts operations from client code
+// and request logs to fill the registry.
+package persisted
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// FileExtension is the extension of registry files, each holding one operation and the fragments it uses.
+const FileExtension = ".graphql"
+
+// Operation is a registered operation.
+type Operation struct {
+	Name string
+	// Query is the text of the operation as stored in the registry, without surrounding whitespace
+	Query string
+	// Hash is the SHA-256 of Query, the hash automatic persisted queries send for it
+	Hash string
+	// Normalized is the canonical form, requests match it however they format the operation
+	Normalized string
+}
+
+// Registry holds the approved operations. It is safe for concurrent use.
+type Registry struct {
+	mu           sync.RWMutex
+	byHash       map[string]*Operation
+	byNormalized map[string]*Operation
+}
+
+func NewRegistry() *Registry {
+	return &Registry{byHash: make(map[string]*Operation), byNormalized: make(map[string]*Operation)}
+}
+
+// LoadRegistry registers the operations of the registry files in dir, validated against the schema. A missing
+// directory yields an empty registry.
+func LoadRegistry(dir string, schema *ast.Schema) (*Registry, error) {
+	registry := NewRegistry()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+FileExtension))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var errs []error
+	for _, file := range files {
+		query, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := registry.Register(string(query), schema); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(file), err))
+		}
+	}
+	return registry, errors.Join(errs...)
+}
+
+// Register validates the query, which must hold exactly one named operation, and adds it to the registry.
+func (r *Registry) Register(query string, schema *ast.Schema) (*Operation, error) {
+	doc, errs := gqlparser.LoadQuery(schema, query)
+	if errs != nil {
+		return nil, errs
+	}
+	if len(doc.Operations) != 1 || doc.Operations[0].Name == "" {
+		return nil, fmt.Errorf("a registered query must hold exactly one named operation")
+	}
+
+	query = strings.TrimSpace(query)
+	op := &Operation{
+		Name:       doc.Operations[0].Name,
+		Query:      query,
+		Hash:       Hash(query),
+		Normalized: Normalize(doc.Operations[0], doc.Fragments),
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byHash[op.Hash] = op
+	r.byNormalized[Hash(op.Normalized)] = op
+	return op, nil
+}
+
+// Lookup returns the operation with the hash.
+func (r *Registry) Lookup(hash string) (*Operation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	op, ok := r.byHash[hash]
+	return op, ok
+}
+
+// Match returns the registered operation that is the given operation in another format.
+func (r *Registry) Match(op *ast.OperationDefinition, fragments ast.FragmentDefinitionList) (*Operation, bool) {
+	return r.LookupNormalized(Normalize(op, fragments))
+}
+
+// LookupNormalized returns the operation with the normalized form.
+func (r *Registry) LookupNormalized(normalized string) (*Operation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	op, ok := r.byNormalized[Hash(normalized)]
+	return op, ok
+}
+
+// Len returns the number of registered operations.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.byNormalized)
+}
+
+// Hash returns the hex SHA-256 of the query as automatic persisted queries compute it.
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// Normalize prints the operation and the fragments it uses, sorted by name, in the canonical format. Whitespace,
+// comments, unused fragments and the order of definitions do not change the result.
+func Normalize(op *ast.OperationDefinition, fragments ast.FragmentDefinitionList) string {
+	doc := &ast.QueryDocument{
+		Operations: ast.OperationList{op},
+		Fragments:  usedFragments(op.SelectionSet, fragments),
+	}
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return strings.TrimSpace(buf.String())
+}
+
+// usedFragments returns the fragments the selection set spreads, directly or through other fragments
+func usedFragments(selections ast.SelectionSet, fragments ast.FragmentDefinitionList) ast.FragmentDefinitionList {
+	used := make(map[string]*ast.FragmentDefinition)
+	var walk func(ast.SelectionSet)
+	walk = func(selections ast.SelectionSet) {
+		for _, selection := range selections {
+			switch s := selection.(type) {
+			case *ast.Field:
+				walk(s.SelectionSet)
+			case *ast.InlineFragment:
+				walk(s.SelectionSet)
+			case *ast.FragmentSpread:
+				if _, ok := used[s.Name]; ok {
+					continue
+				}
+				if fragment := fragments.ForName(s.Name); fragment != nil {
+					used[s.Name] = fragment
+					walk(fragment.SelectionSet)
+				}
+			}
+		}
+	}
+	walk(selections)
+
+	result := make(ast.FragmentDefinitionList, 0, len(used))
+	for _, fragment := range used {
+		result = append(result, fragment)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
@@ -0,0 +1,78 @@
+// Package presenter turns the errors of resolvers into GraphQL errors. Errors classified by apperror report their
+// kind as extensions.code, validation errors list the rejected fields in extensions.fields, and panics are logged
+// and answered with an internal error.
+package presenter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/zap"
+)
+
+// ErrorPresenter returns the error presenter of the GraphQL handler.
+func ErrorPresenter(logger *zap.Logger) graphql.ErrorPresenterFunc {
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		presented := graphql.DefaultErrorPresenter(ctx, err)
+
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) {
+			// Parser, validation and extension errors come as gqlerrors and set their own code
+			var gqlErr *gqlerror.Error
+			if errors.As(err, &gqlErr) {
+				return presented
+			}
+			// Unclassified errors may carry SQL or upstream details, they stay in the logs
+			logger.Error("GraphQL resolver error", operationFields(ctx, zap.Error(err))...)
+			presented.Message = "internal server error"
+			errcode.Set(presented, string(apperror.KindInternal))
+			return presented
+		}
+
+		switch appErr.Kind {
+		case apperror.KindInternal:
+			logger.Error("GraphQL resolver error", operationFields(ctx, zap.Error(err))...)
+		case apperror.KindUpstream:
+			logger.Warn("GraphQL upstream error", operationFields(ctx, zap.Error(err))...)
+		}
+		// The cause of an error stays in the logs, clients only see what it means for them
+		if appErr.Err != nil {
+			presented.Message = appErr.Message
+		}
+		errcode.Set(presented, string(appErr.Kind))
+		if len(appErr.Fields) > 0 {
+			presented.Extensions["fields"] = appErr.Fields
+		}
+		return presented
+	}
+}
+
+// Recover returns the recover function of the GraphQL handler. It logs the panic with the operation it happened in
+// and reports an internal error, the other fields of the operation still resolve.
+func Recover(logger *zap.Logger) graphql.RecoverFunc {
+	return func(ctx context.Context, p interface{}) error {
+		logger.Error("GraphQL resolver panic", operationFields(ctx, zap.Any("panic", p), zap.Stack("stack"))...)
+		return apperror.Internal(fmt.Errorf("panic: %v", p))
+	}
+}
+
+// operationFields adds the operation name and the path of the failed field to the log fields
+func operationFields(ctx context.Context, fields ...zap.Field) []zap.Field {
+	if graphql.HasOperationContext(ctx) {
+		oc := graphql.GetOperationContext(ctx)
+		name := oc.OperationName
+		if name == "" && oc.Operation != nil {
+			name = oc.Operation.Name
+		}
+		fields = append(fields, zap.String("operation", name))
+	}
+	if path := graphql.GetPath(ctx); len(path) > 0 {
+		fields = append(fields, zap.String("path", path.String()))
+	}
+	return fields
+}
@@ -0,0 +1,132 @@
+// Package relay implements the global object identification of the Relay specification. Types implementing the Node
+// interface expose their id as a global ID naming the type, so the node and nodes queries can refetch any object from
+// its ID alone and client caches can normalize objects by ID.
+package relay
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+)
+
+// NodeInterface is the name of the interface whose implementations get global IDs
+const NodeInterface = "Node"
+
+// GlobalID identifies an object among all types.
+type GlobalID struct {
+	Type string
+	ID   uuid.UUID
+}
+
+// String encodes the global ID the way Relay servers commonly do: base64 of "Type:id". Clients must treat it as opaque.
+func (g GlobalID) String() string {
+	return base64.StdEncoding.EncodeToString([]byte(g.Type + ":" + g.ID.String()))
+}
+
+// ParseGlobalID decodes a global ID.
+func ParseGlobalID(s string) (GlobalID, error) {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return GlobalID{}, fmt.Errorf("%q is not a global ID", s)
+	}
+	typeName, id, ok := strings.Cut(string(decoded), ":")
+	if !ok || typeName == "" {
+		return GlobalID{}, fmt.Errorf("%q is not a global ID", s)
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return GlobalID{}, fmt.Errorf("%q is not a global ID: %w", s, err)
+	}
+	return GlobalID{Type: typeName, ID: parsed}, nil
+}
+
+// MarshalID writes the ID scalar. The id field of types implementing Node is written as a global ID, every other ID,
+// such as the venueID of an event, as the plain UUID. The zero UUID is an error, it means the ID was never set.
+func MarshalID(id uuid.UUID) graphql.ContextMarshaler {
+	return graphql.ContextWriterFunc(func(ctx context.Context, w io.Writer) error {
+		if id == uuid.Nil {
+			return fmt.Errorf("the zero UUID is not an ID")
+		}
+		if typeName, ok := nodeType(ctx); ok {
+			graphql.MarshalString(GlobalID{Type: typeName, ID: id}.String()).MarshalGQL(w)
+			return nil
+		}
+		graphql.MarshalString(id.String()).MarshalGQL(w)
+		return nil
+	})
+}
+
+// UnmarshalID reads the ID scalar. Arguments accept global IDs as well as plain UUIDs, so clients that stored the
+// UUIDs returned before global IDs keep working.
+func UnmarshalID(_ context.Context, v interface{}) (uuid.UUID, error) {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return uuid.Nil, fmt.Errorf("%T is not an ID", v)
+	}
+	if id, err := uuid.Parse(s); err == nil {
+		return id, nil
+	}
+	global, err := ParseGlobalID(s)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return global.ID, nil
+}
+
+// nodeType returns the type of the object whose id field is being written, if the type implements Node
+func nodeType(ctx context.Context) (string, bool) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Field.Field == nil || fc.Field.Name != "id" || fc.Field.ObjectDefinition == nil {
+		return "", false
+	}
+	for _, name := range fc.Field.ObjectDefinition.Interfaces {
+		if name == NodeInterface {
+			return fc.Object, true
+		}
+	}
+	return "", false
+}
+
+// ArgumentGlobalIDs returns the global IDs passed to an ID or [ID] argument of the resolving field. The ID scalar
+// reduces arguments to their UUID, node and nodes need the type named by the ID as well, so plain UUIDs are rejected.
+func ArgumentGlobalIDs(ctx context.Context, name string) ([]GlobalID, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Field.Field == nil {
+		return nil, fmt.Errorf("argument %s read outside of a field", name)
+	}
+	var raw []interface{}
+	switch v := fc.Field.ArgumentMap(graphql.GetOperationContext(ctx).Variables)[name].(type) {
+	case []interface{}:
+		raw = v
+	case nil:
+	default:
+		raw = []interface{}{v}
+	}
+
+	ids := make([]GlobalID, 0, len(raw))
+	for _, v := range raw {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%T is not an ID", v)
+		}
+		if _, err := uuid.Parse(s); err == nil {
+			return nil, fmt.Errorf("%q is a plain UUID, %s takes node IDs since they name the type", s, fc.Field.Name)
+		}
+		id, err := ParseGlobalID(s)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
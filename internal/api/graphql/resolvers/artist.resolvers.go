@@ -8,12 +8,89 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/blnto/blnto_service/internal/application/service"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/event"
 	"github.com/blnto/blnto_service/internal/domain/models"
 	graphql1 "github.com/blnto/blnto_service/internal/infrastructure/graphql"
 	"github.com/blnto/blnto_service/internal/utils"
 	"github.com/google/uuid"
 )
 
+// SocialMediaLinks is the resolver for the socialMediaLinks field.
+func (r *artistResolver) SocialMediaLinks(ctx context.Context, obj *models.Artist) ([]*models.SocialMedia, error) {
+	if obj.SocialMediaLinks != nil {
+		return obj.SocialMediaLinks, nil
+	}
+
+	links, err := r.loaders.For(ctx).SocialMediaLinksByArtist.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching social media links: %w", err)
+	}
+	return links, nil
+}
+
+// Appearances is the resolver for the appearances field.
+func (r *artistResolver) Appearances(ctx context.Context, obj *models.Artist, upcoming *bool, past *bool, first *int, after *string) (*models.AppearanceConnection, error) {
+	filter := event.AppearanceFilter{
+		Upcoming: upcoming != nil && *upcoming,
+		Past:     past != nil && *past,
+	}
+
+	fetchFunc := func(ctx context.Context, cursor string, limit int) ([]*models.TimetableEntry, string, error) {
+		return r.eventService.FindAppearancesByArtistID(ctx, obj.ID, filter, cursor, limit)
+	}
+
+	entries, nextCursor, limit, err := utils.FetchItemsList[models.TimetableEntry](ctx, first, after, fetchFunc)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching appearances: %w", err)
+	}
+
+	edges := make([]*models.TimeTableEntryEdge, len(entries))
+	for i, entry := range entries {
+		edges[i] = &models.TimeTableEntryEdge{
+			Node:   entry,
+			Cursor: entry.ID.String(),
+		}
+	}
+
+	hasNextPage := len(edges) == limit
+	return &models.AppearanceConnection{
+		Edges: edges,
+		PageInfo: &models.PageInfo{
+			EndCursor:   &nextCursor,
+			HasNextPage: &hasNextPage,
+		},
+	}, nil
+}
+
+// PerformanceStats is the resolver for the performanceStats field.
+func (r *artistResolver) PerformanceStats(ctx context.Context, obj *models.Artist) (*models.ArtistPerformanceStats, error) {
+	stats, err := r.eventService.GetArtistPerformanceStats(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching performance stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Residencies is the resolver for the residencies field.
+func (r *artistResolver) Residencies(ctx context.Context, obj *models.Artist) ([]*models.Residency, error) {
+	residencies, err := r.residencyService.FindByArtistID(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching residencies: %w", err)
+	}
+	return residencies, nil
+}
+
+// Collectives is the resolver for the collectives field.
+func (r *artistResolver) Collectives(ctx context.Context, obj *models.Artist) ([]*models.Collective, error) {
+	collectives, err := r.collectiveService.FindByArtistID(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching collectives: %w", err)
+	}
+	return collectives, nil
+}
+
 // CreateArtist is the resolver for the createArtist field.
 func (r *mutationResolver) CreateArtist(ctx context.Context, input models.CreateArtistInput) (*models.Artist, error) {
 	newArtist := models.Artist{
@@ -25,10 +102,12 @@ func (r *mutationResolver) CreateArtist(ctx context.Context, input models.Create
 
 	if input.SocialMedia != nil {
 		var socialMediaLinks []*models.SocialMedia
-		for _, stageInput := range input.SocialMedia {
+		for _, socialMediaInput := range input.SocialMedia {
 			socialMediaLink := &models.SocialMedia{
-				Platform: stageInput.Platform,
-				Link:     stageInput.Link,
+				Link: socialMediaInput.Link,
+			}
+			if socialMediaInput.Platform != nil {
+				socialMediaLink.Platform = *socialMediaInput.Platform
 			}
 			socialMediaLinks = append(socialMediaLinks, socialMediaLink)
 		}
@@ -47,24 +126,40 @@ func (r *mutationResolver) CreateArtist(ctx context.Context, input models.Create
 func (r *mutationResolver) UpdateArtist(ctx context.Context, input models.UpdateArtistInput) (*models.Artist, error) {
 	newArtist := models.Artist{
 		ID:                    input.ID,
-		Name:                  *input.Name,
 		Location:              input.Location,
 		SoundcloudPromotedSet: input.SoundcloudPromotedSet,
 		SoundcloudPermalink:   input.SoundcloudPermalink,
+		Username:              input.Username,
+		FullName:              input.FullName,
+		FirstName:             input.FirstName,
+		LastName:              input.LastName,
+		AvatarURL:             input.AvatarURL,
+		Description:           input.Description,
+		City:                  input.City,
+		Country:               input.Country,
+	}
+	// The name is optional, the service keeps the stored one when it is omitted
+	if input.Name != nil {
+		newArtist.Name = *input.Name
 	}
 
 	if input.SocialMedia != nil {
-		var socialMediaLinks []*models.SocialMedia
+		// A non-nil (possibly empty) slice tells the service to replace the artist's links
+		socialMediaLinks := make([]*models.SocialMedia, 0, len(input.SocialMedia))
 		for _, socialMediaInput := range input.SocialMedia {
-			// Assuming that models.CreateVenueStageInput and models.Stage have similar fields
+			// Platform and link are optional, the service keeps the stored values when they are omitted
 			socialMediaLink := &models.SocialMedia{
-				ID:       socialMediaInput.ID,
-				Platform: *socialMediaInput.Platform,
-				Link:     *socialMediaInput.Link,
+				ID: socialMediaInput.ID,
+			}
+			if socialMediaInput.Platform != nil {
+				socialMediaLink.Platform = *socialMediaInput.Platform
+			}
+			if socialMediaInput.Link != nil {
+				socialMediaLink.Link = *socialMediaInput.Link
 			}
 			socialMediaLinks = append(socialMediaLinks, socialMediaLink)
-			newArtist.SocialMediaLinks = socialMediaLinks
 		}
+		newArtist.SocialMediaLinks = socialMediaLinks
 	}
 
 	// Call the repository function to create the artist
@@ -87,11 +182,25 @@ func (r *mutationResolver) DeleteArtist(ctx context.Context, input models.Delete
 	return result, nil
 }
 
+// ResetArtistField is the resolver for the resetArtistField field.
+func (r *mutationResolver) ResetArtistField(ctx context.Context, artistID uuid.UUID, field models.ArtistField) (*models.Artist, error) {
+	artistField, ok := service.MapGqlArtistField(field)
+	if !ok {
+		return nil, apperror.Invalid("field", "unknown artist field %s", field)
+	}
+
+	updatedArtist, err := r.artistService.ResetField(ctx, artistID, artistField)
+	if err != nil {
+		return nil, fmt.Errorf("error resetting artist field: %w", err)
+	}
+	return updatedArtist, nil
+}
+
 // GetArtist is the resolver for the getArtist field.
 func (r *queryResolver) GetArtist(ctx context.Context, id uuid.UUID) (*models.Artist, error) {
 	artist, err := r.artistService.FindByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artist: %v", err)
+		return nil, fmt.Errorf("error fetching artist: %w", err)
 	}
 	return artist, err
 }
@@ -107,7 +216,7 @@ func (r *queryResolver) SearchArtists(ctx context.Context, criteria models.Artis
 	// Assuming you have an artist repository instance (artistRepo)
 	artists, nextCursor, err := r.artistService.Search(ctx, &criteria)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artists: %v", err)
+		return nil, fmt.Errorf("error fetching artists: %w", err)
 	}
 
 	// Map artists to GraphQL edges
@@ -137,9 +246,9 @@ func (r *queryResolver) SearchArtists(ctx context.Context, criteria models.Artis
 
 // GetFeaturedArtists is the resolver for the getFeaturedArtists field.
 func (r *queryResolver) GetFeaturedArtists(ctx context.Context) ([]*models.Artist, error) {
-	artist, err := r.artistService.FindFeatured(ctx)
+	artist, err := r.popularityService.FindFeatured(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching featured artists: %v", err)
+		return nil, fmt.Errorf("error fetching featured artists: %w", err)
 	}
 
 	return artist, nil
@@ -149,7 +258,7 @@ func (r *queryResolver) GetFeaturedArtists(ctx context.Context) ([]*models.Artis
 func (r *queryResolver) GetArtistByName(ctx context.Context, name string) (*models.Artist, error) {
 	artist, err := r.artistService.FindByName(ctx, name)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artist: %v", err)
+		return nil, fmt.Errorf("error fetching artist: %w", err)
 	}
 
 	return artist, nil
@@ -164,7 +273,7 @@ func (r *queryResolver) ListArtists(ctx context.Context, first *int, after *stri
 	artists, nextCursor, limit, err := utils.FetchItemsList[models.Artist](ctx, first, after, r.artistService.FindAllByCursor)
 
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artists: %v", err)
+		return nil, fmt.Errorf("error fetching artists: %w", err)
 	}
 	// Map items to GraphQL edges
 	edges := make([]*models.ArtistEdge, len(artists))
@@ -190,11 +299,58 @@ func (r *queryResolver) ListArtists(ctx context.Context, first *int, after *stri
 	return artistConnection, nil
 }
 
+// BrokenSocialMediaLinks is the resolver for the brokenSocialMediaLinks field.
+func (r *queryResolver) BrokenSocialMediaLinks(ctx context.Context, first *int, after *string) (*models.BrokenSocialMediaLinkConnection, error) {
+	links, nextCursor, limit, err := utils.FetchItemsList[models.SocialMedia](ctx, first, after, r.linkHealthService.FindBrokenByCursor)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching broken links: %w", err)
+	}
+
+	edges := make([]*models.BrokenSocialMediaLinkEdge, len(links))
+	for i, link := range links {
+		edges[i] = &models.BrokenSocialMediaLinkEdge{
+			Node:   link,
+			Cursor: link.ID.String(),
+		}
+	}
+
+	hasNextPage := len(edges) == limit
+	return &models.BrokenSocialMediaLinkConnection{
+		Edges: edges,
+		PageInfo: &models.PageInfo{
+			EndCursor:   &nextCursor,
+			HasNextPage: &hasNextPage,
+		},
+	}, nil
+}
+
+// Health is the resolver for the health field.
+func (r *socialMediaResolver) Health(ctx context.Context, obj *models.SocialMedia) (*models.SocialMediaLinkHealth, error) {
+	// Already loaded when the link was fetched through brokenSocialMediaLinks
+	if obj.Health != nil {
+		return obj.Health, nil
+	}
+
+	health, err := r.linkHealthService.FindByLinkID(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching link health: %w", err)
+	}
+	return health, nil
+}
+
+// Artist returns graphql1.ArtistResolver implementation.
+func (r *Resolver) Artist() graphql1.ArtistResolver { return &artistResolver{r} }
+
 // Mutation returns graphql1.MutationResolver implementation.
 func (r *Resolver) Mutation() graphql1.MutationResolver { return &mutationResolver{r} }
 
 // Query returns graphql1.QueryResolver implementation.
 func (r *Resolver) Query() graphql1.QueryResolver { return &queryResolver{r} }
 
+// SocialMedia returns graphql1.SocialMediaResolver implementation.
+func (r *Resolver) SocialMedia() graphql1.SocialMediaResolver { return &socialMediaResolver{r} }
+
+type artistResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type socialMediaResolver struct{ *Resolver }
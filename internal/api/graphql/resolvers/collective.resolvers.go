@@ -0,0 +1,166 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.40
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blnto/blnto_service/internal/domain/models"
+	graphql1 "github.com/blnto/blnto_service/internal/infrastructure/graphql"
+	"github.com/blnto/blnto_service/internal/utils"
+	"github.com/google/uuid"
+)
+
+// Members is the resolver for the members field.
+func (r *collectiveResolver) Members(ctx context.Context, obj *models.Collective) ([]*models.CollectiveMember, error) {
+	members, err := r.collectiveService.FindMembers(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching collective members: %w", err)
+	}
+	return members, nil
+}
+
+// HostedEvents is the resolver for the hostedEvents field.
+func (r *collectiveResolver) HostedEvents(ctx context.Context, obj *models.Collective, upcoming *bool) ([]*models.Event, error) {
+	events, err := r.collectiveService.FindHostedEvents(ctx, obj.ID, upcoming != nil && *upcoming)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching hosted events: %w", err)
+	}
+	return events, nil
+}
+
+// Takeovers is the resolver for the takeovers field.
+func (r *collectiveResolver) Takeovers(ctx context.Context, obj *models.Collective, upcoming *bool) ([]*models.StageTakeover, error) {
+	takeovers, err := r.collectiveService.FindTakeovers(ctx, obj.ID, upcoming != nil && *upcoming)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching stage takeovers: %w", err)
+	}
+	return takeovers, nil
+}
+
+// CreateCollective is the resolver for the createCollective field.
+func (r *mutationResolver) CreateCollective(ctx context.Context, input models.CreateCollectiveInput) (*models.Collective, error) {
+	createdCollective, err := r.collectiveService.Save(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return createdCollective, nil
+}
+
+// UpdateCollective is the resolver for the updateCollective field.
+func (r *mutationResolver) UpdateCollective(ctx context.Context, input models.UpdateCollectiveInput) (*models.Collective, error) {
+	updatedCollective, err := r.collectiveService.Update(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return updatedCollective, nil
+}
+
+// DeleteCollective is the resolver for the deleteCollective field.
+func (r *mutationResolver) DeleteCollective(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.collectiveService.Delete(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return result, nil
+}
+
+// AddCollectiveMember is the resolver for the addCollectiveMember field.
+func (r *mutationResolver) AddCollectiveMember(ctx context.Context, input models.AddCollectiveMemberInput) (*models.CollectiveMember, error) {
+	member, err := r.collectiveService.AddMember(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveCollectiveMember is the resolver for the removeCollectiveMember field.
+func (r *mutationResolver) RemoveCollectiveMember(ctx context.Context, collectiveID uuid.UUID, artistID uuid.UUID) (bool, error) {
+	result, err := r.collectiveService.RemoveMember(ctx, collectiveID, artistID)
+	if err != nil {
+		return false, err
+	}
+	return result, nil
+}
+
+// SetEventHost is the resolver for the setEventHost field.
+func (r *mutationResolver) SetEventHost(ctx context.Context, input models.SetEventHostInput) (*models.Event, error) {
+	updatedEvent, err := r.collectiveService.SetEventHost(ctx, input.EventID, input.CollectiveID)
+	if err != nil {
+		return nil, err
+	}
+	return updatedEvent, nil
+}
+
+// CreateStageTakeover is the resolver for the createStageTakeover field.
+func (r *mutationResolver) CreateStageTakeover(ctx context.Context, input models.CreateStageTakeoverInput) (*models.StageTakeover, error) {
+	takeover, err := r.collectiveService.CreateTakeover(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return takeover, nil
+}
+
+// DeleteStageTakeover is the resolver for the deleteStageTakeover field.
+func (r *mutationResolver) DeleteStageTakeover(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.collectiveService.DeleteTakeover(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return result, nil
+}
+
+// GetCollective is the resolver for the getCollective field.
+func (r *queryResolver) GetCollective(ctx context.Context, id uuid.UUID) (*models.Collective, error) {
+	collective, err := r.collectiveService.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching collective: %w", err)
+	}
+	return collective, nil
+}
+
+// ListCollectives is the resolver for the listCollectives field.
+func (r *queryResolver) ListCollectives(ctx context.Context, first *int, after *string) (*models.CollectiveConnection, error) {
+	collectives, nextCursor, limit, err := utils.FetchItemsList[models.Collective](ctx, first, after, r.collectiveService.FindAllByCursor)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching collectives: %w", err)
+	}
+
+	edges := make([]*models.CollectiveEdge, len(collectives))
+	for i, item := range collectives {
+		edges[i] = &models.CollectiveEdge{
+			Node:   item,
+			Cursor: item.ID.String(),
+		}
+	}
+
+	hasNextPage := len(edges) == limit
+	return &models.CollectiveConnection{
+		Edges: edges,
+		PageInfo: &models.PageInfo{
+			EndCursor:   &nextCursor,
+			HasNextPage: &hasNextPage,
+		},
+	}, nil
+}
+
+// Collective is the resolver for the collective field.
+func (r *stageTakeoverResolver) Collective(ctx context.Context, obj *models.StageTakeover) (*models.Collective, error) {
+	collective, err := r.collectiveService.FindByID(ctx, obj.CollectiveID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching collective: %w", err)
+	}
+	return collective, nil
+}
+
+// Collective returns graphql1.CollectiveResolver implementation.
+func (r *Resolver) Collective() graphql1.CollectiveResolver { return &collectiveResolver{r} }
+
+// StageTakeover returns graphql1.StageTakeoverResolver implementation.
+func (r *Resolver) StageTakeover() graphql1.StageTakeoverResolver { return &stageTakeoverResolver{r} }
+
+type collectiveResolver struct{ *Resolver }
+type stageTakeoverResolver struct{ *Resolver }
@@ -8,26 +8,70 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/blnto/blnto_service/internal/domain/apperror"
 	"github.com/blnto/blnto_service/internal/domain/models"
+	graphql1 "github.com/blnto/blnto_service/internal/infrastructure/graphql"
 	"github.com/blnto/blnto_service/internal/utils"
 	"github.com/google/uuid"
 )
 
+// Venue is the resolver for the venue field.
+func (r *eventResolver) Venue(ctx context.Context, obj *models.Event) (*models.Venue, error) {
+	if obj.Venue != nil {
+		return obj.Venue, nil
+	}
+
+	venue, err := r.loaders.For(ctx).Venue.Load(ctx, obj.VenueID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching event venue: %w", err)
+	}
+	if venue == nil {
+		return nil, fmt.Errorf("error fetching event venue: %w", apperror.NotFound("venue", obj.VenueID))
+	}
+	return venue, nil
+}
+
+// Host is the resolver for the host field.
+func (r *eventResolver) Host(ctx context.Context, obj *models.Event) (*models.Collective, error) {
+	if obj.HostCollectiveID == nil {
+		return nil, nil
+	}
+
+	host, err := r.collectiveService.FindByID(ctx, *obj.HostCollectiveID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching event host: %w", err)
+	}
+	return host, nil
+}
+
+// Timetable is the resolver for the timetable field.
+func (r *eventResolver) Timetable(ctx context.Context, obj *models.Event) ([]*models.TimetableEntry, error) {
+	if obj.Timetable != nil {
+		return obj.Timetable, nil
+	}
+
+	timetable, err := r.loaders.For(ctx).TimetableByEvent.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching event timetable: %w", err)
+	}
+	return timetable, nil
+}
+
 // CreateEvent is the resolver for the createEvent field.
 func (r *mutationResolver) CreateEvent(ctx context.Context, input models.CreateEventInput) (*models.Event, error) {
-	panic(fmt.Errorf("not implemented: CreateEvent - createEvent"))
+	return nil, apperror.NotImplemented("createEvent")
 }
 
 // DeleteEvent is the resolver for the deleteEvent field.
 func (r *mutationResolver) DeleteEvent(ctx context.Context, input models.DeleteEventInput) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteEvent - deleteEvent"))
+	return false, apperror.NotImplemented("deleteEvent")
 }
 
 // ListEvents is the resolver for the listEvents field.
 func (r *queryResolver) ListEvents(ctx context.Context, first *int, after *string, last *int, before *string) (*models.EventConnection, error) {
 	events, nextCursor, limit, err := utils.FetchItemsList[models.Event](ctx, first, after, r.eventService.FindAllUpcoming)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artists: %v", err)
+		return nil, fmt.Errorf("error fetching events: %w", err)
 	}
 
 	cursorFunc := func(event *models.Event) string {
@@ -41,7 +85,14 @@ func (r *queryResolver) ListEvents(ctx context.Context, first *int, after *strin
 
 // GetEvent is the resolver for the getEvent field.
 func (r *queryResolver) GetEvent(ctx context.Context, id uuid.UUID) (*models.Event, error) {
-	panic(fmt.Errorf("not implemented: GetEvent - getEvent"))
+	event, err := r.loaders.For(ctx).Event.Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching event: %w", err)
+	}
+	if event == nil {
+		return nil, apperror.NotFound("event", id)
+	}
+	return event, nil
 }
 
 // GetUpcomingEventsByVenue is the resolver for the getUpcomingEventsByVenue field.
@@ -50,7 +101,7 @@ func (r *queryResolver) GetUpcomingEventsByVenue(ctx context.Context, venueID uu
 	events, err := r.eventService.FindUpcomingByVenueID(ctx, venueID)
 
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artists: %v", err)
+		return nil, fmt.Errorf("error fetching events: %w", err)
 	}
 
 	cursorFunc := func(event *models.Event) string {
@@ -68,7 +119,7 @@ func (r *queryResolver) GetPastEventsByVenue(ctx context.Context, venueID uuid.U
 	events, err := r.eventService.FindPastEventsByVenueID(ctx, venueID)
 
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artists: %v", err)
+		return nil, fmt.Errorf("error fetching events: %w", err)
 	}
 
 	cursorFunc := func(event *models.Event) string {
@@ -90,7 +141,7 @@ func (r *queryResolver) GetAllUpcomingEvents(ctx context.Context) (*models.Event
 	events, nextCursor, limit, err := utils.FetchItemsList[models.Event](ctx, &limit, nil, r.eventService.FindAllUpcoming)
 
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artists: %v", err)
+		return nil, fmt.Errorf("error fetching events: %w", err)
 	}
 
 	eventConnection := utils.BuildEventConnection(events, limit, nextCursor, cursorFunc)
@@ -104,7 +155,7 @@ func (r *queryResolver) GetTodayEvents(ctx context.Context) (*models.EventConnec
 	events, err := r.eventService.FindToday(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artists: %v", err)
+		return nil, fmt.Errorf("error fetching events: %w", err)
 	}
 
 	cursorFunc := func(event *models.Event) string {
@@ -122,7 +173,7 @@ func (r *queryResolver) GetTommorowEvents(ctx context.Context) (*models.EventCon
 	events, err := r.eventService.FindTomorrow(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artists: %v", err)
+		return nil, fmt.Errorf("error fetching events: %w", err)
 	}
 
 	cursorFunc := func(event *models.Event) string {
@@ -140,7 +191,7 @@ func (r *queryResolver) GetCurrentEvents(ctx context.Context) (*models.EventConn
 	events, err := r.eventService.FindCurrent(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artists: %v", err)
+		return nil, fmt.Errorf("error fetching events: %w", err)
 	}
 
 	cursorFunc := func(event *models.Event) string {
@@ -158,7 +209,7 @@ func (r *queryResolver) GetEventsByVenue(ctx context.Context, venueID uuid.UUID)
 	events, err := r.eventService.FindUpcomingByVenueID(ctx, venueID)
 
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artists: %v", err)
+		return nil, fmt.Errorf("error fetching events: %w", err)
 	}
 
 	cursorFunc := func(event *models.Event) string {
@@ -169,3 +220,8 @@ func (r *queryResolver) GetEventsByVenue(ctx context.Context, venueID uuid.UUID)
 
 	return eventConnection, nil
 }
+
+// Event returns graphql1.EventResolver implementation.
+func (r *Resolver) Event() graphql1.EventResolver { return &eventResolver{r} }
+
+type eventResolver struct{ *Resolver }
@@ -0,0 +1,40 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.40
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blnto/blnto_service/internal/api/graphql/relay"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// Node is the resolver for the node field.
+func (r *queryResolver) Node(ctx context.Context, id uuid.UUID) (models.Node, error) {
+	ids, err := relay.ArgumentGlobalIDs(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := r.resolveNodes(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching node: %w", err)
+	}
+	return nodes[0], nil
+}
+
+// Nodes is the resolver for the nodes field.
+func (r *queryResolver) Nodes(ctx context.Context, ids []uuid.UUID) ([]models.Node, error) {
+	globalIDs, err := relay.ArgumentGlobalIDs(ctx, "ids")
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := r.resolveNodes(ctx, globalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching nodes: %w", err)
+	}
+	return nodes, nil
+}
@@ -0,0 +1,50 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.40
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// PinFeaturedArtist is the resolver for the pinFeaturedArtist field.
+func (r *mutationResolver) PinFeaturedArtist(ctx context.Context, artistID uuid.UUID, position *int, expiresAt *time.Time) (*models.FeaturedArtistPin, error) {
+	pin, err := r.popularityService.Pin(ctx, artistID, position, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("error pinning featured artist: %w", err)
+	}
+	return pin, nil
+}
+
+// UnpinFeaturedArtist is the resolver for the unpinFeaturedArtist field.
+func (r *mutationResolver) UnpinFeaturedArtist(ctx context.Context, artistID uuid.UUID) (bool, error) {
+	unpinned, err := r.popularityService.Unpin(ctx, artistID)
+	if err != nil {
+		return false, fmt.Errorf("error unpinning featured artist: %w", err)
+	}
+	return unpinned, nil
+}
+
+// TrendingArtists is the resolver for the trendingArtists field.
+func (r *queryResolver) TrendingArtists(ctx context.Context, window models.TrendingWindow, first *int) ([]*models.TrendingArtist, error) {
+	trending, err := r.popularityService.Trending(ctx, window, first)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching trending artists: %w", err)
+	}
+	return trending, nil
+}
+
+// FeaturedArtistPins is the resolver for the featuredArtistPins field.
+func (r *queryResolver) FeaturedArtistPins(ctx context.Context) ([]*models.FeaturedArtistPin, error) {
+	pins, err := r.popularityService.Pins(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching featured pins: %w", err)
+	}
+	return pins, nil
+}
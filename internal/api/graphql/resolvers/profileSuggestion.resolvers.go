@@ -0,0 +1,88 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.40
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blnto/blnto_service/internal/application/service"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	graphql1 "github.com/blnto/blnto_service/internal/infrastructure/graphql"
+	"github.com/blnto/blnto_service/internal/utils"
+	"github.com/google/uuid"
+)
+
+// AcceptProfileSuggestion is the resolver for the acceptProfileSuggestion field.
+func (r *mutationResolver) AcceptProfileSuggestion(ctx context.Context, id uuid.UUID) (*models.ProfileSuggestion, error) {
+	suggestion, err := r.profileSuggestionService.Accept(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error accepting profile suggestion: %w", err)
+	}
+	return suggestion, nil
+}
+
+// RejectProfileSuggestion is the resolver for the rejectProfileSuggestion field.
+func (r *mutationResolver) RejectProfileSuggestion(ctx context.Context, id uuid.UUID) (*models.ProfileSuggestion, error) {
+	suggestion, err := r.profileSuggestionService.Reject(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error rejecting profile suggestion: %w", err)
+	}
+	return suggestion, nil
+}
+
+// Artist is the resolver for the artist field.
+func (r *profileSuggestionResolver) Artist(ctx context.Context, obj *models.ProfileSuggestion) (*models.Artist, error) {
+	artist, err := r.artistService.FindByID(ctx, obj.ArtistID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching artist: %w", err)
+	}
+	return artist, nil
+}
+
+// ProfileSuggestions is the resolver for the profileSuggestions field.
+func (r *queryResolver) ProfileSuggestions(ctx context.Context, status *models.ProfileSuggestionStatus, first *int, after *string) (*models.ProfileSuggestionConnection, error) {
+	var suggestionStatus artist.SuggestionStatus
+	if status != nil {
+		var ok bool
+		if suggestionStatus, ok = service.MapGqlSuggestionStatus(*status); !ok {
+			return nil, apperror.Invalid("status", "unknown suggestion status %s", *status)
+		}
+	}
+
+	fetch := func(ctx context.Context, cursor string, limit int) ([]*models.ProfileSuggestion, string, error) {
+		return r.profileSuggestionService.FindByStatusAndCursor(ctx, suggestionStatus, cursor, limit)
+	}
+	suggestions, nextCursor, limit, err := utils.FetchItemsList[models.ProfileSuggestion](ctx, first, after, fetch)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching profile suggestions: %w", err)
+	}
+
+	edges := make([]*models.ProfileSuggestionEdge, len(suggestions))
+	for i, suggestion := range suggestions {
+		edges[i] = &models.ProfileSuggestionEdge{
+			Node:   suggestion,
+			Cursor: suggestion.ID.String(),
+		}
+	}
+
+	hasNextPage := len(edges) == limit
+	return &models.ProfileSuggestionConnection{
+		Edges: edges,
+		PageInfo: &models.PageInfo{
+			EndCursor:   &nextCursor,
+			HasNextPage: &hasNextPage,
+		},
+	}, nil
+}
+
+// ProfileSuggestion returns graphql1.ProfileSuggestionResolver implementation.
+func (r *Resolver) ProfileSuggestion() graphql1.ProfileSuggestionResolver {
+	return &profileSuggestionResolver{r}
+}
+
+type profileSuggestionResolver struct{ *Resolver }
@@ -0,0 +1,21 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.40
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blnto/blnto_service/internal/domain/models"
+)
+
+// PromotedSet is the resolver for the promotedSet field.
+func (r *artistResolver) PromotedSet(ctx context.Context, obj *models.Artist) (*models.PromotedSet, error) {
+	promotedSet, err := r.loaders.For(ctx).PromotedSetByArtist.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching promoted set: %w", err)
+	}
+	return promotedSet, nil
+}
@@ -0,0 +1,88 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.40
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	graphql1 "github.com/blnto/blnto_service/internal/infrastructure/graphql"
+	"github.com/google/uuid"
+)
+
+// CreateResidency is the resolver for the createResidency field.
+func (r *mutationResolver) CreateResidency(ctx context.Context, input models.CreateResidencyInput) (*models.Residency, error) {
+	residency, err := r.residencyService.Create(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return residency, nil
+}
+
+// EndResidency is the resolver for the endResidency field.
+func (r *mutationResolver) EndResidency(ctx context.Context, input models.EndResidencyInput) (*models.Residency, error) {
+	residency, err := r.residencyService.End(ctx, input.ID, input.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	return residency, nil
+}
+
+// DeleteResidency is the resolver for the deleteResidency field.
+func (r *mutationResolver) DeleteResidency(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.residencyService.Delete(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return result, nil
+}
+
+// ResidenciesByVenue is the resolver for the residenciesByVenue field.
+func (r *queryResolver) ResidenciesByVenue(ctx context.Context, venueID uuid.UUID, includePast *bool) ([]*models.Residency, error) {
+	residencies, err := r.residencyService.FindByVenueID(ctx, venueID, includePast != nil && *includePast)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching residencies: %w", err)
+	}
+	return residencies, nil
+}
+
+// Debuts is the resolver for the debuts field.
+func (r *queryResolver) Debuts(ctx context.Context, from time.Time, to time.Time, venueID *uuid.UUID) ([]*models.TimetableEntry, error) {
+	if !from.Before(to) {
+		return nil, apperror.Invalid("to", "from must be before to")
+	}
+
+	debuts, err := r.eventService.FindDebuts(ctx, from, to, venueID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching debuts: %w", err)
+	}
+	return debuts, nil
+}
+
+// Artist is the resolver for the artist field.
+func (r *residencyResolver) Artist(ctx context.Context, obj *models.Residency) (*models.Artist, error) {
+	artist, err := r.artistService.FindByID(ctx, obj.ArtistID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching artist: %w", err)
+	}
+	return artist, nil
+}
+
+// Venue is the resolver for the venue field.
+func (r *residencyResolver) Venue(ctx context.Context, obj *models.Residency) (*models.Venue, error) {
+	venue, err := r.venueService.FindByID(ctx, obj.VenueID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching venue: %w", err)
+	}
+	return venue, nil
+}
+
+// Residency returns graphql1.ResidencyResolver implementation.
+func (r *Resolver) Residency() graphql1.ResidencyResolver { return &residencyResolver{r} }
+
+type residencyResolver struct{ *Resolver }
@@ -1,7 +1,13 @@
 package resolvers
 
 import (
+	"context"
+
+	"github.com/blnto/blnto_service/internal/api/graphql/loaders"
+	"github.com/blnto/blnto_service/internal/api/graphql/relay"
 	"github.com/blnto/blnto_service/internal/application/service"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/google/uuid"
 )
 
 // This file will not be regenerated automatically.
@@ -9,12 +15,82 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	artistService *service.ArtistService
-	eventService  *service.EventService
-	stageService  *service.StageService
-	venueService  *service.VenueService
+	artistService            *service.ArtistService
+	eventService             *service.EventService
+	stageService             *service.StageService
+	venueService             *service.VenueService
+	residencyService         *service.ResidencyService
+	collectiveService        *service.CollectiveService
+	linkHealthService        *service.LinkHealthService
+	trackService             *service.TrackService
+	profileSuggestionService *service.ProfileSuggestionService
+	promotedSetService       *service.PromotedSetService
+	popularityService        *service.PopularityService
+	soundCloudAccountService *service.SoundCloudAccountService
+	loaders                  *loaders.Factory
+}
+
+func NewResolver(artistService *service.ArtistService, eventService *service.EventService, stageService *service.StageService, venueService *service.VenueService, residencyService *service.ResidencyService, collectiveService *service.CollectiveService, linkHealthService *service.LinkHealthService, trackService *service.TrackService, profileSuggestionService *service.ProfileSuggestionService, promotedSetService *service.PromotedSetService, popularityService *service.PopularityService, soundCloudAccountService *service.SoundCloudAccountService, loaders *loaders.Factory) *Resolver {
+	return &Resolver{artistService: artistService, eventService: eventService, stageService: stageService, venueService: venueService, residencyService: residencyService, collectiveService: collectiveService, linkHealthService: linkHealthService, trackService: trackService, profileSuggestionService: profileSuggestionService, promotedSetService: promotedSetService, popularityService: popularityService, soundCloudAccountService: soundCloudAccountService, loaders: loaders}
+}
+
+// resolveNodes fetches the objects of global IDs through the loaders, one batch per type. IDs of unknown types or
+// objects resolve to nil, as the node field expects.
+func (r *Resolver) resolveNodes(ctx context.Context, ids []relay.GlobalID) ([]models.Node, error) {
+	indexesByType := make(map[string][]int)
+	for i, id := range ids {
+		indexesByType[id.Type] = append(indexesByType[id.Type], i)
+	}
+
+	l := r.loaders.For(ctx)
+	nodes := make([]models.Node, len(ids))
+	for typeName, indexes := range indexesByType {
+		keys := make([]uuid.UUID, len(indexes))
+		for i, index := range indexes {
+			keys[i] = ids[index].ID
+		}
+
+		var found []models.Node
+		var err error
+		switch typeName {
+		case "Artist":
+			found, err = loadNodes(ctx, l.Artist, keys)
+		case "Event":
+			found, err = loadNodes(ctx, l.Event, keys)
+		case "Venue":
+			found, err = loadNodes(ctx, l.Venue, keys)
+		case "Stage":
+			found, err = loadNodes(ctx, l.Stage, keys)
+		case "TimetableEntry":
+			found, err = loadNodes(ctx, l.TimetableEntry, keys)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i, index := range indexes {
+			nodes[index] = found[i]
+		}
+	}
+	return nodes, nil
 }
 
-func NewResolver(artistService *service.ArtistService, eventService *service.EventService, stageService *service.StageService, venueService *service.VenueService) *Resolver {
-	return &Resolver{artistService: artistService, eventService: eventService, stageService: stageService, venueService: venueService}
+// loadNodes loads the keys as nodes, keeping missing objects an untyped nil
+func loadNodes[V interface {
+	comparable
+	models.Node
+}](ctx context.Context, loader *loaders.Loader[uuid.UUID, V], keys []uuid.UUID) ([]models.Node, error) {
+	values, err := loader.LoadAll(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	var missing V
+	nodes := make([]models.Node, len(values))
+	for i, v := range values {
+		if v != missing {
+			nodes[i] = v
+		}
+	}
+	return nodes, nil
 }
@@ -0,0 +1,31 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.40
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// SoundcloudAccount is the resolver for the soundcloudAccount field.
+func (r *artistResolver) SoundcloudAccount(ctx context.Context, obj *models.Artist) (*models.SoundCloudAccount, error) {
+	account, err := r.soundCloudAccountService.FindByArtistID(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching soundcloud account: %w", err)
+	}
+	return account, nil
+}
+
+// DisconnectSoundCloudAccount is the resolver for the disconnectSoundCloudAccount field.
+func (r *mutationResolver) DisconnectSoundCloudAccount(ctx context.Context, artistID uuid.UUID) (bool, error) {
+	disconnected, err := r.soundCloudAccountService.Disconnect(ctx, artistID)
+	if err != nil {
+		return false, fmt.Errorf("error disconnecting soundcloud account: %w", err)
+	}
+	return disconnected, nil
+}
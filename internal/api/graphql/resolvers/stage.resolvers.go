@@ -6,18 +6,18 @@ package resolvers
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/blnto/blnto_service/internal/domain/apperror"
 	"github.com/blnto/blnto_service/internal/domain/models"
 	"github.com/google/uuid"
 )
 
 // CreateStage is the resolver for the createStage field.
 func (r *mutationResolver) CreateStage(ctx context.Context, input models.CreateStageInput) (*models.Stage, error) {
-	panic(fmt.Errorf("not implemented: CreateStage - createStage"))
+	return nil, apperror.NotImplemented("createStage")
 }
 
 // StagesByVenue is the resolver for the stagesByVenue field.
 func (r *queryResolver) StagesByVenue(ctx context.Context, venueID uuid.UUID) ([]*models.Stage, error) {
-	panic(fmt.Errorf("not implemented: StagesByVenue - stagesByVenue"))
+	return nil, apperror.NotImplemented("stagesByVenue")
 }
@@ -7,27 +7,93 @@ package resolvers
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/blnto/blnto_service/internal/domain/apperror"
 	"github.com/blnto/blnto_service/internal/domain/models"
+	graphql1 "github.com/blnto/blnto_service/internal/infrastructure/graphql"
 	"github.com/google/uuid"
 )
 
 // CreateTimetableEntry is the resolver for the createTimetableEntry field.
 func (r *mutationResolver) CreateTimetableEntry(ctx context.Context, input models.CreateTimetableEntryInput) (*models.TimetableEntry, error) {
-	panic(fmt.Errorf("not implemented: CreateTimetableEntry - createTimetableEntry"))
+	return nil, apperror.NotImplemented("createTimetableEntry")
 }
 
 // DeleteTimeTableEntry is the resolver for the deleteTimeTableEntry field.
 func (r *mutationResolver) DeleteTimeTableEntry(ctx context.Context, input models.DeleteTimetableEntryInput) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteTimeTableEntry - deleteTimeTableEntry"))
+	return false, apperror.NotImplemented("deleteTimeTableEntry")
 }
 
 // GetTimetableEntriesByEventID is the resolver for the getTimetableEntriesByEventID field.
 func (r *queryResolver) GetTimetableEntriesByEventID(ctx context.Context, eventID uuid.UUID) (*models.TimetableEntryConnection, error) {
-	panic(fmt.Errorf("not implemented: GetTimetableEntriesByEventID - getTimetableEntriesByEventID"))
+	return nil, apperror.NotImplemented("getTimetableEntriesByEventID")
 }
 
 // TimetableByEventID is the resolver for the timetableByEventID field.
 func (r *queryResolver) TimetableByEventID(ctx context.Context, eventID uuid.UUID) ([]*models.TimetableEntry, error) {
-	panic(fmt.Errorf("not implemented: TimetableByEventID - timetableByEventID"))
+	return nil, apperror.NotImplemented("timetableByEventID")
 }
+
+// Stage is the resolver for the stage field.
+func (r *timetableEntryResolver) Stage(ctx context.Context, obj *models.TimetableEntry) (*models.Stage, error) {
+	if obj.Stage != nil {
+		return obj.Stage, nil
+	}
+
+	stage, err := r.loaders.For(ctx).Stage.Load(ctx, obj.StageID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching timetable stage: %w", err)
+	}
+	return stage, nil
+}
+
+// Artist is the resolver for the artist field.
+func (r *timetableEntryResolver) Artist(ctx context.Context, obj *models.TimetableEntry) (*models.Artist, error) {
+	if obj.Artist != nil {
+		return obj.Artist, nil
+	}
+
+	artist, err := r.loaders.For(ctx).Artist.Load(ctx, obj.ArtistID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching timetable artist: %w", err)
+	}
+	return artist, nil
+}
+
+// IsResident is the resolver for the isResident field.
+func (r *timetableEntryResolver) IsResident(ctx context.Context, obj *models.TimetableEntry) (bool, error) {
+	residency, err := r.loaders.For(ctx).ResidencyByEntry.Load(ctx, obj.ID)
+	if err != nil {
+		return false, fmt.Errorf("error fetching residency: %w", err)
+	}
+	return residency != nil, nil
+}
+
+// ResidentSince is the resolver for the residentSince field.
+func (r *timetableEntryResolver) ResidentSince(ctx context.Context, obj *models.TimetableEntry) (*time.Time, error) {
+	residency, err := r.loaders.For(ctx).ResidencyByEntry.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching residency: %w", err)
+	}
+	if residency == nil {
+		return nil, nil
+	}
+	return &residency.StartDate, nil
+}
+
+// IsDebut is the resolver for the isDebut field.
+func (r *timetableEntryResolver) IsDebut(ctx context.Context, obj *models.TimetableEntry) (bool, error) {
+	isDebut, err := r.eventService.IsDebut(ctx, obj.ID)
+	if err != nil {
+		return false, fmt.Errorf("error checking debut: %w", err)
+	}
+	return isDebut, nil
+}
+
+// TimetableEntry returns graphql1.TimetableEntryResolver implementation.
+func (r *Resolver) TimetableEntry() graphql1.TimetableEntryResolver {
+	return &timetableEntryResolver{r}
+}
+
+type timetableEntryResolver struct{ *Resolver }
@@ -0,0 +1,42 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.40
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/utils"
+)
+
+// Tracks is the resolver for the tracks field.
+func (r *artistResolver) Tracks(ctx context.Context, obj *models.Artist, first *int, after *string) (*models.TrackConnection, error) {
+	fetchFunc := func(ctx context.Context, cursor string, limit int) ([]*models.Track, string, error) {
+		return r.trackService.FindByArtistID(ctx, obj.ID, cursor, limit)
+	}
+
+	tracks, nextCursor, limit, err := utils.FetchItemsList[models.Track](ctx, first, after, fetchFunc)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching tracks: %w", err)
+	}
+
+	edges := make([]*models.TrackEdge, len(tracks))
+	for i, track := range tracks {
+		edges[i] = &models.TrackEdge{
+			Node:   track,
+			Cursor: track.ID.String(),
+		}
+	}
+
+	hasNextPage := len(edges) == limit
+	return &models.TrackConnection{
+		Edges: edges,
+		PageInfo: &models.PageInfo{
+			EndCursor:   &nextCursor,
+			HasNextPage: &hasNextPage,
+		},
+	}, nil
+}
@@ -8,7 +8,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/blnto/blnto_service/internal/domain/apperror"
 	"github.com/blnto/blnto_service/internal/domain/models"
+	graphql1 "github.com/blnto/blnto_service/internal/infrastructure/graphql"
 	"github.com/google/uuid"
 )
 
@@ -42,12 +44,12 @@ func (r *mutationResolver) CreateVenue(ctx context.Context, input models.CreateV
 
 // UpdateVenue is the resolver for the updateVenue field.
 func (r *mutationResolver) UpdateVenue(ctx context.Context, id uuid.UUID, input models.CreateVenueInput) (*models.Venue, error) {
-	panic(fmt.Errorf("not implemented: UpdateVenue - updateVenue"))
+	return nil, apperror.NotImplemented("updateVenue")
 }
 
 // DeleteVenue is the resolver for the deleteVenue field.
 func (r *mutationResolver) DeleteVenue(ctx context.Context, id uuid.UUID) (*models.Venue, error) {
-	panic(fmt.Errorf("not implemented: DeleteVenue - deleteVenue"))
+	return nil, apperror.NotImplemented("deleteVenue")
 }
 
 // ListVenues is the resolver for the listVenues field.
@@ -68,7 +70,7 @@ func (r *queryResolver) ListVenues(ctx context.Context, first *int, after *strin
 	// Assuming you have an artist repository instance (artistRepo)
 	venues, nextCursor, err := r.venueService.FindAllByCursor(ctx, cursor, limit)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching artists: %v", err)
+		return nil, fmt.Errorf("error fetching venues: %w", err)
 	}
 
 	// Map artists to GraphQL edges
@@ -98,5 +100,30 @@ func (r *queryResolver) ListVenues(ctx context.Context, first *int, after *strin
 
 // GetVenue is the resolver for the getVenue field.
 func (r *queryResolver) GetVenue(ctx context.Context, id uuid.UUID) (*models.Venue, error) {
-	panic(fmt.Errorf("not implemented: GetVenue - getVenue"))
+	venue, err := r.loaders.For(ctx).Venue.Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching venue: %w", err)
+	}
+	if venue == nil {
+		return nil, apperror.NotFound("venue", id)
+	}
+	return venue, nil
 }
+
+// Stages is the resolver for the stages field.
+func (r *venueResolver) Stages(ctx context.Context, obj *models.Venue) ([]*models.Stage, error) {
+	if obj.Stages != nil {
+		return obj.Stages, nil
+	}
+
+	stages, err := r.loaders.For(ctx).StagesByVenue.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching venue stages: %w", err)
+	}
+	return stages, nil
+}
+
+// Venue returns graphql1.VenueResolver implementation.
+func (r *Resolver) Venue() graphql1.VenueResolver { return &venueResolver{r} }
+
+type venueResolver struct{ *Resolver }
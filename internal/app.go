@@ -1,48 +1,129 @@
 package internal
 
 import (
+	"fmt"
+	"github.com/blnto/blnto_service/internal/api/feed"
+	"github.com/blnto/blnto_service/internal/api/graphql/limits"
+	"github.com/blnto/blnto_service/internal/api/graphql/loaders"
+	"github.com/blnto/blnto_service/internal/api/graphql/persisted"
 	"github.com/blnto/blnto_service/internal/api/graphql/resolvers"
+	"github.com/blnto/blnto_service/internal/application/artistsync"
+	"github.com/blnto/blnto_service/internal/application/discovery"
+	"github.com/blnto/blnto_service/internal/application/promotedset"
 	"github.com/blnto/blnto_service/internal/application/service"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/residentadvisor"
+	"github.com/blnto/blnto_service/internal/infrastructure/graphql"
+	"github.com/blnto/blnto_service/internal/infrastructure/linkcheck"
 	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/blnto/blnto_service/internal/infrastructure/secrets"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gorm.io/gorm"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type App struct {
-	DB               *gorm.DB
-	ArtistService    *service.ArtistService
-	EventService     *service.EventService
-	StageService     *service.StageService
-	VenueService     *service.VenueService
-	Logger           *zap.Logger
-	Loggerfile       *os.File
-	Resolver         *resolvers.Resolver
-	ArtistRepository *repository.ArtistRepository
-	VenueRepository  *repository.VenueRepository
-	EventRepository  *repository.EventRepository
-	StageRepository  *repository.StageRepository
+	DB                   *gorm.DB
+	ArtistService        *service.ArtistService
+	EventService         *service.EventService
+	StageService         *service.StageService
+	VenueService         *service.VenueService
+	ResidencyService     *service.ResidencyService
+	CollectiveService    *service.CollectiveService
+	LinkHealthService    *service.LinkHealthService
+	EnrichmentService    *service.EnrichmentService
+	TrackService         *service.TrackService
+	SuggestionService    *service.ProfileSuggestionService
+	PromotedSetService   *service.PromotedSetService
+	PopularityService    *service.PopularityService
+	SoundCloudAccounts   *service.SoundCloudAccountService
+	ProviderRegistry     *provider.Registry
+	SoundCloudClient     *artistApi.SoundCloudClient
+	LinkChecker          *linkcheck.Checker
+	ArtistSync           *artistsync.Engine
+	Discoverer           *discovery.Discoverer
+	PromotedSetRefresher *promotedset.Refresher
+	Logger               *zap.Logger
+	Loggerfile           *os.File
+	Resolver             *resolvers.Resolver
+	Loaders              *loaders.Factory
+	QueryLimits          *limits.Limiter
+	PersistedQueries     *persisted.AllowList
+	Feed                 *feed.Handler
+	ArtistRepository     *repository.ArtistRepository
+	VenueRepository      *repository.VenueRepository
+	EventRepository      *repository.EventRepository
+	StageRepository      *repository.StageRepository
+	ResidencyRepository  *repository.ResidencyRepository
+	CollectiveRepository *repository.CollectiveRepository
+	LinkHealthRepository *repository.LinkHealthRepository
+	ProfileRepository    *repository.ExternalProfileRepository
+	TrackRepository      *repository.TrackRepository
+	SyncRunRepository    *repository.SyncRunRepository
+	SuggestionRepository *repository.ProfileSuggestionRepository
+	PopularityRepository *repository.PopularityRepository
 }
 
 func NewApp(config *App) *App {
 	return &App{
-		DB:               config.DB,
-		ArtistService:    config.ArtistService,
-		EventService:     config.EventService,
-		StageService:     config.StageService,
-		VenueService:     config.VenueService,
-		Logger:           config.Logger,
-		Loggerfile:       config.Loggerfile,
-		Resolver:         config.Resolver,
-		ArtistRepository: config.ArtistRepository,
-		VenueRepository:  config.VenueRepository,
-		EventRepository:  config.EventRepository,
-		StageRepository:  config.StageRepository,
+		DB:                   config.DB,
+		ArtistService:        config.ArtistService,
+		EventService:         config.EventService,
+		StageService:         config.StageService,
+		VenueService:         config.VenueService,
+		ResidencyService:     config.ResidencyService,
+		CollectiveService:    config.CollectiveService,
+		LinkHealthService:    config.LinkHealthService,
+		EnrichmentService:    config.EnrichmentService,
+		TrackService:         config.TrackService,
+		SuggestionService:    config.SuggestionService,
+		PromotedSetService:   config.PromotedSetService,
+		PopularityService:    config.PopularityService,
+		SoundCloudAccounts:   config.SoundCloudAccounts,
+		ProviderRegistry:     config.ProviderRegistry,
+		SoundCloudClient:     config.SoundCloudClient,
+		LinkChecker:          config.LinkChecker,
+		ArtistSync:           config.ArtistSync,
+		Discoverer:           config.Discoverer,
+		PromotedSetRefresher: config.PromotedSetRefresher,
+		Logger:               config.Logger,
+		Loggerfile:           config.Loggerfile,
+		Resolver:             config.Resolver,
+		Loaders:              config.Loaders,
+		QueryLimits:          config.QueryLimits,
+		PersistedQueries:     config.PersistedQueries,
+		Feed:                 config.Feed,
+		ArtistRepository:     config.ArtistRepository,
+		VenueRepository:      config.VenueRepository,
+		EventRepository:      config.EventRepository,
+		StageRepository:      config.StageRepository,
+		ResidencyRepository:  config.ResidencyRepository,
+		CollectiveRepository: config.CollectiveRepository,
+		LinkHealthRepository: config.LinkHealthRepository,
+		ProfileRepository:    config.ProfileRepository,
+		TrackRepository:      config.TrackRepository,
+		SyncRunRepository:    config.SyncRunRepository,
+		SuggestionRepository: config.SuggestionRepository,
+		PopularityRepository: config.PopularityRepository,
 	}
 }
 
 func InitializeDependencies() (*App, error) {
+	// Validate the provider credentials and load the token encryption keys before touching the database
+	soundCloudConfig, err := provideSoundCloudConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid soundcloud configuration: %w", err)
+	}
+	keyring, err := secrets.LoadKeyring()
+	if err != nil {
+		return nil, fmt.Errorf("invalid token encryption keys: %w", err)
+	}
+
 	// Create a database connection
 	db, err := repository.ProvideDatabase()
 
@@ -57,35 +138,113 @@ func InitializeDependencies() (*App, error) {
 	venueRepo := repository.NewVenueRepository(db)
 	eventRepo := repository.NewEventRepository(db)
 	stageRepo := repository.NewStageRepository(db)
+	residencyRepo := repository.NewResidencyRepository(db)
+	collectiveRepo := repository.NewCollectiveRepository(db)
+	linkHealthRepo := repository.NewLinkHealthRepository(db)
+	profileRepo := repository.NewExternalProfileRepository(db)
+	trackRepo := repository.NewTrackRepository(db)
+	syncRunRepo := repository.NewSyncRunRepository(db)
+	suggestionRepo := repository.NewProfileSuggestionRepository(db)
+	promotedSetRepo := repository.NewPromotedSetRepository(db)
+	popularityRepo := repository.NewPopularityRepository(db)
+	// Create the artist metadata providers
+	soundCloudClient := artistApi.NewClient(artistApi.NewDBTokenStore(db, keyring), soundCloudConfig)
+	providerRegistry := provideProviderRegistry(soundCloudClient)
+	embedder, err := provideEmbedder(providerRegistry)
+	if err != nil {
+		return nil, err
+	}
+	// Artists connect their own SoundCloud accounts, their tokens are kept apart from the app token
+	accountConnector := artistApi.NewAccountConnector(soundCloudClient, artistApi.NewDBAccountStore(db, keyring), artistRepo)
+	// Create a logger
+	logger, file, err := provideLogger()
+
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a service
-	artistService := service.NewArtistService(artistRepo)
+	promotedSetService := service.NewPromotedSetService(promotedSetRepo, embedder, accountConnector)
+	artistService := service.NewArtistService(artistRepo, promotedSetService)
 	eventService := service.NewEventService(eventRepo)
 	stageService := service.NewStageService(stageRepo)
 	venueService := service.NewVenueService(venueRepo)
+	residencyService := service.NewResidencyService(residencyRepo)
+	collectiveService := service.NewCollectiveService(collectiveRepo, eventRepo)
+	linkHealthService := service.NewLinkHealthService(linkHealthRepo)
+	enrichmentService := service.NewEnrichmentService(providerRegistry, artistRepo, profileRepo, trackRepo, popularityRepo)
+	trackService := service.NewTrackService(trackRepo)
+	suggestionService := service.NewProfileSuggestionService(suggestionRepo, artistRepo, enrichmentService, logger)
+	popularityService := service.NewPopularityService(popularityRepo, artistRepo, providePopularityConfig())
+	soundCloudAccounts := service.NewSoundCloudAccountService(accountConnector, artistRepo)
 
-	// Create a logger
-	logger, file, err := provideLogger()
+	// Create the background social link checker
+	linkChecker := linkcheck.NewChecker(linkHealthRepo, nil, provideLinkCheckerConfig(), logger)
+
+	// Create the artist sync job
+	artistSync := artistsync.NewEngine(syncRunRepo, enrichmentService, ProvideArtistSyncConfig(), logger)
+
+	// Create the promoted set refresher
+	promotedSetRefresher := promotedset.NewRefresher(promotedSetRepo, embedder, accountConnector, providePromotedSetConfig(), logger)
+
+	// Create the SoundCloud discovery job for artists without a link
+	discoverer, err := provideDiscoverer(providerRegistry, suggestionRepo, suggestionService, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the per-request dataloaders that batch the lookups of field resolvers
+	loaderFactory := loaders.NewFactory(artistService, eventService, stageService, venueService, promotedSetService, residencyService, loaders.DefaultConfig())
 
+	// Load the approved operations before serving, a broken registry must not silently open strict mode
+	persistedQueries, err := providePersistedQueries()
 	if err != nil {
 		return nil, err
 	}
 
 	// Create a resolver
-	resolver := resolvers.NewResolver(artistService, eventService, stageService, venueService)
+	resolver := resolvers.NewResolver(artistService, eventService, stageService, venueService, residencyService, collectiveService, linkHealthService, trackService, suggestionService, promotedSetService, popularityService, soundCloudAccounts, loaderFactory)
 
 	appConfig := &App{
-		DB:               db,
-		ArtistService:    artistService,
-		EventService:     eventService,
-		StageService:     stageService,
-		VenueService:     venueService,
-		Logger:           logger,
-		Loggerfile:       file,
-		Resolver:         resolver,
-		ArtistRepository: artistRepo,
-		VenueRepository:  venueRepo,
-		EventRepository:  eventRepo,
-		StageRepository:  stageRepo,
+		DB:                   db,
+		ArtistService:        artistService,
+		EventService:         eventService,
+		StageService:         stageService,
+		VenueService:         venueService,
+		ResidencyService:     residencyService,
+		CollectiveService:    collectiveService,
+		LinkHealthService:    linkHealthService,
+		EnrichmentService:    enrichmentService,
+		TrackService:         trackService,
+		SuggestionService:    suggestionService,
+		PromotedSetService:   promotedSetService,
+		PopularityService:    popularityService,
+		SoundCloudAccounts:   soundCloudAccounts,
+		ProviderRegistry:     providerRegistry,
+		SoundCloudClient:     soundCloudClient,
+		LinkChecker:          linkChecker,
+		ArtistSync:           artistSync,
+		Discoverer:           discoverer,
+		PromotedSetRefresher: promotedSetRefresher,
+		Logger:               logger,
+		Loggerfile:           file,
+		Resolver:             resolver,
+		Loaders:              loaderFactory,
+		QueryLimits:          limits.NewLimiter(provideQueryLimitsConfig()),
+		PersistedQueries:     persistedQueries,
+		Feed:                 feed.NewHandler(venueService, eventService, artistService, provideFeedConfig(), logger),
+		ArtistRepository:     artistRepo,
+		VenueRepository:      venueRepo,
+		EventRepository:      eventRepo,
+		StageRepository:      stageRepo,
+		ResidencyRepository:  residencyRepo,
+		CollectiveRepository: collectiveRepo,
+		LinkHealthRepository: linkHealthRepo,
+		ProfileRepository:    profileRepo,
+		TrackRepository:      trackRepo,
+		SyncRunRepository:    syncRunRepo,
+		SuggestionRepository: suggestionRepo,
+		PopularityRepository: popularityRepo,
 	}
 	return NewApp(appConfig), nil
 }
@@ -113,3 +272,286 @@ func provideLogger() (*zap.Logger, *os.File, error) {
 	logger.Info("This is a JSON log message", zap.String("type", "example"))
 	return logger, file, nil
 }
+
+// provideProviderRegistry registers SoundCloud, and Resident Advisor when RESIDENT_ADVISOR_SYNC_ENABLED is set. Without
+// it RA pages are only read by the cmd/cli/residentadvisor importer.
+func provideProviderRegistry(soundCloudClient *artistApi.SoundCloudClient) *provider.Registry {
+	providers := []provider.Provider{
+		artistApi.NewSoundCloudProvider(soundCloudClient, artistApi.SoundCloudPriority),
+	}
+	if os.Getenv("RESIDENT_ADVISOR_SYNC_ENABLED") == "true" {
+		client := residentadvisor.NewClient(residentadvisor.NewFetcher(ProvideResidentAdvisorConfig()))
+		providers = append(providers, residentadvisor.NewProvider(client, residentadvisor.DefaultPriority))
+	}
+	return provider.NewRegistry(providers...)
+}
+
+// provideEmbedder returns the provider that resolves promoted sets, SoundCloud being the only one that can.
+func provideEmbedder(registry *provider.Registry) (provider.Embedder, error) {
+	p, ok := registry.Get(provider.SoundCloud)
+	if !ok {
+		return nil, fmt.Errorf("provider %s is not registered", provider.SoundCloud)
+	}
+	embedder, ok := p.(provider.Embedder)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support embedding", provider.SoundCloud)
+	}
+	return embedder, nil
+}
+
+// provideSoundCloudConfig reads the SoundCloud client settings from the environment, unset values use the defaults.
+// The credentials have no default and must be set.
+// Pointing SOUNDCLOUD_BASE_URL, SOUNDCLOUD_TOKEN_URL and SOUNDCLOUD_OEMBED_URL at cmd/cli/fakesoundcloud runs
+// the sync offline.
+func provideSoundCloudConfig() (artistApi.Config, error) {
+	config := artistApi.Config{
+		BaseURL:      os.Getenv("SOUNDCLOUD_BASE_URL"),
+		TokenURL:     os.Getenv("SOUNDCLOUD_TOKEN_URL"),
+		OEmbedURL:    os.Getenv("SOUNDCLOUD_OEMBED_URL"),
+		AuthorizeURL: os.Getenv("SOUNDCLOUD_AUTHORIZE_URL"),
+		RedirectURL:  os.Getenv("SOUNDCLOUD_REDIRECT_URL"),
+		ClientID:     os.Getenv("SOUNDCLOUD_CLIENT_ID"),
+		ClientSecret: os.Getenv("SOUNDCLOUD_CLIENT_SECRET"),
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("SOUNDCLOUD_TIMEOUT")); err == nil {
+		config.Timeout = timeout
+	}
+	if rate, err := strconv.ParseFloat(os.Getenv("SOUNDCLOUD_REQUESTS_PER_SECOND"), 64); err == nil {
+		config.RequestsPerSecond = rate
+	}
+	if burst, err := strconv.Atoi(os.Getenv("SOUNDCLOUD_BURST")); err == nil {
+		config.Burst = burst
+	}
+	if retries, err := strconv.Atoi(os.Getenv("SOUNDCLOUD_MAX_RETRIES")); err == nil {
+		config.MaxRetries = retries
+	}
+	if threshold, err := strconv.Atoi(os.Getenv("SOUNDCLOUD_BREAKER_THRESHOLD")); err == nil {
+		config.BreakerThreshold = threshold
+	}
+	if cooldown, err := time.ParseDuration(os.Getenv("SOUNDCLOUD_BREAKER_COOLDOWN")); err == nil {
+		config.BreakerCooldown = cooldown
+	}
+	if refreshBefore, err := time.ParseDuration(os.Getenv("SOUNDCLOUD_TOKEN_REFRESH_BEFORE")); err == nil {
+		config.TokenRefreshBefore = refreshBefore
+	}
+	if ttl, err := time.ParseDuration(os.Getenv("SOUNDCLOUD_AUTHORIZATION_TTL")); err == nil {
+		config.AuthorizationTTL = ttl
+	}
+	return config, config.Validate()
+}
+
+// ProvideResidentAdvisorConfig reads the Resident Advisor fetcher settings from the environment, unset values use
+// the defaults. Setting RESIDENT_ADVISOR_FIXTURE_DIR reads saved pages instead of fetching them.
+func ProvideResidentAdvisorConfig() residentadvisor.Config {
+	config := residentadvisor.Config{
+		BaseURL:    os.Getenv("RESIDENT_ADVISOR_BASE_URL"),
+		FixtureDir: os.Getenv("RESIDENT_ADVISOR_FIXTURE_DIR"),
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("RESIDENT_ADVISOR_TIMEOUT")); err == nil {
+		config.Timeout = timeout
+	}
+	if interval, err := time.ParseDuration(os.Getenv("RESIDENT_ADVISOR_REQUEST_INTERVAL")); err == nil {
+		config.RequestInterval = interval
+	}
+	return config
+}
+
+// provideLinkCheckerConfig reads the link checker settings from the environment, falling back to the defaults.
+func provideLinkCheckerConfig() linkcheck.Config {
+	config := linkcheck.DefaultConfig()
+
+	if interval, err := time.ParseDuration(os.Getenv("LINK_CHECK_INTERVAL")); err == nil && interval > 0 {
+		config.Interval = interval
+	}
+	if recheckAfter, err := time.ParseDuration(os.Getenv("LINK_CHECK_RECHECK_AFTER")); err == nil {
+		config.RecheckAfter = recheckAfter
+	}
+	if perHost, err := time.ParseDuration(os.Getenv("LINK_CHECK_PER_HOST_INTERVAL")); err == nil {
+		config.PerHostInterval = perHost
+	}
+	if concurrency, err := strconv.Atoi(os.Getenv("LINK_CHECK_CONCURRENCY")); err == nil {
+		config.Concurrency = concurrency
+	}
+	if threshold, err := strconv.Atoi(os.Getenv("LINK_CHECK_FAILURE_THRESHOLD")); err == nil {
+		config.FailureThreshold = threshold
+	}
+
+	return config
+}
+
+// ProvideArtistSyncConfig reads the artist sync settings from the environment, falling back to the defaults.
+func ProvideArtistSyncConfig() artistsync.Config {
+	config := artistsync.DefaultConfig()
+
+	if interval, err := time.ParseDuration(os.Getenv("ARTIST_SYNC_INTERVAL")); err == nil && interval > 0 {
+		config.Interval = interval
+	}
+	if staleAfter, err := time.ParseDuration(os.Getenv("ARTIST_SYNC_STALE_AFTER")); err == nil {
+		config.StaleAfter = staleAfter
+	}
+	if workers, err := strconv.Atoi(os.Getenv("ARTIST_SYNC_WORKERS")); err == nil {
+		config.Workers = workers
+	}
+	if batchSize, err := strconv.Atoi(os.Getenv("ARTIST_SYNC_BATCH_SIZE")); err == nil {
+		config.BatchSize = batchSize
+	}
+
+	return config
+}
+
+// provideDiscoverer creates the discovery job for SoundCloud, the only provider that supports searching.
+func provideDiscoverer(registry *provider.Registry, store discovery.Store, linker discovery.Linker, logger *zap.Logger) (*discovery.Discoverer, error) {
+	p, ok := registry.Get(provider.SoundCloud)
+	if !ok {
+		return nil, fmt.Errorf("provider %s is not registered", provider.SoundCloud)
+	}
+	searcher, ok := p.(provider.Searcher)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support search", provider.SoundCloud)
+	}
+	return discovery.NewDiscoverer(store, searcher, linker, provideDiscoveryConfig(), logger), nil
+}
+
+// provideDiscoveryConfig reads the artist discovery settings from the environment, falling back to the defaults.
+func provideDiscoveryConfig() discovery.Config {
+	config := discovery.DefaultConfig()
+
+	if interval, err := time.ParseDuration(os.Getenv("ARTIST_DISCOVERY_INTERVAL")); err == nil && interval > 0 {
+		config.Interval = interval
+	}
+	if recheckAfter, err := time.ParseDuration(os.Getenv("ARTIST_DISCOVERY_RECHECK_AFTER")); err == nil {
+		config.RecheckAfter = recheckAfter
+	}
+	if threshold, err := strconv.ParseFloat(os.Getenv("ARTIST_DISCOVERY_AUTO_LINK_THRESHOLD"), 64); err == nil {
+		config.AutoLinkThreshold = threshold
+	}
+	if margin, err := strconv.ParseFloat(os.Getenv("ARTIST_DISCOVERY_MIN_MARGIN"), 64); err == nil {
+		config.MinMargin = margin
+	}
+	if threshold, err := strconv.ParseFloat(os.Getenv("ARTIST_DISCOVERY_SUGGEST_THRESHOLD"), 64); err == nil {
+		config.SuggestThreshold = threshold
+	}
+
+	return config
+}
+
+// providePromotedSetConfig reads the promoted set refresh settings from the environment, falling back to the defaults.
+func providePromotedSetConfig() promotedset.Config {
+	config := promotedset.DefaultConfig()
+
+	if interval, err := time.ParseDuration(os.Getenv("PROMOTED_SET_REFRESH_INTERVAL")); err == nil && interval > 0 {
+		config.Interval = interval
+	}
+	if recheckAfter, err := time.ParseDuration(os.Getenv("PROMOTED_SET_RECHECK_AFTER")); err == nil {
+		config.RecheckAfter = recheckAfter
+	}
+	if threshold, err := strconv.Atoi(os.Getenv("PROMOTED_SET_FAILURE_THRESHOLD")); err == nil {
+		config.FailureThreshold = threshold
+	}
+
+	return config
+}
+
+// providePersistedQueries loads the registry of approved operations, validated against the schema.
+func providePersistedQueries() (*persisted.AllowList, error) {
+	config := persisted.DefaultConfig()
+	if dir := os.Getenv("PERSISTED_QUERIES_DIR"); dir != "" {
+		config.Dir = dir
+	}
+	config.Strict = os.Getenv("PERSISTED_QUERIES_STRICT") == "true"
+	if header := os.Getenv("PERSISTED_QUERIES_BYPASS_HEADER"); header != "" {
+		config.BypassHeader = header
+	}
+	config.BypassToken = os.Getenv("PERSISTED_QUERIES_BYPASS_TOKEN")
+	if size, err := strconv.Atoi(os.Getenv("PERSISTED_QUERIES_CACHE_SIZE")); err == nil {
+		config.CacheSize = size
+	}
+
+	registry, err := persisted.LoadRegistry(config.Dir, graphql.NewExecutableSchema(graphql.Config{}).Schema())
+	if err != nil {
+		return nil, fmt.Errorf("invalid persisted query registry: %w", err)
+	}
+	if config.Strict && registry.Len() == 0 {
+		return nil, fmt.Errorf("persisted query strict mode is on but %s holds no operations", config.Dir)
+	}
+	return persisted.NewAllowList(registry, config), nil
+}
+
+// provideQueryLimitsConfig reads the GraphQL query budget from the environment, falling back to the defaults.
+// GRAPHQL_FIELD_COSTS lists cost overrides as "Type.field=cost" pairs separated by commas.
+func provideQueryLimitsConfig() limits.Config {
+	config := limits.DefaultConfig()
+
+	if maxComplexity, err := strconv.Atoi(os.Getenv("GRAPHQL_MAX_COMPLEXITY")); err == nil {
+		config.MaxComplexity = maxComplexity
+	}
+	if maxDepth, err := strconv.Atoi(os.Getenv("GRAPHQL_MAX_DEPTH")); err == nil {
+		config.MaxDepth = maxDepth
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("GRAPHQL_TIMEOUT")); err == nil {
+		config.Timeout = timeout
+	}
+	if pageSize, err := strconv.Atoi(os.Getenv("GRAPHQL_DEFAULT_PAGE_SIZE")); err == nil {
+		config.DefaultPageSize = pageSize
+	}
+	for _, pair := range strings.Split(os.Getenv("GRAPHQL_FIELD_COSTS"), ",") {
+		field, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if cost, err := strconv.Atoi(value); ok && err == nil && cost >= 0 {
+			if config.FieldCosts == nil {
+				config.FieldCosts = make(map[string]int)
+			}
+			config.FieldCosts[field] = cost
+		}
+	}
+
+	return config
+}
+
+// provideFeedConfig reads the caching policy and page sizes of the JSON feed from the environment, falling back to the
+// defaults.
+func provideFeedConfig() feed.Config {
+	config := feed.DefaultConfig()
+
+	if maxAge, err := time.ParseDuration(os.Getenv("FEED_CACHE_MAX_AGE")); err == nil && maxAge >= 0 {
+		config.Cache.MaxAge = maxAge
+	}
+	if stale, err := time.ParseDuration(os.Getenv("FEED_CACHE_STALE_WHILE_REVALIDATE")); err == nil && stale >= 0 {
+		config.Cache.StaleWhileRevalidate = stale
+	}
+	if limit, err := strconv.Atoi(os.Getenv("FEED_DEFAULT_LIMIT")); err == nil && limit > 0 {
+		config.DefaultLimit = limit
+	}
+	if limit, err := strconv.Atoi(os.Getenv("FEED_MAX_LIMIT")); err == nil && limit > 0 {
+		config.MaxLimit = limit
+	}
+
+	return config
+}
+
+func providePopularityConfig() service.PopularityConfig {
+	config := service.DefaultPopularityConfig()
+
+	if horizon, err := time.ParseDuration(os.Getenv("TRENDING_BOOKING_HORIZON")); err == nil {
+		config.BookingHorizon = horizon
+	}
+	if weight, err := strconv.ParseFloat(os.Getenv("TRENDING_BOOKING_WEIGHT"), 64); err == nil {
+		config.Weights.Booking = weight
+	}
+	if limit, err := strconv.Atoi(os.Getenv("FEATURED_ARTISTS_LIMIT")); err == nil && limit > 0 {
+		config.Featured.Limit = limit
+	}
+	if window, err := time.ParseDuration(os.Getenv("FEATURED_ARTISTS_WINDOW")); err == nil {
+		config.Featured.Window = window
+	}
+	if minFollowers, err := strconv.Atoi(os.Getenv("FEATURED_ARTISTS_MIN_FOLLOWERS")); err == nil {
+		config.Featured.MinFollowers = minFollowers
+	}
+	if require, err := strconv.ParseBool(os.Getenv("FEATURED_ARTISTS_REQUIRE_UPCOMING_BOOKING")); err == nil {
+		config.Featured.RequireUpcomingBooking = require
+	}
+	if require, err := strconv.ParseBool(os.Getenv("FEATURED_ARTISTS_REQUIRE_SOUNDCLOUD")); err == nil {
+		config.Featured.RequireSoundCloud = require
+	}
+
+	return config
+}
@@ -0,0 +1,256 @@
+// Package artistsync refreshes stale artists from the metadata providers. Each pass is recorded as a sync run
+// so an interrupted pass is resumed instead of started over.
+package artistsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Store loads stale artists and persists sync runs.
+type Store interface {
+	FindStaleArtists(ctx context.Context, staleBefore time.Time, runID uuid.UUID, cursor string, limit int) ([]artist.Artist, error)
+	MarkArtistSynced(ctx context.Context, artistID uuid.UUID, at time.Time) error
+	FindResumableRun(ctx context.Context) (*artist.SyncRun, error)
+	CreateRun(ctx context.Context, run *artist.SyncRun) error
+	UpdateRunStatus(ctx context.Context, run *artist.SyncRun) error
+	RecordSuccess(ctx context.Context, runID uuid.UUID) error
+	RecordFailure(ctx context.Context, runID, artistID uuid.UUID, syncErr error) error
+}
+
+// Enricher refreshes a single artist from the providers.
+type Enricher interface {
+	EnrichArtist(ctx context.Context, a *artist.Artist) error
+	PreviewArtist(ctx context.Context, a *artist.Artist) ([]artist.ProfileChange, error)
+}
+
+// Config holds the sync settings.
+type Config struct {
+	// Interval between two scheduled runs
+	Interval time.Duration
+	// StaleAfter is the age of the last sync after which an artist is synced again
+	StaleAfter time.Duration
+	// Workers bounds the number of artists synced concurrently
+	Workers int
+	// BatchSize is the number of artists loaded at once
+	BatchSize int
+}
+
+// DefaultConfig returns the settings used when nothing else is configured.
+func DefaultConfig() Config {
+	return Config{
+		Interval:   24 * time.Hour,
+		StaleAfter: 24 * time.Hour,
+		Workers:    4,
+		BatchSize:  100,
+	}
+}
+
+// Engine syncs stale artists with a bounded worker pool.
+type Engine struct {
+	store    Store
+	enricher Enricher
+	config   Config
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+func NewEngine(store Store, enricher Enricher, config Config, logger *zap.Logger) *Engine {
+	if config.Interval <= 0 {
+		config.Interval = DefaultConfig().Interval
+	}
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.BatchSize < 1 {
+		config.BatchSize = DefaultConfig().BatchSize
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Engine{store: store, enricher: enricher, config: config, logger: logger, now: time.Now}
+}
+
+// Run syncs stale artists every Interval until the context is cancelled.
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if run, err := e.Sync(ctx); err != nil && ctx.Err() == nil {
+			e.logger.Error("artist sync failed", zap.Error(err))
+		} else if run != nil {
+			e.logger.Info("artist sync finished",
+				zap.String("run", run.ID.String()),
+				zap.String("status", string(run.Status)),
+				zap.Int("succeeded", run.Succeeded),
+				zap.Int("failed", run.Failed),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync resumes the last unfinished run, or starts a new one, and syncs every stale artist. When the context is
+// cancelled the run is left interrupted for the next call to resume.
+func (e *Engine) Sync(ctx context.Context) (*artist.SyncRun, error) {
+	run, err := e.store.FindResumableRun(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading unfinished sync run: %w", err)
+	}
+	if run == nil {
+		run = artist.NewSyncRun(e.now().Add(-e.config.StaleAfter), e.now())
+		if err := e.store.CreateRun(ctx, run); err != nil {
+			return nil, fmt.Errorf("error creating sync run: %w", err)
+		}
+	} else {
+		e.logger.Info("resuming artist sync", zap.String("run", run.ID.String()))
+	}
+	run.Status = artist.SyncRunning
+
+	var succeeded, failed int
+	var mu sync.Mutex
+	err = e.each(ctx, run.StaleBefore, run.ID, func(a *artist.Artist) {
+		syncErr := e.syncArtist(ctx, a)
+		if syncErr != nil && ctx.Err() != nil {
+			// Shutting down, the artist stays stale and is picked up when the run resumes
+			return
+		}
+		// An artist that finished syncing is recorded even while shutting down, so the resumed run skips it
+		ctx := context.WithoutCancel(ctx)
+
+		if syncErr != nil {
+			if err := e.store.RecordFailure(ctx, run.ID, a.ID, syncErr); err != nil {
+				e.logger.Error("failed to record sync failure", zap.String("artist", a.ID.String()), zap.Error(err))
+			}
+			mu.Lock()
+			failed++
+			mu.Unlock()
+			return
+		}
+
+		if err := e.store.MarkArtistSynced(ctx, a.ID, e.now()); err != nil {
+			e.logger.Error("failed to mark artist synced", zap.String("artist", a.ID.String()), zap.Error(err))
+		}
+		if err := e.store.RecordSuccess(ctx, run.ID); err != nil {
+			e.logger.Error("failed to record sync success", zap.String("artist", a.ID.String()), zap.Error(err))
+		}
+		mu.Lock()
+		succeeded++
+		mu.Unlock()
+	})
+
+	run.Succeeded += succeeded
+	run.Failed += failed
+	run.Total += succeeded + failed
+	switch {
+	case ctx.Err() != nil:
+		run.Status = artist.SyncInterrupted
+	case err != nil:
+		run.Finish(artist.SyncFailed, e.now())
+	default:
+		run.Finish(artist.SyncCompleted, e.now())
+	}
+
+	// The run must be saved even when the context was cancelled
+	if updateErr := e.store.UpdateRunStatus(context.WithoutCancel(ctx), run); updateErr != nil {
+		return run, fmt.Errorf("error saving sync run: %w", updateErr)
+	}
+	return run, err
+}
+
+// DryRun fetches every stale artist's profiles without storing anything and writes the changes a sync would make.
+func (e *Engine) DryRun(ctx context.Context, w io.Writer) error {
+	staleBefore := e.now().Add(-e.config.StaleAfter)
+
+	var mu sync.Mutex
+	return e.each(ctx, staleBefore, uuid.Nil, func(a *artist.Artist) {
+		changes, err := e.enricher.PreviewArtist(ctx, a)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			fmt.Fprintf(w, "%s (%s): error: %v\n", a.Name, a.ID, err)
+		}
+		if len(changes) == 0 && err == nil {
+			fmt.Fprintf(w, "%s (%s): no changes\n", a.Name, a.ID)
+			return
+		}
+		for _, c := range changes {
+			fmt.Fprintf(w, "%s (%s): %s.%s: %q -> %q\n", a.Name, a.ID, c.Provider, c.Field, c.Old, c.New)
+		}
+	})
+}
+
+// syncArtist enriches the artist, waiting and retrying while a provider reports it is unavailable.
+func (e *Engine) syncArtist(ctx context.Context, a *artist.Artist) error {
+	for {
+		err := e.enricher.EnrichArtist(ctx, a)
+
+		var unavailable *provider.UnavailableError
+		if !errors.As(err, &unavailable) {
+			return err
+		}
+		e.logger.Warn("provider unavailable, pausing sync", zap.String("provider", string(unavailable.Provider)), zap.Time("until", unavailable.RetryAt))
+
+		timer := time.NewTimer(time.Until(unavailable.RetryAt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// each feeds the stale artists to Workers goroutines running fn, one batch at a time.
+func (e *Engine) each(ctx context.Context, staleBefore time.Time, runID uuid.UUID, fn func(a *artist.Artist)) error {
+	jobs := make(chan *artist.Artist)
+	var wg sync.WaitGroup
+	for i := 0; i < e.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range jobs {
+				fn(a)
+			}
+		}()
+	}
+	defer func() {
+		close(jobs)
+		wg.Wait()
+	}()
+
+	cursor := ""
+	for {
+		artists, err := e.store.FindStaleArtists(ctx, staleBefore, runID, cursor, e.config.BatchSize)
+		if err != nil {
+			return fmt.Errorf("error loading stale artists: %w", err)
+		}
+		for i := range artists {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case jobs <- &artists[i]:
+			}
+		}
+		if len(artists) < e.config.BatchSize {
+			return nil
+		}
+		cursor = artists[len(artists)-1].ID.String()
+	}
+}
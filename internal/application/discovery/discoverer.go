@@ -0,0 +1,230 @@
+// Package discovery searches providers for artists that are not linked to them yet. Candidates are scored
+// against the artist; a clear match is linked right away and plausible ones are queued for editors to review.
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Store loads unlinked artists and persists suggestions.
+type Store interface {
+	FindUnlinkedArtists(ctx context.Context, name provider.Name, checkedBefore time.Time, cursor string, limit int) ([]artist.Artist, error)
+	MarkDiscoveryChecked(ctx context.Context, artistID uuid.UUID, at time.Time) error
+	IsLinkedElsewhere(ctx context.Context, name provider.Name, externalID string, artistID uuid.UUID) (bool, error)
+	SaveSuggestions(ctx context.Context, suggestions []artist.ProfileSuggestion) error
+}
+
+// Linker links an artist to a profile.
+type Linker interface {
+	Link(ctx context.Context, a *artist.Artist, suggestion *artist.ProfileSuggestion) error
+}
+
+// Config holds the discovery settings.
+type Config struct {
+	// Interval between two scheduled passes
+	Interval time.Duration
+	// RecheckAfter is how long an artist without a match waits before it is searched again
+	RecheckAfter time.Duration
+	// AutoLinkThreshold is the score from which the best candidate is linked without review
+	AutoLinkThreshold float64
+	// MinMargin is how far the best candidate must lead the runner-up to be linked without review
+	MinMargin float64
+	// SuggestThreshold is the lowest score queued as a suggestion
+	SuggestThreshold float64
+	// Candidates is the number of search results scored per artist
+	Candidates int
+	// BatchSize is the number of artists loaded at once
+	BatchSize int
+}
+
+// DefaultConfig returns the settings used when nothing else is configured.
+func DefaultConfig() Config {
+	return Config{
+		Interval:          24 * time.Hour,
+		RecheckAfter:      30 * 24 * time.Hour,
+		AutoLinkThreshold: 0.9,
+		MinMargin:         0.1,
+		SuggestThreshold:  0.5,
+		Candidates:        10,
+		BatchSize:         50,
+	}
+}
+
+// Result counts what a pass did.
+type Result struct {
+	Checked    int
+	AutoLinked int
+	Suggested  int
+	Failed     int
+}
+
+// Discoverer searches one provider for unlinked artists.
+type Discoverer struct {
+	store    Store
+	searcher provider.Searcher
+	linker   Linker
+	config   Config
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+func NewDiscoverer(store Store, searcher provider.Searcher, linker Linker, config Config, logger *zap.Logger) *Discoverer {
+	if config.Interval <= 0 {
+		config.Interval = DefaultConfig().Interval
+	}
+	if config.Candidates < 1 {
+		config.Candidates = DefaultConfig().Candidates
+	}
+	if config.BatchSize < 1 {
+		config.BatchSize = DefaultConfig().BatchSize
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Discoverer{store: store, searcher: searcher, linker: linker, config: config, logger: logger, now: time.Now}
+}
+
+// Run searches for unlinked artists every Interval until the context is cancelled.
+func (d *Discoverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if result, err := d.Discover(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Error("artist discovery failed", zap.Error(err))
+		} else {
+			d.logger.Info("artist discovery finished",
+				zap.String("provider", string(d.searcher.Name())),
+				zap.Int("checked", result.Checked),
+				zap.Int("autoLinked", result.AutoLinked),
+				zap.Int("suggested", result.Suggested),
+				zap.Int("failed", result.Failed),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Discover searches the provider for every unlinked artist that is due. A provider that becomes unavailable
+// ends the pass, the remaining artists are searched on the next one.
+func (d *Discoverer) Discover(ctx context.Context) (Result, error) {
+	var result Result
+	checkedBefore := d.now().Add(-d.config.RecheckAfter)
+
+	cursor := ""
+	for {
+		artists, err := d.store.FindUnlinkedArtists(ctx, d.searcher.Name(), checkedBefore, cursor, d.config.BatchSize)
+		if err != nil {
+			return result, fmt.Errorf("error loading unlinked artists: %w", err)
+		}
+
+		for i := range artists {
+			a := &artists[i]
+			linked, suggested, err := d.discoverArtist(ctx, a)
+			if err != nil {
+				var unavailable *provider.UnavailableError
+				if errors.As(err, &unavailable) || ctx.Err() != nil {
+					return result, err
+				}
+				d.logger.Warn("artist discovery failed", zap.String("artist", a.ID.String()), zap.Error(err))
+				result.Failed++
+				continue
+			}
+
+			result.Checked++
+			result.Suggested += suggested
+			if linked {
+				result.AutoLinked++
+			}
+		}
+
+		if len(artists) < d.config.BatchSize {
+			return result, nil
+		}
+		cursor = artists[len(artists)-1].ID.String()
+	}
+}
+
+type candidate struct {
+	profile provider.Profile
+	score   artist.CandidateScore
+}
+
+// discoverArtist searches the provider for the artist's name and either links the best candidate or stores the
+// plausible ones as suggestions. It reports whether the artist was linked and how many suggestions were stored.
+func (d *Discoverer) discoverArtist(ctx context.Context, a *artist.Artist) (bool, int, error) {
+	profiles, err := d.searcher.SearchProfiles(ctx, a.Name, d.config.Candidates)
+	if err != nil {
+		return false, 0, fmt.Errorf("error searching %s: %w", d.searcher.Name(), err)
+	}
+
+	candidates := make([]candidate, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile.ExternalID == "" {
+			continue
+		}
+		linked, err := d.store.IsLinkedElsewhere(ctx, d.searcher.Name(), profile.ExternalID, a.ID)
+		if err != nil {
+			return false, 0, fmt.Errorf("error checking existing links: %w", err)
+		}
+		candidates = append(candidates, candidate{profile: profile, score: artist.ScoreCandidate(a, &profile, linked)})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score.Total > candidates[j].score.Total
+	})
+
+	linked := false
+	var suggestions []artist.ProfileSuggestion
+	for i, c := range candidates {
+		if c.score.Total < d.config.SuggestThreshold {
+			break
+		}
+		suggestion := artist.NewProfileSuggestion(a.ID, d.searcher.Name(), &c.profile, c.score, artist.SuggestionPending)
+		if i == 0 && d.clearMatch(candidates) {
+			if err := d.linker.Link(ctx, a, &suggestion); err != nil {
+				return false, 0, fmt.Errorf("error linking %s: %w", c.profile.URL, err)
+			}
+			suggestion.Decide(artist.SuggestionAutoLinked, d.now())
+			linked = true
+		} else if linked {
+			suggestion.Decide(artist.SuggestionRejected, d.now())
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	if err := d.store.SaveSuggestions(ctx, suggestions); err != nil {
+		return linked, 0, fmt.Errorf("error saving suggestions: %w", err)
+	}
+	if err := d.store.MarkDiscoveryChecked(ctx, a.ID, d.now()); err != nil {
+		return linked, 0, fmt.Errorf("error marking artist checked: %w", err)
+	}
+
+	suggested := len(suggestions)
+	if linked {
+		suggested = 0
+	}
+	return linked, suggested, nil
+}
+
+// clearMatch reports whether the best of the sorted candidates is good enough to link without review.
+func (d *Discoverer) clearMatch(candidates []candidate) bool {
+	best := candidates[0].score
+	if best.LinkedElsewhere || best.Total < d.config.AutoLinkThreshold {
+		return false
+	}
+	return len(candidates) == 1 || best.Total-candidates[1].score.Total >= d.config.MinMargin
+}
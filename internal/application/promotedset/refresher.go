@@ -0,0 +1,142 @@
+// Package promotedset periodically resolves the artists' promoted sets again, so players that were taken down
+// are flagged and changed titles or artwork are picked up.
+package promotedset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Store loads the promoted sets to refresh and persists the results.
+type Store interface {
+	FindDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]artist.Artist, error)
+	FindByArtistID(ctx context.Context, artistID uuid.UUID) (*artist.PromotedSet, error)
+	Save(ctx context.Context, set *artist.PromotedSet) error
+}
+
+// Config holds the refresher settings.
+type Config struct {
+	// Interval between two refresh rounds
+	Interval time.Duration
+	// RecheckAfter is the minimum age of the last check before a promoted set is resolved again
+	RecheckAfter time.Duration
+	// BatchSize limits how many promoted sets are resolved per round
+	BatchSize int
+	// FailureThreshold is the number of consecutive failed resolutions after which a promoted set is flagged as broken
+	FailureThreshold int
+}
+
+// DefaultConfig returns the settings used when nothing else is configured.
+func DefaultConfig() Config {
+	return Config{
+		Interval:         24 * time.Hour,
+		RecheckAfter:     7 * 24 * time.Hour,
+		BatchSize:        200,
+		FailureThreshold: 3,
+	}
+}
+
+// Refresher resolves promoted sets that are due with the embedder.
+type Refresher struct {
+	store    Store
+	embedder provider.Embedder
+	accounts provider.AccountTokens
+	config   Config
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+// NewRefresher creates a refresher. accounts may be nil, then promoted sets are resolved without the artists'
+// connected accounts and private share links fail.
+func NewRefresher(store Store, embedder provider.Embedder, accounts provider.AccountTokens, config Config, logger *zap.Logger) *Refresher {
+	if config.Interval <= 0 {
+		config.Interval = DefaultConfig().Interval
+	}
+	if config.BatchSize < 1 {
+		config.BatchSize = DefaultConfig().BatchSize
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Refresher{store: store, embedder: embedder, accounts: accounts, config: config, logger: logger, now: time.Now}
+}
+
+// Run refreshes promoted sets every Interval until the context is cancelled.
+func (r *Refresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RefreshDue(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("promoted set refresh failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RefreshDue resolves one batch of promoted sets that are due and returns how many were checked. A provider that
+// becomes unavailable ends the round, the remaining sets are checked on the next one.
+func (r *Refresher) RefreshDue(ctx context.Context) (int, error) {
+	artists, err := r.store.FindDueForCheck(ctx, r.now().Add(-r.config.RecheckAfter), r.config.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("error loading promoted sets: %w", err)
+	}
+
+	checked := 0
+	for i := range artists {
+		if err := r.refresh(ctx, &artists[i]); err != nil {
+			return checked, err
+		}
+		checked++
+	}
+	return checked, nil
+}
+
+// refresh resolves the promoted set of one artist. Only errors that should end the round are returned.
+func (r *Refresher) refresh(ctx context.Context, a *artist.Artist) error {
+	set, err := r.store.FindByArtistID(ctx, a.ID)
+	if err != nil {
+		r.logger.Error("failed to load promoted set", zap.String("artist", a.ID.String()), zap.Error(err))
+		return nil
+	}
+	if set == nil || set.URL != a.SCPromotedSet {
+		// Never resolved or the URL changed without going through the artist service
+		set = &artist.PromotedSet{ArtistID: a.ID, Provider: r.embedder.Name(), URL: a.SCPromotedSet}
+	}
+
+	embed, err := provider.ResolveEmbedFor(ctx, r.embedder, r.accounts, a.ID, a.SCPromotedSet)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	var unavailable *provider.UnavailableError
+	if errors.As(err, &unavailable) {
+		return err
+	}
+
+	if err != nil {
+		conclusive := errors.Is(err, provider.ErrNotFound) || errors.Is(err, provider.ErrNotSupported)
+		set.RecordFailure(err, conclusive, r.now(), r.config.FailureThreshold)
+		if set.Broken {
+			r.logger.Warn("promoted set is broken", zap.String("artist", a.ID.String()), zap.String("url", a.SCPromotedSet), zap.Error(err))
+		}
+	} else {
+		set.Apply(embed, r.now())
+	}
+
+	if err := r.store.Save(ctx, set); err != nil {
+		r.logger.Error("failed to save promoted set", zap.String("artist", a.ID.String()), zap.Error(err))
+	}
+	return nil
+}
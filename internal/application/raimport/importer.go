@@ -0,0 +1,257 @@
+// Package raimport imports Resident Advisor artist and event pages. Every artist on a page is matched against the
+// existing artists, first by RA link and then by name. Matched artists get the RA link, and artist pages are stored
+// as the artist's RA profile, which ranks below SoundCloud and never replaces manually set fields. Artists that do
+// not exist yet are reported, not created.
+//
+// Event lineups are matched and reported but not stored as timetable entries: RA pages list the acts without stages
+// or set times, and events are not created from RA. Storing lineups is out of scope of the importer.
+package raimport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/residentadvisor"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ArtistStore loads the artists pages are matched against and stores their RA links.
+type ArtistStore interface {
+	FindAllWithSocialMediaLinks(ctx context.Context) ([]artist.Artist, error)
+	CreateSocialMediaLink(ctx context.Context, link artist.SocialMediaLink) error
+}
+
+// ProfileStore stores the RA profiles of matched artists.
+type ProfileStore interface {
+	Upsert(ctx context.Context, profile *artist.ExternalProfile) error
+}
+
+// Config holds the importer settings.
+type Config struct {
+	// Priority of the stored RA profiles against the other providers
+	Priority int
+	// DryRun matches pages without storing anything
+	DryRun bool
+}
+
+// DefaultConfig returns the settings used when nothing else is configured.
+func DefaultConfig() Config {
+	return Config{Priority: residentadvisor.DefaultPriority}
+}
+
+// MatchKind tells how an RA artist was matched.
+type MatchKind string
+
+const (
+	MatchedByLink MatchKind = "link"
+	MatchedByName MatchKind = "name"
+)
+
+// Match is an artist of an RA page matched against the existing artists.
+type Match struct {
+	Name string
+	// URL is the canonical RA link, empty for lineup acts without an RA page
+	URL string
+	// ArtistID is uuid.Nil when no artist matched
+	ArtistID uuid.UUID
+	By       MatchKind
+	// LinkAdded reports whether the RA link was added to the artist, or would be on a dry run
+	LinkAdded bool
+	// Problem explains why the act was not matched
+	Problem string
+}
+
+// Matched reports whether an existing artist matched.
+func (m *Match) Matched() bool {
+	return m.ArtistID != uuid.Nil
+}
+
+// ArtistResult is the outcome of importing an artist page.
+type ArtistResult struct {
+	Page  *residentadvisor.ArtistPage
+	Match Match
+}
+
+// EventResult is the outcome of importing an event page, with one match per lineup act.
+type EventResult struct {
+	Page   *residentadvisor.EventPage
+	Lineup []Match
+}
+
+// Importer imports RA pages. The artists are loaded on the first import and kept for the importer's lifetime,
+// create one importer per run.
+type Importer struct {
+	artists  ArtistStore
+	profiles ProfileStore
+	client   *residentadvisor.Client
+	config   Config
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu    sync.Mutex
+	index *artistIndex
+}
+
+func NewImporter(artists ArtistStore, profiles ProfileStore, client *residentadvisor.Client, config Config, logger *zap.Logger) *Importer {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Importer{
+		artists:  artists,
+		profiles: profiles,
+		client:   client,
+		config:   config,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// ImportArtist imports the artist page at the URL. A matched artist gets the RA link and the RA profile.
+func (i *Importer) ImportArtist(ctx context.Context, pageURL string) (*ArtistResult, error) {
+	page, err := i.client.ArtistPage(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	match, err := i.match(ctx, page.Name, page.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !match.Matched() || i.config.DryRun {
+		return &ArtistResult{Page: page, Match: match}, nil
+	}
+
+	profile := &artist.ExternalProfile{ArtistID: match.ArtistID, Provider: provider.ResidentAdvisor, Priority: i.config.Priority}
+	profile.ApplyProfile(page.Profile(), i.now())
+	if err := i.profiles.Upsert(ctx, profile); err != nil {
+		return nil, fmt.Errorf("error saving profile of artist %s: %w", match.ArtistID, err)
+	}
+	return &ArtistResult{Page: page, Match: match}, nil
+}
+
+// ImportEvent matches the lineup of the event page at the URL. Matched acts with an RA page get the RA link, the
+// lineup itself is only returned.
+func (i *Importer) ImportEvent(ctx context.Context, pageURL string) (*EventResult, error) {
+	page, err := i.client.EventPage(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	result := &EventResult{Page: page, Lineup: make([]Match, 0, len(page.Lineup))}
+	for _, act := range page.Lineup {
+		match, err := i.match(ctx, act.Name, act.URL)
+		if err != nil {
+			return nil, err
+		}
+		result.Lineup = append(result.Lineup, match)
+	}
+	return result, nil
+}
+
+// match finds the artist of an RA act and stores the RA link on it. Callers hold mu.
+func (i *Importer) match(ctx context.Context, name, raURL string) (Match, error) {
+	if i.index == nil {
+		artists, err := i.artists.FindAllWithSocialMediaLinks(ctx)
+		if err != nil {
+			return Match{}, fmt.Errorf("error loading artists: %w", err)
+		}
+		i.index = newArtistIndex(artists)
+	}
+
+	match := i.index.match(name, raURL)
+	if !match.Matched() || raURL == "" || i.index.hasLink(match.ArtistID) {
+		return match, nil
+	}
+
+	if !i.config.DryRun {
+		artistID := match.ArtistID
+		link := artist.SocialMediaLink{ArtistID: &artistID, Platform: artist.ResidentAdvisor, Link: raURL}
+		if err := link.Normalize(); err != nil {
+			return Match{}, fmt.Errorf("error normalizing %s: %w", raURL, err)
+		}
+		if err := i.artists.CreateSocialMediaLink(ctx, link); err != nil {
+			return Match{}, fmt.Errorf("error saving RA link of artist %s: %w", artistID, err)
+		}
+		i.logger.Info("linked artist to resident advisor", zap.String("artist", artistID.String()), zap.String("url", raURL))
+	}
+	// Indexed on dry runs too, so later pages match the artist by link as they would after a real run
+	i.index.addLink(match.ArtistID, raURL)
+	match.LinkAdded = true
+	return match, nil
+}
+
+// artistIndex looks artists up by RA slug and by name key
+type artistIndex struct {
+	bySlug map[string]uuid.UUID
+	byName map[string][]uuid.UUID
+	slugOf map[uuid.UUID]string
+	nameOf map[uuid.UUID]string
+}
+
+func newArtistIndex(artists []artist.Artist) *artistIndex {
+	index := &artistIndex{
+		bySlug: make(map[string]uuid.UUID),
+		byName: make(map[string][]uuid.UUID),
+		slugOf: make(map[uuid.UUID]string),
+		nameOf: make(map[uuid.UUID]string),
+	}
+	for _, a := range artists {
+		index.nameOf[a.ID] = a.Name
+		if key := artist.NameKey(a.Name); key != "" {
+			index.byName[key] = append(index.byName[key], a.ID)
+		}
+		for _, link := range a.SocialMediaLinks {
+			if link.Platform == artist.ResidentAdvisor {
+				index.addLink(a.ID, link.Link)
+			}
+		}
+	}
+	return index
+}
+
+func (x *artistIndex) addLink(artistID uuid.UUID, raURL string) {
+	if slug, ok := residentadvisor.ArtistSlug(raURL); ok {
+		x.bySlug[slug] = artistID
+		x.slugOf[artistID] = slug
+	}
+}
+
+func (x *artistIndex) hasLink(artistID uuid.UUID) bool {
+	_, ok := x.slugOf[artistID]
+	return ok
+}
+
+// match looks the act up by RA link first and by name second. A name only matches when exactly one artist has it
+// and that artist links no other RA page.
+func (x *artistIndex) match(name, raURL string) Match {
+	match := Match{Name: name, URL: raURL}
+	slug, hasSlug := residentadvisor.ArtistSlug(raURL)
+	if hasSlug {
+		if artistID, ok := x.bySlug[slug]; ok {
+			match.ArtistID, match.By = artistID, MatchedByLink
+			return match
+		}
+	}
+
+	candidates := x.byName[artist.NameKey(name)]
+	switch {
+	case len(candidates) == 0:
+		match.Problem = "no artist with this name"
+	case len(candidates) > 1:
+		match.Problem = fmt.Sprintf("%d artists share this name", len(candidates))
+	case hasSlug && x.slugOf[candidates[0]] != "":
+		match.Problem = fmt.Sprintf("%s links another RA page (%s)", x.nameOf[candidates[0]], residentadvisor.ArtistURL(x.slugOf[candidates[0]]))
+	default:
+		match.ArtistID, match.By = candidates[0], MatchedByName
+	}
+	return match
+}
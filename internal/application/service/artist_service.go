@@ -2,28 +2,38 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/blnto/blnto_service/internal/domain/apperror"
 	"github.com/blnto/blnto_service/internal/domain/artist"
 	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/domain/validation"
 	"github.com/blnto/blnto_service/internal/infrastructure/repository"
 	"github.com/google/uuid"
 )
 
 type ArtistService struct {
-	repo *repository.ArtistRepository
+	repo         *repository.ArtistRepository
+	promotedSets *PromotedSetService
 }
 
-func NewArtistService(repo *repository.ArtistRepository) *ArtistService {
-	return &ArtistService{repo: repo}
+func NewArtistService(repo *repository.ArtistRepository, promotedSets *PromotedSetService) *ArtistService {
+	return &ArtistService{repo: repo, promotedSets: promotedSets}
 }
 
 func (s *ArtistService) GetArtist(ctx context.Context, id uuid.UUID) (*artist.Artist, error) {
 	return s.repo.FindByID(ctx, id)
 }
 
+// GetArtists returns a page of artists as stored, with their provider profiles, overrides and timestamps.
+func (s *ArtistService) GetArtists(ctx context.Context, cursor string, limit int) ([]artist.Artist, string, error) {
+	return s.repo.FindAllByCursor(ctx, cursor, limit)
+}
+
 func (s *ArtistService) UpdateArtist(ctx context.Context, gqlArtist *models.Artist) (*models.Artist, error) {
 	// Map GraphQL artist to GORM artist
 	artistModel := mapGqlArtistToGormArtist(gqlArtist)
@@ -54,6 +64,28 @@ func (s *ArtistService) FindByID(ctx context.Context, id uuid.UUID) (*models.Art
 	return mapGormArtistToGqlArtist(artistModel), nil
 }
 
+// FindByIDs returns the artists with the given IDs, skipping IDs that do not exist.
+func (s *ArtistService) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Artist, error) {
+	artists, err := s.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.Artist, 0, len(artists))
+	for i := range artists {
+		result = append(result, mapGormArtistToGqlArtist(&artists[i]))
+	}
+	return result, nil
+}
+
+// FindSocialMediaLinksByArtistIDs returns the social media links of the given artists.
+func (s *ArtistService) FindSocialMediaLinksByArtistIDs(ctx context.Context, artistIDs []uuid.UUID) ([]*models.SocialMedia, error) {
+	links, err := s.repo.FindSocialMediaLinksByArtistIDs(ctx, artistIDs)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormSocialMediaLinksToGql(links), nil
+}
+
 func (s *ArtistService) Search(ctx context.Context, criteria *models.ArtistSearchInput) ([]*models.Artist, string, error) {
 	// Implement your search logic
 	artists, nextCursor, err := s.repo.Search(ctx, criteria)
@@ -136,27 +168,31 @@ func (s *ArtistService) FindAllByCursor(ctx context.Context, cursor string, limi
 	return result, nextCursor, nil
 }
 
-func (s *ArtistService) Save(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
-	gormArtist := s.createGormArtistFromGqlArtist(artist)
+func (s *ArtistService) Save(ctx context.Context, gqlArtist *models.Artist) (*models.Artist, error) {
+	gormArtist := s.createGormArtistFromGqlArtist(gqlArtist)
 
-	// Validate and check uniqueness of permalink
-	if err := s.validateAndCheckPermalink(ctx, artist); err != nil {
+	// Report every invalid field at once, social media links are canonicalized, given their platforms and checked
+	// for duplicates on the way
+	errs := validateArtistInput(gqlArtist, true)
+	if err := errs.Merge("input.socialMedia", artist.NormalizeLinks(gormArtist.SocialMediaLinks)); err != nil {
 		return nil, err
 	}
-	// Set permalink
-	if err := gormArtist.SetPermalink(artist.SoundcloudPermalink); err != nil {
-		return nil, fmt.Errorf("failed to set permalink: %w", err)
+	if err := errs.Err(); err != nil {
+		return nil, err
 	}
 
-	// Validate social media links
-	for _, link := range gormArtist.SocialMediaLinks {
-		err := link.ValidateLinkFormat()
-		if err != nil {
-			return nil, err
-		}
+	// Check uniqueness of permalink
+	if err := s.checkPermalinkAvailable(ctx, gqlArtist); err != nil {
+		return nil, err
+	}
+	// Set permalink
+	if err := gormArtist.SetPermalink(gqlArtist.SoundcloudPermalink); err != nil {
+		return nil, fmt.Errorf("failed to set permalink: %w", err)
 	}
 
-	if err := s.validateSocialMediaPlatforms(gormArtist.SocialMediaLinks); err != nil {
+	// Reject promoted sets that cannot be embedded before anything is stored
+	embed, err := s.resolvePromotedSet(ctx, gormArtist, "")
+	if err != nil {
 		return nil, err
 	}
 
@@ -166,6 +202,10 @@ func (s *ArtistService) Save(ctx context.Context, artist *models.Artist) (*model
 		return nil, fmt.Errorf("failed to save artist: %w", err)
 	}
 
+	if err := s.storePromotedSet(ctx, savedArtist, "", embed); err != nil {
+		return nil, fmt.Errorf("failed to save promoted set: %w", err)
+	}
+
 	return mapGormArtistToGqlArtist(savedArtist), nil
 }
 
@@ -175,8 +215,14 @@ func (s *ArtistService) Delete(ctx context.Context, id uuid.UUID) (bool, error)
 }
 
 func (s *ArtistService) Update(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
-	// Validate and check uniqueness of permalink
-	if err := s.validateAndCheckPermalink(ctx, artist); err != nil {
+	// Report every invalid field at once before anything is fetched
+	errs := validateArtistInput(artist, false)
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	// Check uniqueness of permalink
+	if err := s.checkPermalinkAvailable(ctx, artist); err != nil {
 		return nil, err
 	}
 
@@ -192,23 +238,114 @@ func (s *ArtistService) Update(ctx context.Context, artist *models.Artist) (*mod
 	}
 
 	// Update fields from artist to existingArtist...
+	previousPromotedSet := existingArtist.SCPromotedSet
 	s.updateGormArtistFromGqlArtist(existingArtist, artist)
 
+	// Reject promoted sets that cannot be embedded before anything is stored
+	embed, err := s.resolvePromotedSet(ctx, existingArtist, previousPromotedSet)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enrichable fields set by the editor become manual overrides that sync leaves alone
+	overrides := s.manualOverridesFromGqlArtist(existingArtist, artist)
+
 	// Handle social media links update
 	if err := s.handleSocialMediaLinksUpdate(ctx, existingArtist, artist); err != nil {
 		return nil, fmt.Errorf("failed to update social media links: %w", err)
 	}
 
-	// Save the updated artist
-	updatedArtist, err := s.repo.Update(ctx, existingArtist)
+	// Save the updated artist together with its overrides
+	updatedArtist, err := s.repo.UpdateWithOverrides(ctx, existingArtist, overrides)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.storePromotedSet(ctx, updatedArtist, previousPromotedSet, embed); err != nil {
+		return nil, fmt.Errorf("failed to save promoted set: %w", err)
+	}
+
 	return mapGormArtistToGqlArtist(updatedArtist), nil
 }
 
+// ResetField drops the manual or imported value of a field so the artist shows the provider value again.
+func (s *ArtistService) ResetField(ctx context.Context, id uuid.UUID, field artist.Field) (*models.Artist, error) {
+	if !field.IsValid() {
+		return nil, apperror.Invalid("field", "unknown artist field %q", field)
+	}
+
+	existingArtist, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteFieldOverride(ctx, id, field); err != nil {
+		return nil, fmt.Errorf("failed to reset field %s: %w", field, err)
+	}
+	existingArtist.ClearField(field)
+
+	return mapGormArtistToGqlArtist(existingArtist), nil
+}
+
+// resolvePromotedSet resolves the artist's promoted set when it changed and replaces the URL with the canonical
+// one. It returns nil when there is nothing to resolve.
+func (s *ArtistService) resolvePromotedSet(ctx context.Context, a *artist.Artist, previous string) (*provider.Embed, error) {
+	a.SCPromotedSet = strings.TrimSpace(a.SCPromotedSet)
+	if a.SCPromotedSet == "" || a.SCPromotedSet == previous {
+		return nil, nil
+	}
+
+	embed, err := s.promotedSets.Resolve(ctx, a.ID, a.SCPromotedSet)
+	if err != nil {
+		return nil, err
+	}
+	a.SCPromotedSet = embed.URL
+	return embed, nil
+}
+
+// storePromotedSet saves the resolved promoted set, or removes it when the URL was cleared.
+func (s *ArtistService) storePromotedSet(ctx context.Context, a *artist.Artist, previous string, embed *provider.Embed) error {
+	switch {
+	case embed != nil:
+		return s.promotedSets.Store(ctx, a.ID, embed)
+	case a.SCPromotedSet == "" && previous != "":
+		return s.promotedSets.Clear(ctx, a.ID)
+	}
+	return nil
+}
+
+func (s *ArtistService) manualOverridesFromGqlArtist(gormArtist *artist.Artist, gqlArtist *models.Artist) []artist.FieldOverride {
+	values := []struct {
+		field artist.Field
+		value *string
+	}{
+		{artist.FieldUsername, gqlArtist.Username},
+		{artist.FieldFullName, gqlArtist.FullName},
+		{artist.FieldFirstName, gqlArtist.FirstName},
+		{artist.FieldLastName, gqlArtist.LastName},
+		{artist.FieldAvatarURL, gqlArtist.AvatarURL},
+		{artist.FieldDescription, gqlArtist.Description},
+		{artist.FieldCity, gqlArtist.City},
+		{artist.FieldCountry, gqlArtist.Country},
+	}
+
+	now := time.Now()
+	var overrides []artist.FieldOverride
+	for _, v := range values {
+		if v.value == nil {
+			continue
+		}
+		overrides = append(overrides, gormArtist.SetField(v.field, *v.value, artist.SourceManual, now))
+	}
+	return overrides
+}
+
 func (s *ArtistService) handleSocialMediaLinksUpdate(ctx context.Context, existingArtist *artist.Artist, gqlArtist *models.Artist) error {
+	// Links were not part of the update, keep the current ones
+	if gqlArtist.SocialMediaLinks == nil {
+		return nil
+	}
+
 	// Fetch current social media links from the database for existingArtist
 	currentLinks, err := s.repo.GetSocialMediaLinksByArtistID(ctx, existingArtist.ID)
 	if err != nil {
@@ -221,18 +358,45 @@ func (s *ArtistService) handleSocialMediaLinksUpdate(ctx context.Context, existi
 		currentLinksMap[link.ID] = link
 	}
 
-	// Loop through the provided artist's social media links
-	for _, link := range gqlArtist.SocialMediaLinks {
+	// Build the desired set of links first so it can be normalized and checked for duplicates as a whole
+	desiredLinks := make([]artist.SocialMediaLink, 0, len(gqlArtist.SocialMediaLinks))
+	for i, link := range gqlArtist.SocialMediaLinks {
+		desiredLink := artist.SocialMediaLink{
+			ID:       link.ID,
+			ArtistID: &existingArtist.ID,
+			Platform: artist.SocialMediaPlatform(link.Platform),
+			Link:     link.Link,
+		}
+
+		if link.ID != uuid.Nil {
+			current, ok := currentLinksMap[link.ID]
+			if !ok {
+				return apperror.Invalid(fmt.Sprintf("input.socialMedia.%d.id", i), "social media link %s does not belong to this artist", link.ID)
+			}
+			if desiredLink.Link == "" {
+				desiredLink.Link = current.Link
+			}
+			if desiredLink.Platform == "" && desiredLink.Link == current.Link {
+				desiredLink.Platform = current.Platform
+			}
+		}
+
+		desiredLinks = append(desiredLinks, desiredLink)
+	}
+
+	if err := validation.Prefix("input.socialMedia", artist.NormalizeLinks(desiredLinks)); err != nil {
+		return err
+	}
+
+	for _, link := range desiredLinks {
 		if link.ID == uuid.Nil {
 			// New link, create it
-			err := s.createSocialMediaLink(ctx, existingArtist.ID, link)
-			if err != nil {
+			if err := s.repo.CreateSocialMediaLink(ctx, link); err != nil {
 				return err
 			}
 		} else {
 			// Existing link, update it
-			err := s.updateSocialMediaLink(ctx, link)
-			if err != nil {
+			if err := s.repo.UpdateSocialMediaLink(ctx, link); err != nil {
 				return err
 			}
 			// Remove the link from the map as it's still present
@@ -252,73 +416,59 @@ func (s *ArtistService) handleSocialMediaLinksUpdate(ctx context.Context, existi
 
 }
 
-func (s *ArtistService) createSocialMediaLink(ctx context.Context, artistID uuid.UUID, link *models.SocialMedia) error {
-	// Implementation for creating a new social media link
-	newLink := &artist.SocialMediaLink{
-		ArtistID: artistID,
-		Platform: artist.SocialMediaPlatform(link.Platform),
-		Link:     link.Link,
-	}
-	return s.repo.CreateSocialMediaLink(ctx, *newLink)
-}
-
-func (s *ArtistService) updateSocialMediaLink(ctx context.Context, link *models.SocialMedia) error {
-	// Implementation for updating an existing social media link
-	existingLink := &artist.SocialMediaLink{
-		ID:       link.ID,
-		ArtistID: link.ArtistID,
-		Platform: artist.SocialMediaPlatform(link.Platform),
-		Link:     link.Link,
+// validateArtistInput checks the fields of a created or updated artist that need no lookup. Only created artists
+// require a name, updates keep the stored one when it is omitted.
+func validateArtistInput(a *models.Artist, create bool) *validation.Errors {
+	errs := &validation.Errors{}
+	if create || a.Name != "" {
+		errs.Check(strings.TrimSpace(a.Name) != "", "input.name", "artist name cannot be empty")
+	}
+	if a.SoundcloudPermalink != nil {
+		// Permalinks only fail validation, which Merge collects
+		_, err := artist.NormalizePermalink(*a.SoundcloudPermalink)
+		_ = errs.Merge("input", err)
+	}
+	if a.SoundcloudPromotedSet != nil {
+		promotedSet := strings.TrimSpace(*a.SoundcloudPromotedSet)
+		errs.Check(promotedSet == "" || validation.IsURL(promotedSet), "input.soundcloudPromotedSet", "promoted set %q is not a valid URL", promotedSet)
+	}
+	if a.AvatarURL != nil {
+		errs.Check(*a.AvatarURL == "" || validation.IsURL(*a.AvatarURL), "input.avatarUrl", "avatar URL %q is not a valid URL", *a.AvatarURL)
+	}
+	// Links of updates may be omitted to keep the stored ones, they are normalized once those are known
+	if !create {
+		for i, link := range a.SocialMediaLinks {
+			if link.Link == "" {
+				continue
+			}
+			if _, _, err := artist.CanonicalizeLink(link.Link); err != nil {
+				errs.Add(validation.Path("input.socialMedia", i, "link"), "%v", err)
+			}
+		}
 	}
-	return s.repo.UpdateSocialMediaLink(ctx, *existingLink)
+	return errs
 }
 
-func (s *ArtistService) validateAndCheckPermalink(ctx context.Context, artist *models.Artist) error {
-	if artist.SoundcloudPermalink != nil {
-		if *artist.SoundcloudPermalink == "" {
-			return errors.New("permalink cannot be an empty string")
-		}
+func (s *ArtistService) checkPermalinkAvailable(ctx context.Context, artist *models.Artist) error {
+	if artist.SoundcloudPermalink != nil && *artist.SoundcloudPermalink != "" {
 		exists, err := s.repo.PermalinkExistsExcludingArtist(ctx, *artist.SoundcloudPermalink, artist.ID)
 		if err != nil {
 			return err
 		}
 		if exists {
-			return errors.New("permalink must be unique")
-		}
-	}
-	return nil
-}
-
-func (s *ArtistService) validateSocialMediaPlatforms(links []artist.SocialMediaLink) error {
-	allowedPlatforms := map[artist.SocialMediaPlatform]bool{
-		artist.Twitter:    true,
-		artist.Facebook:   true,
-		artist.Instagram:  true,
-		artist.YouTube:    true,
-		artist.Soundcloud: true,
-	}
-
-	for _, link := range links {
-		if _, ok := allowedPlatforms[link.Platform]; !ok {
-			return fmt.Errorf("platform '%s' is not allowed", link.Platform)
+			return apperror.Conflict("permalink %s is already used by another artist", *artist.SoundcloudPermalink)
 		}
 	}
 	return nil
 }
 
+// mapGqlArtistToGormArtist maps the artist's own fields. Profile fields such as city or avatar come from its
+// external profiles and are never written back to the artist.
 func mapGqlArtistToGormArtist(gqlArtist *models.Artist) *artist.Artist {
 	gqlArtistDto := &artist.Artist{
 		ID:            gqlArtist.ID,
 		Name:          gqlArtist.Name,
 		Location:      *gqlArtist.Location,
-		SCCity:        *gqlArtist.City,
-		SCCountry:     *gqlArtist.Country,
-		SCAvatarURL:   *gqlArtist.AvatarURL,
-		SCFirstName:   *gqlArtist.FirstName,
-		SCLastName:    *gqlArtist.LastName,
-		SCFullName:    *gqlArtist.FullName,
-		SCUsername:    gqlArtist.Username,
-		SCDescription: *gqlArtist.Description,
 		SCPromotedSet: *gqlArtist.SoundcloudPromotedSet,
 	}
 
@@ -359,7 +509,10 @@ func (s *ArtistService) createGormArtistFromGqlArtist(gqlArtist *models.Artist)
 }
 
 func (s *ArtistService) updateGormArtistFromGqlArtist(gormArtist *artist.Artist, gqlArtist *models.Artist) {
-	gormArtist.Name = gqlArtist.Name
+	// An omitted name keeps the stored one
+	if gqlArtist.Name != "" {
+		gormArtist.Name = gqlArtist.Name
+	}
 
 	if gqlArtist.Location != nil {
 		gormArtist.Location = *gqlArtist.Location
@@ -370,49 +523,103 @@ func (s *ArtistService) updateGormArtistFromGqlArtist(gormArtist *artist.Artist,
 	}
 }
 
-func (s *ArtistService) FindFeatured(ctx context.Context) ([]*models.Artist, error) {
-	artists, err := s.repo.FindFeatured(ctx)
-
-	if err != nil {
-		return nil, err
-	}
-
-	var gormArtists []*models.Artist
-
-	for _, artistDto := range artists {
-		gormArtists = append(gormArtists, mapGormArtistToGqlArtist(&artistDto))
-	}
-
-	return gormArtists, nil
-}
-
 func mapGormArtistToGqlArtist(gormArtist *artist.Artist) *models.Artist {
+	profile := gormArtist.DisplayProfile()
 
 	gqlArtist := &models.Artist{
 		ID:                    gormArtist.ID,
 		Name:                  gormArtist.Name,
 		Location:              &gormArtist.Location,
-		City:                  &gormArtist.SCCity,
-		Country:               &gormArtist.SCCountry,
-		AvatarURL:             &gormArtist.SCAvatarURL,
-		FirstName:             &gormArtist.SCFirstName,
-		LastName:              &gormArtist.SCLastName,
-		FullName:              &gormArtist.SCFullName,
-		Username:              gormArtist.SCUsername,
-		Description:           &gormArtist.SCDescription,
+		City:                  &profile.City,
+		Country:               &profile.Country,
+		AvatarURL:             &profile.AvatarURL,
+		FirstName:             &profile.FirstName,
+		LastName:              &profile.LastName,
+		FullName:              &profile.FullName,
+		Description:           &profile.Description,
 		SoundcloudID:          gormArtist.SCID,
 		SoundcloudPromotedSet: &gormArtist.SCPromotedSet,
 		SoundcloudPermalink:   gormArtist.SCPermalink,
 	}
+	if profile.Username != "" {
+		gqlArtist.Username = &profile.Username
+	}
 
-	for _, sm := range gormArtist.SocialMediaLinks {
-		gqlSocialMedia := models.SocialMedia{
-			ID:       sm.ID, // Assuming UUID is used as the ID in GraphQL model
-			Platform: models.SocialMediaPlatform(sm.Platform),
-			Link:     sm.Link,
+	gqlArtist.SocialMediaLinks = mapGormSocialMediaLinksToGql(gormArtist.SocialMediaLinks)
+	gqlArtist.ExternalProfiles = mapGormExternalProfilesToGql(gormArtist.ExternalProfiles)
+	gqlArtist.FieldSources = mapGormFieldSourcesToGql(gormArtist.ResolvedFields())
+
+	return gqlArtist
+}
+
+var gqlArtistFields = map[artist.Field]models.ArtistField{
+	artist.FieldUsername:    models.ArtistFieldUsername,
+	artist.FieldFullName:    models.ArtistFieldFullName,
+	artist.FieldFirstName:   models.ArtistFieldFirstName,
+	artist.FieldLastName:    models.ArtistFieldLastName,
+	artist.FieldAvatarURL:   models.ArtistFieldAvatarURL,
+	artist.FieldDescription: models.ArtistFieldDescription,
+	artist.FieldCity:        models.ArtistFieldCity,
+	artist.FieldCountry:     models.ArtistFieldCountry,
+}
+
+// MapGqlArtistField converts a GraphQL artist field to the domain field.
+func MapGqlArtistField(field models.ArtistField) (artist.Field, bool) {
+	for f, gqlField := range gqlArtistFields {
+		if gqlField == field {
+			return f, true
 		}
-		gqlArtist.SocialMediaLinks = append(gqlArtist.SocialMediaLinks, &gqlSocialMedia)
 	}
+	return "", false
+}
 
-	return gqlArtist
+func mapGormFieldSourcesToGql(values []artist.FieldValue) []*models.ArtistFieldSource {
+	sources := make([]*models.ArtistFieldSource, 0, len(values))
+	for _, v := range values {
+		gqlSource := &models.ArtistFieldSource{
+			Field:     gqlArtistFields[v.Field],
+			UpdatedAt: v.UpdatedAt,
+		}
+		if v.Source != "" {
+			source := string(v.Source)
+			gqlSource.Source = &source
+		}
+		sources = append(sources, gqlSource)
+	}
+	return sources
+}
+
+func mapGormExternalProfilesToGql(profiles []artist.ExternalProfile) []*models.ExternalProfile {
+	gqlProfiles := make([]*models.ExternalProfile, 0, len(profiles))
+	for _, p := range profiles {
+		gqlProfiles = append(gqlProfiles, &models.ExternalProfile{
+			Provider:    string(p.Provider),
+			Priority:    p.Priority,
+			ExternalID:  p.ExternalID,
+			URL:         p.URL,
+			Username:    p.Username,
+			DisplayName: p.DisplayName,
+			AvatarURL:   p.AvatarURL,
+			Description: p.Description,
+			City:        p.City,
+			Country:     p.Country,
+			FetchedAt:   p.FetchedAt,
+		})
+	}
+	return gqlProfiles
+}
+
+func mapGormSocialMediaLinksToGql(links []artist.SocialMediaLink) []*models.SocialMedia {
+	var gqlLinks []*models.SocialMedia
+	for _, sm := range links {
+		gqlSocialMedia := models.SocialMedia{
+			ID:           sm.ID, // Assuming UUID is used as the ID in GraphQL model
+			Platform:     models.SocialMediaPlatform(sm.Platform),
+			Link:         sm.Link,
+			ArtistID:     sm.ArtistID,
+			CollectiveID: sm.CollectiveID,
+		}
+		gqlLinks = append(gqlLinks, &gqlSocialMedia)
+	}
+	return gqlLinks
 }
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/collective"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/domain/validation"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+type CollectiveService struct {
+	repo      *repository.CollectiveRepository
+	eventRepo *repository.EventRepository
+}
+
+func NewCollectiveService(repo *repository.CollectiveRepository, eventRepo *repository.EventRepository) *CollectiveService {
+	return &CollectiveService{repo: repo, eventRepo: eventRepo}
+}
+
+func (s *CollectiveService) FindByID(ctx context.Context, id uuid.UUID) (*models.Collective, error) {
+	collectiveModel, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormCollectiveToGqlCollective(collectiveModel), nil
+}
+
+func (s *CollectiveService) FindAllByCursor(ctx context.Context, cursor string, limit int) ([]*models.Collective, string, error) {
+	collectives, nextCursor, err := s.repo.FindAllByCursor(ctx, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := make([]*models.Collective, len(collectives))
+	for i := range collectives {
+		result[i] = mapGormCollectiveToGqlCollective(&collectives[i])
+	}
+	return result, nextCursor, nil
+}
+
+func (s *CollectiveService) FindByArtistID(ctx context.Context, artistID uuid.UUID) ([]*models.Collective, error) {
+	collectives, err := s.repo.FindByArtistID(ctx, artistID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.Collective, len(collectives))
+	for i := range collectives {
+		result[i] = mapGormCollectiveToGqlCollective(&collectives[i])
+	}
+	return result, nil
+}
+
+func (s *CollectiveService) Save(ctx context.Context, input models.CreateCollectiveInput) (*models.Collective, error) {
+	collectiveModel := &collective.Collective{
+		Name:        input.Name,
+		Description: input.Description,
+		Kind:        collective.Crew,
+	}
+	if input.Kind != nil {
+		collectiveModel.Kind = collective.Kind(*input.Kind)
+	}
+
+	for _, sm := range input.SocialMedia {
+		link := artist.SocialMediaLink{Link: sm.Link}
+		if sm.Platform != nil {
+			link.Platform = artist.SocialMediaPlatform(*sm.Platform)
+		}
+		collectiveModel.SocialMediaLinks = append(collectiveModel.SocialMediaLinks, link)
+	}
+
+	var errs validation.Errors
+	errs.Check(input.Name != "", "input.name", "collective name cannot be empty")
+	if err := errs.Merge("input.socialMedia", artist.NormalizeLinks(collectiveModel.SocialMediaLinks)); err != nil {
+		return nil, err
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	savedCollective, err := s.repo.Save(ctx, collectiveModel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save collective: %w", err)
+	}
+	return mapGormCollectiveToGqlCollective(savedCollective), nil
+}
+
+func (s *CollectiveService) Update(ctx context.Context, input models.UpdateCollectiveInput) (*models.Collective, error) {
+	existingCollective, err := s.repo.FindByID(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		if *input.Name == "" {
+			return nil, apperror.Invalid("input.name", "collective name cannot be empty")
+		}
+		existingCollective.Name = *input.Name
+	}
+	if input.Description != nil {
+		existingCollective.Description = input.Description
+	}
+	if input.Kind != nil {
+		existingCollective.Kind = collective.Kind(*input.Kind)
+	}
+
+	updatedCollective, err := s.repo.Save(ctx, existingCollective)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormCollectiveToGqlCollective(updatedCollective), nil
+}
+
+func (s *CollectiveService) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *CollectiveService) FindMembers(ctx context.Context, collectiveID uuid.UUID) ([]*models.CollectiveMember, error) {
+	members, err := s.repo.FindMembers(ctx, collectiveID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.CollectiveMember, 0, len(members))
+	for _, member := range members {
+		result = append(result, mapGormMembershipToGqlMember(member))
+	}
+	return result, nil
+}
+
+func (s *CollectiveService) AddMember(ctx context.Context, input models.AddCollectiveMemberInput) (*models.CollectiveMember, error) {
+	membership, err := s.repo.AddMember(ctx, &collective.Membership{
+		CollectiveID: input.CollectiveID,
+		ArtistID:     input.ArtistID,
+		Role:         input.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapGormMembershipToGqlMember(membership), nil
+}
+
+func (s *CollectiveService) RemoveMember(ctx context.Context, collectiveID, artistID uuid.UUID) (bool, error) {
+	return s.repo.RemoveMember(ctx, collectiveID, artistID)
+}
+
+func (s *CollectiveService) FindHostedEvents(ctx context.Context, collectiveID uuid.UUID, upcomingOnly bool) ([]*models.Event, error) {
+	events, err := s.repo.FindHostedEvents(ctx, collectiveID, upcomingOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.Event, 0, len(events))
+	for _, eventData := range events {
+		result = append(result, mapGormEventToGqlEvent(eventData))
+	}
+	return result, nil
+}
+
+// SetEventHost assigns a collective as host of an event, or clears the host when collectiveID is nil.
+func (s *CollectiveService) SetEventHost(ctx context.Context, eventID uuid.UUID, collectiveID *uuid.UUID) (*models.Event, error) {
+	if collectiveID != nil {
+		if _, err := s.repo.FindByID(ctx, *collectiveID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.eventRepo.SetHost(ctx, eventID, collectiveID); err != nil {
+		return nil, err
+	}
+
+	eventModel, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormEventToGqlEvent(eventModel), nil
+}
+
+func (s *CollectiveService) FindTakeovers(ctx context.Context, collectiveID uuid.UUID, upcomingOnly bool) ([]*models.StageTakeover, error) {
+	takeovers, err := s.repo.FindTakeovers(ctx, collectiveID, upcomingOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.StageTakeover, 0, len(takeovers))
+	for _, takeover := range takeovers {
+		result = append(result, mapGormTakeoverToGqlTakeover(takeover))
+	}
+	return result, nil
+}
+
+func (s *CollectiveService) CreateTakeover(ctx context.Context, input models.CreateStageTakeoverInput) (*models.StageTakeover, error) {
+	takeover := &collective.Takeover{
+		CollectiveID: input.CollectiveID,
+		StageID:      input.StageID,
+		StartTime:    input.StartTime,
+		EndTime:      input.EndTime,
+	}
+	if err := validation.Prefix("input", takeover.Validate()); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindByID(ctx, input.CollectiveID); err != nil {
+		return nil, err
+	}
+
+	takeover, err := s.repo.CreateTakeover(ctx, takeover)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormTakeoverToGqlTakeover(takeover), nil
+}
+
+func (s *CollectiveService) DeleteTakeover(ctx context.Context, id uuid.UUID) (bool, error) {
+	return s.repo.DeleteTakeover(ctx, id)
+}
+
+func mapGormCollectiveToGqlCollective(gormCollective *collective.Collective) *models.Collective {
+	return &models.Collective{
+		ID:               gormCollective.ID,
+		Name:             gormCollective.Name,
+		Description:      gormCollective.Description,
+		Kind:             models.CollectiveKind(gormCollective.Kind),
+		SocialMediaLinks: mapGormSocialMediaLinksToGql(gormCollective.SocialMediaLinks),
+	}
+}
+
+func mapGormMembershipToGqlMember(membership *collective.Membership) *models.CollectiveMember {
+	member := &models.CollectiveMember{
+		ID:   membership.ID,
+		Role: membership.Role,
+	}
+	if membership.Artist != nil {
+		member.Artist = mapGormArtistToGqlArtist(membership.Artist)
+	}
+	return member
+}
+
+func mapGormTakeoverToGqlTakeover(takeover *collective.Takeover) *models.StageTakeover {
+	gqlTakeover := &models.StageTakeover{
+		ID:           takeover.ID,
+		CollectiveID: takeover.CollectiveID,
+		StartTime:    takeover.StartTime,
+		EndTime:      takeover.EndTime,
+	}
+	if takeover.Stage != nil {
+		gqlTakeover.Stage = mapGormStageToGqlStage(takeover.Stage)
+	}
+	return gqlTakeover
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+)
+
+// EnrichmentService fills the external profiles of artists from the registered metadata providers.
+type EnrichmentService struct {
+	registry    *provider.Registry
+	artistRepo  *repository.ArtistRepository
+	profileRepo *repository.ExternalProfileRepository
+	trackRepo   *repository.TrackRepository
+	popularity  *repository.PopularityRepository
+}
+
+func NewEnrichmentService(registry *provider.Registry, artistRepo *repository.ArtistRepository, profileRepo *repository.ExternalProfileRepository, trackRepo *repository.TrackRepository, popularity *repository.PopularityRepository) *EnrichmentService {
+	return &EnrichmentService{registry: registry, artistRepo: artistRepo, profileRepo: profileRepo, trackRepo: trackRepo, popularity: popularity}
+}
+
+// EnrichArtist fetches the profile of the artist from every provider that supports one of the artist's URLs.
+// A failing provider does not stop the others, all failures are returned together.
+func (s *EnrichmentService) EnrichArtist(ctx context.Context, a *artist.Artist) error {
+	candidates := profileURLs(a)
+
+	var errs []error
+	for _, p := range s.registry.All() {
+		rawURL, ok := firstSupported(p, candidates)
+		if !ok {
+			continue
+		}
+		if err := s.enrichFromProvider(ctx, a, p, rawURL); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PreviewArtist fetches the profiles like EnrichArtist but stores nothing, returning the fields that would change.
+func (s *EnrichmentService) PreviewArtist(ctx context.Context, a *artist.Artist) ([]artist.ProfileChange, error) {
+	candidates := profileURLs(a)
+
+	var changes []artist.ProfileChange
+	var errs []error
+	for _, p := range s.registry.All() {
+		rawURL, ok := firstSupported(p, candidates)
+		if !ok {
+			continue
+		}
+		profile, err := s.fetchProfile(ctx, a, p, rawURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+
+		current := &artist.ExternalProfile{}
+		if stored, ok := a.ProfileFor(p.Name()); ok {
+			current = stored
+		}
+		changes = append(changes, current.Changes(profile)...)
+	}
+	return changes, errors.Join(errs...)
+}
+
+func (s *EnrichmentService) enrichFromProvider(ctx context.Context, a *artist.Artist, p provider.Provider, rawURL string) error {
+	profile, err := s.fetchProfile(ctx, a, p, rawURL)
+	if err != nil {
+		return err
+	}
+	if err := s.profileRepo.Upsert(ctx, profile); err != nil {
+		return fmt.Errorf("error saving profile: %w", err)
+	}
+
+	if p.Name() == provider.SoundCloud {
+		if scID, err := strconv.Atoi(profile.ExternalID); err == nil {
+			if err := s.artistRepo.SetSoundCloudID(ctx, a.ID, scID); err != nil {
+				return fmt.Errorf("error saving soundcloud id: %w", err)
+			}
+		}
+	}
+
+	media, err := s.syncTracks(ctx, a, p, profile.ExternalID)
+	if err != nil {
+		return err
+	}
+
+	snapshot := artist.NewPopularitySnapshot(a.ID, p.Name(), profile.Followers, media, time.Now())
+	if err := s.popularity.SaveSnapshot(ctx, &snapshot); err != nil {
+		return fmt.Errorf("error saving popularity snapshot: %w", err)
+	}
+	return nil
+}
+
+// fetchProfile resolves the URL with the provider and returns the profile it reports, not yet stored.
+func (s *EnrichmentService) fetchProfile(ctx context.Context, a *artist.Artist, p provider.Provider, rawURL string) (*artist.ExternalProfile, error) {
+	externalID, err := p.ResolveURL(ctx, rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving %s: %w", rawURL, err)
+	}
+	fetched, err := p.FetchProfile(ctx, externalID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching profile %s: %w", externalID, err)
+	}
+	if fetched.ExternalID == "" {
+		fetched.ExternalID = externalID
+	}
+	if fetched.URL == "" {
+		fetched.URL = rawURL
+	}
+
+	profile := &artist.ExternalProfile{ArtistID: a.ID, Provider: p.Name(), Priority: p.Priority()}
+	profile.ApplyProfile(fetched, time.Now())
+	return profile, nil
+}
+
+// syncTracks upserts the tracks the provider currently lists and marks the ones it no longer returns as removed.
+// It returns the media fetched, nil when the provider does not list media.
+func (s *EnrichmentService) syncTracks(ctx context.Context, a *artist.Artist, p provider.Provider, externalID string) ([]provider.Media, error) {
+	syncStart := time.Now()
+	media, err := p.FetchMedia(ctx, externalID)
+	if errors.Is(err, provider.ErrNotSupported) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching tracks: %w", err)
+	}
+
+	tracks := make([]artist.Track, 0, len(media))
+	for _, m := range media {
+		if m.Kind != provider.Track || m.ExternalID == "" {
+			continue
+		}
+		tracks = append(tracks, artist.NewTrackFromMedia(a.ID, p.Name(), m, syncStart))
+	}
+
+	if err := s.trackRepo.Upsert(ctx, tracks); err != nil {
+		return nil, fmt.Errorf("error saving tracks: %w", err)
+	}
+	if _, err := s.trackRepo.MarkMissing(ctx, a.ID, p.Name(), syncStart); err != nil {
+		return nil, fmt.Errorf("error marking removed tracks: %w", err)
+	}
+	return media, nil
+}
+
+// profileURLs lists the URLs that may identify the artist on a provider, the SoundCloud permalink first.
+func profileURLs(a *artist.Artist) []string {
+	var urls []string
+	if a.SCPermalink != nil && *a.SCPermalink != "" {
+		permalink := *a.SCPermalink
+		if !strings.Contains(permalink, "://") && !strings.Contains(permalink, "soundcloud.com") {
+			permalink = "https://soundcloud.com/" + strings.TrimPrefix(permalink, "/")
+		} else if !strings.Contains(permalink, "://") {
+			permalink = "https://" + permalink
+		}
+		urls = append(urls, permalink)
+	}
+	for _, link := range a.SocialMediaLinks {
+		urls = append(urls, link.Link)
+	}
+	return urls
+}
+
+func firstSupported(p provider.Provider, urls []string) (string, bool) {
+	for _, u := range urls {
+		if p.Supports(u) {
+			return u, true
+		}
+	}
+	return "", false
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/blnto/blnto_service/internal/domain/event"
 	"github.com/blnto/blnto_service/internal/domain/models"
@@ -23,9 +24,11 @@ func mapGormEventToGqlEvent(gormEvent *event.Event) *models.Event {
 	}
 
 	gqlEvent := &models.Event{
-		ID:        gormEvent.ID,
-		StartDate: gormEvent.StartDate,
-		EndDate:   gormEvent.EndDate,
+		ID:               gormEvent.ID,
+		VenueID:          gormEvent.VenueID,
+		HostCollectiveID: gormEvent.HostCollectiveID,
+		StartDate:        gormEvent.StartDate,
+		EndDate:          gormEvent.EndDate,
 	}
 
 	if gormEvent.Venue != nil {
@@ -46,12 +49,21 @@ func mapGormTimetableEntriesToGql(gormEntries []*event.TimetableEntry) []*models
 			ID:        entry.ID,
 			EventID:   entry.EventID,
 			StageID:   entry.StageID,
-			Stage:     mapGormStageToGqlStage(entry.Stage),
 			ArtistID:  entry.ArtistID,
-			Artist:    mapGormArtistToGqlArtist(entry.Artist),
 			StartTime: &entry.StartTime,
 			EndTime:   &entry.EndTime,
 		}
+
+		// Associations are only mapped when they were preloaded
+		if entry.Event != nil {
+			gqlEntry.Event = mapGormEventToGqlEvent(entry.Event)
+		}
+		if entry.Stage != nil {
+			gqlEntry.Stage = mapGormStageToGqlStage(entry.Stage)
+		}
+		if entry.Artist != nil {
+			gqlEntry.Artist = mapGormArtistToGqlArtist(entry.Artist)
+		}
 		gqlEntries = append(gqlEntries, gqlEntry)
 	}
 	return gqlEntries
@@ -197,3 +209,91 @@ func (s *EventService) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
 	success, err := s.repo.Delete(ctx, id)
 	return success, err
 }
+
+func (s *EventService) FindAppearancesByArtistID(ctx context.Context, artistID uuid.UUID, filter event.AppearanceFilter, cursor string, limit int) ([]*models.TimetableEntry, string, error) {
+	entries, nextCursor, err := s.repo.FindAppearancesByArtistID(ctx, artistID, filter, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	return mapGormTimetableEntriesToGql(entries), nextCursor, nil
+}
+
+func (s *EventService) GetArtistPerformanceStats(ctx context.Context, artistID uuid.UUID) (*models.ArtistPerformanceStats, error) {
+	stats, err := s.repo.GetArtistPerformanceStats(ctx, artistID)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlStats := &models.ArtistPerformanceStats{
+		TotalSets:        stats.TotalSets,
+		TotalHoursPlayed: stats.TotalHoursPlayed,
+		FirstAppearance:  stats.FirstAppearance,
+		LastAppearance:   stats.LastAppearance,
+	}
+	if stats.FavouriteStage != nil {
+		gqlStats.FavouriteStage = mapGormStageToGqlStage(stats.FavouriteStage)
+	}
+
+	return gqlStats, nil
+}
+
+func (s *EventService) FindDebuts(ctx context.Context, from, to time.Time, venueID *uuid.UUID) ([]*models.TimetableEntry, error) {
+	entries, err := s.repo.FindDebuts(ctx, from, to, venueID)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormTimetableEntriesToGql(entries), nil
+}
+
+func (s *EventService) FindByID(ctx context.Context, id uuid.UUID) (*models.Event, error) {
+	eventModel, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormEventToGqlEvent(eventModel), nil
+}
+
+// FindByIDs returns the events with the given IDs, skipping IDs that do not exist.
+func (s *EventService) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Event, error) {
+	events, err := s.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.Event, 0, len(events))
+	for _, e := range events {
+		result = append(result, mapGormEventToGqlEvent(e))
+	}
+	return result, nil
+}
+
+// FindTimetableEntriesByIDs returns the timetable entries with the given IDs, skipping IDs that do not exist.
+func (s *EventService) FindTimetableEntriesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.TimetableEntry, error) {
+	entries, err := s.repo.FindTimetableEntriesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormTimetableEntriesToGql(entries), nil
+}
+
+// FindTimetablesByEventIDs returns the timetable entries of the given events ordered by start time.
+func (s *EventService) FindTimetablesByEventIDs(ctx context.Context, eventIDs []uuid.UUID) ([]*models.TimetableEntry, error) {
+	entries, err := s.repo.FindTimetablesByEventIDs(ctx, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormTimetableEntriesToGql(entries), nil
+}
+
+// GetUpcoming returns up to limit events that have not ended yet as stored, with their venues and timestamps.
+func (s *EventService) GetUpcoming(ctx context.Context, venueID *uuid.UUID, limit int) ([]*event.Event, error) {
+	return s.repo.FindUpcoming(ctx, venueID, limit)
+}
+
+// GetTimetable returns the event as stored, with its venue, its timetable and their timestamps.
+func (s *EventService) GetTimetable(ctx context.Context, id uuid.UUID) (*event.Event, error) {
+	return s.repo.FindWithTimetable(ctx, id)
+}
+
+func (s *EventService) IsDebut(ctx context.Context, entryID uuid.UUID) (bool, error) {
+	return s.repo.IsDebut(ctx, entryID)
+}
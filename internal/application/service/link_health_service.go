@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+type LinkHealthService struct {
+	repo *repository.LinkHealthRepository
+}
+
+func NewLinkHealthService(repo *repository.LinkHealthRepository) *LinkHealthService {
+	return &LinkHealthService{repo: repo}
+}
+
+// FindByLinkID returns the health of a link, or nil if it has not been checked yet.
+func (s *LinkHealthService) FindByLinkID(ctx context.Context, linkID uuid.UUID) (*models.SocialMediaLinkHealth, error) {
+	health, err := s.repo.FindByLinkID(ctx, linkID)
+	if err != nil || health == nil {
+		return nil, err
+	}
+	return mapGormLinkHealthToGql(health), nil
+}
+
+// FindBrokenByCursor returns a page of links currently flagged as broken.
+func (s *LinkHealthService) FindBrokenByCursor(ctx context.Context, cursor string, limit int) ([]*models.SocialMedia, string, error) {
+	results, nextCursor, err := s.repo.FindBroken(ctx, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	links := make([]*models.SocialMedia, 0, len(results))
+	for i := range results {
+		if results[i].Link == nil {
+			continue
+		}
+		gqlLinks := mapGormSocialMediaLinksToGql([]artist.SocialMediaLink{*results[i].Link})
+		gqlLinks[0].Health = mapGormLinkHealthToGql(&results[i])
+		links = append(links, gqlLinks[0])
+	}
+	return links, nextCursor, nil
+}
+
+func mapGormLinkHealthToGql(health *artist.LinkHealth) *models.SocialMediaLinkHealth {
+	return &models.SocialMediaLinkHealth{
+		StatusCode:          health.StatusCode,
+		FinalURL:            health.FinalURL,
+		LastError:           health.LastError,
+		LastCheckedAt:       health.LastCheckedAt,
+		ConsecutiveFailures: health.ConsecutiveFailures,
+		Broken:              health.Broken,
+	}
+}
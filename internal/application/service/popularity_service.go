@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultTrendingLimit = 10
+	maxTrendingLimit     = 50
+)
+
+// FeaturedRules decide which artists getFeaturedArtists returns after the pinned ones.
+type FeaturedRules struct {
+	// Limit is the length of the featured list, pins included
+	Limit int
+	// Window is the trending window the featured artists are ranked by
+	Window time.Duration
+	// MinFollowers skips trending artists with fewer followers
+	MinFollowers int
+	// RequireUpcomingBooking skips trending artists without an upcoming booking
+	RequireUpcomingBooking bool
+	// RequireSoundCloud fills the rest of the list only with artists linked to SoundCloud
+	RequireSoundCloud bool
+}
+
+// PopularityConfig tunes trending scores and the featured list.
+type PopularityConfig struct {
+	Weights artist.TrendingWeights
+	// BookingHorizon is how far ahead a timetable entry counts as an upcoming booking
+	BookingHorizon time.Duration
+	Featured       FeaturedRules
+}
+
+// DefaultPopularityConfig returns the configuration used when nothing else is configured.
+func DefaultPopularityConfig() PopularityConfig {
+	return PopularityConfig{
+		Weights:        artist.DefaultTrendingWeights(),
+		BookingHorizon: 90 * 24 * time.Hour,
+		Featured: FeaturedRules{
+			Limit:             10,
+			Window:            7 * 24 * time.Hour,
+			RequireSoundCloud: true,
+		},
+	}
+}
+
+var gqlTrendingWindows = map[models.TrendingWindow]time.Duration{
+	models.TrendingWindowDay:   24 * time.Hour,
+	models.TrendingWindowWeek:  7 * 24 * time.Hour,
+	models.TrendingWindowMonth: 30 * 24 * time.Hour,
+}
+
+// PopularityService ranks artists by their growth on SoundCloud and builds the featured list.
+type PopularityService struct {
+	repo       *repository.PopularityRepository
+	artistRepo *repository.ArtistRepository
+	config     PopularityConfig
+}
+
+func NewPopularityService(repo *repository.PopularityRepository, artistRepo *repository.ArtistRepository, config PopularityConfig) *PopularityService {
+	return &PopularityService{repo: repo, artistRepo: artistRepo, config: config}
+}
+
+// Trending returns the artists that grew the most within the window, highest score first.
+func (s *PopularityService) Trending(ctx context.Context, window models.TrendingWindow, first *int) ([]*models.TrendingArtist, error) {
+	duration, ok := gqlTrendingWindows[window]
+	if !ok {
+		return nil, apperror.Invalid("window", "invalid trending window %q", window)
+	}
+	limit := defaultTrendingLimit
+	if first != nil && *first > 0 {
+		limit = min(*first, maxTrendingLimit)
+	}
+
+	trends, err := s.trends(ctx, duration, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	trends = trends[:min(limit, len(trends))]
+
+	artists, err := s.findArtists(ctx, trendArtistIDs(trends))
+	if err != nil {
+		return nil, err
+	}
+
+	trending := make([]*models.TrendingArtist, 0, len(trends))
+	for _, t := range trends {
+		a, ok := artists[t.ArtistID]
+		if !ok {
+			continue
+		}
+		trending = append(trending, &models.TrendingArtist{
+			Artist:           mapGormArtistToGqlArtist(a),
+			Score:            t.Score,
+			FollowerGrowth:   t.FollowerGrowth,
+			PlayGrowth:       t.PlayGrowth,
+			Followers:        t.Followers,
+			Plays:            int(t.Plays),
+			UpcomingBookings: t.UpcomingBookings,
+		})
+	}
+	return trending, nil
+}
+
+// FindFeatured returns the pinned artists, then the trending artists that pass the featured rules, then fills the
+// list up to its limit with other artists.
+func (s *PopularityService) FindFeatured(ctx context.Context) ([]*models.Artist, error) {
+	rules := s.config.Featured
+	now := time.Now()
+
+	pins, err := s.repo.FindActivePins(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching featured pins: %w", err)
+	}
+	trends, err := s.trends(ctx, rules.Window, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	add := func(id uuid.UUID) {
+		if len(ids) < rules.Limit && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, pin := range pins {
+		add(pin.ArtistID)
+	}
+	for _, t := range trends {
+		if t.Score <= 0 || t.Followers < rules.MinFollowers || (rules.RequireUpcomingBooking && t.UpcomingBookings == 0) {
+			continue
+		}
+		add(t.ArtistID)
+	}
+
+	artists, err := s.findArtists(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	featured := make([]*models.Artist, 0, rules.Limit)
+	for _, id := range ids {
+		if a, ok := artists[id]; ok {
+			featured = append(featured, mapGormArtistToGqlArtist(a))
+		}
+	}
+
+	if len(featured) < rules.Limit {
+		// ask for enough to make up for the ones already listed
+		fill, err := s.artistRepo.FindFeatured(ctx, rules.RequireSoundCloud, rules.Limit+len(featured))
+		if err != nil {
+			return nil, err
+		}
+		for i := range fill {
+			if len(featured) == rules.Limit {
+				break
+			}
+			if !seen[fill[i].ID] {
+				seen[fill[i].ID] = true
+				featured = append(featured, mapGormArtistToGqlArtist(&fill[i]))
+			}
+		}
+	}
+	return featured, nil
+}
+
+// Pins returns the active featured pins in position order.
+func (s *PopularityService) Pins(ctx context.Context) ([]*models.FeaturedArtistPin, error) {
+	pins, err := s.repo.FindActivePins(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(pins))
+	for _, pin := range pins {
+		ids = append(ids, pin.ArtistID)
+	}
+	artists, err := s.findArtists(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlPins := make([]*models.FeaturedArtistPin, 0, len(pins))
+	for i := range pins {
+		if a, ok := artists[pins[i].ArtistID]; ok {
+			gqlPins = append(gqlPins, mapGormFeaturedPinToGql(&pins[i], a))
+		}
+	}
+	return gqlPins, nil
+}
+
+// Pin puts the artist on the featured list, replacing an existing pin of the same artist.
+func (s *PopularityService) Pin(ctx context.Context, artistID uuid.UUID, position *int, expiresAt *time.Time) (*models.FeaturedArtistPin, error) {
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, apperror.Invalid("expiresAt", "expiresAt must be in the future")
+	}
+	a, err := s.artistRepo.FindByID(ctx, artistID)
+	if err != nil {
+		return nil, err
+	}
+
+	pin := &artist.FeaturedPin{ArtistID: artistID, PinnedAt: now, ExpiresAt: expiresAt}
+	if position != nil {
+		pin.Position = *position
+	}
+	if err := s.repo.SavePin(ctx, pin); err != nil {
+		return nil, err
+	}
+	return mapGormFeaturedPinToGql(pin, a), nil
+}
+
+// Unpin removes the artist from the featured pins and reports whether it was pinned.
+func (s *PopularityService) Unpin(ctx context.Context, artistID uuid.UUID) (bool, error) {
+	return s.repo.DeletePin(ctx, artistID)
+}
+
+// trends scores every artist with SoundCloud snapshots in the window, highest score first.
+func (s *PopularityService) trends(ctx context.Context, window time.Duration, now time.Time) ([]artist.Trend, error) {
+	ranges, err := s.repo.FindSnapshotRanges(ctx, provider.SoundCloud, now.Add(-window))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching popularity snapshots: %w", err)
+	}
+	bookings, err := s.repo.CountUpcomingBookings(ctx, now, now.Add(s.config.BookingHorizon))
+	if err != nil {
+		return nil, fmt.Errorf("error counting upcoming bookings: %w", err)
+	}
+
+	trends := make([]artist.Trend, 0, len(ranges))
+	for _, r := range ranges {
+		trends = append(trends, artist.ComputeTrend(r, bookings[r.ArtistID], s.config.Weights))
+	}
+	sort.SliceStable(trends, func(i, j int) bool {
+		if trends[i].Score != trends[j].Score {
+			return trends[i].Score > trends[j].Score
+		}
+		return trends[i].Followers > trends[j].Followers
+	})
+	return trends, nil
+}
+
+func (s *PopularityService) findArtists(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*artist.Artist, error) {
+	artists, err := s.artistRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching artists: %w", err)
+	}
+	byID := make(map[uuid.UUID]*artist.Artist, len(artists))
+	for i := range artists {
+		byID[artists[i].ID] = &artists[i]
+	}
+	return byID, nil
+}
+
+func trendArtistIDs(trends []artist.Trend) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(trends))
+	for _, t := range trends {
+		ids = append(ids, t.ArtistID)
+	}
+	return ids
+}
+
+func mapGormFeaturedPinToGql(pin *artist.FeaturedPin, a *artist.Artist) *models.FeaturedArtistPin {
+	return &models.FeaturedArtistPin{
+		Artist:    mapGormArtistToGqlArtist(a),
+		Position:  pin.Position,
+		PinnedAt:  pin.PinnedAt,
+		ExpiresAt: pin.ExpiresAt,
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ProfileSuggestionService links artists to discovered provider profiles and lets editors review suggestions.
+type ProfileSuggestionService struct {
+	repo       *repository.ProfileSuggestionRepository
+	artistRepo *repository.ArtistRepository
+	enrichment *EnrichmentService
+	logger     *zap.Logger
+}
+
+func NewProfileSuggestionService(repo *repository.ProfileSuggestionRepository, artistRepo *repository.ArtistRepository, enrichment *EnrichmentService, logger *zap.Logger) *ProfileSuggestionService {
+	return &ProfileSuggestionService{repo: repo, artistRepo: artistRepo, enrichment: enrichment, logger: logger}
+}
+
+// Link points the artist at the suggested profile and fetches it right away. The artist is also marked for the
+// next sync pass, so a failed fetch is not lost.
+func (s *ProfileSuggestionService) Link(ctx context.Context, a *artist.Artist, suggestion *artist.ProfileSuggestion) error {
+	if suggestion.Provider != provider.SoundCloud {
+		return fmt.Errorf("linking %s profiles: %w", suggestion.Provider, provider.ErrNotSupported)
+	}
+
+	permalink, err := artist.NormalizePermalink(suggestion.Permalink())
+	if err != nil {
+		return fmt.Errorf("suggestion %s has no profile URL: %w", suggestion.ID, err)
+	}
+	exists, err := s.artistRepo.PermalinkExistsExcludingArtist(ctx, permalink, a.ID)
+	if err != nil {
+		return fmt.Errorf("error checking permalink: %w", err)
+	}
+	if exists {
+		return apperror.Conflict("permalink %s is already used by another artist", permalink)
+	}
+
+	if err := s.artistRepo.LinkSoundCloudPermalink(ctx, a.ID, permalink); err != nil {
+		return fmt.Errorf("error linking permalink: %w", err)
+	}
+	a.SCPermalink = &permalink
+
+	// A failed fetch is retried by the sync job, linking already succeeded
+	if err := s.enrichment.EnrichArtist(ctx, a); err != nil {
+		s.logger.Warn("failed to fetch linked profile", zap.String("artist", a.ID.String()), zap.Error(err))
+	}
+	return nil
+}
+
+// Accept links the artist to a pending suggestion and rejects the artist's other pending suggestions.
+func (s *ProfileSuggestionService) Accept(ctx context.Context, id uuid.UUID) (*models.ProfileSuggestion, error) {
+	suggestion, err := s.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.artistRepo.FindByID(ctx, suggestion.ArtistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Link(ctx, a, suggestion); err != nil {
+		return nil, err
+	}
+	suggestion.Decide(artist.SuggestionAccepted, time.Now())
+	if err := s.repo.Decide(ctx, suggestion); err != nil {
+		return nil, fmt.Errorf("error saving suggestion: %w", err)
+	}
+	return mapGormProfileSuggestionToGql(suggestion), nil
+}
+
+// Reject marks a pending suggestion as rejected so discovery does not suggest the profile again.
+func (s *ProfileSuggestionService) Reject(ctx context.Context, id uuid.UUID) (*models.ProfileSuggestion, error) {
+	suggestion, err := s.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion.Decide(artist.SuggestionRejected, time.Now())
+	if err := s.repo.Decide(ctx, suggestion); err != nil {
+		return nil, fmt.Errorf("error saving suggestion: %w", err)
+	}
+	return mapGormProfileSuggestionToGql(suggestion), nil
+}
+
+// FindByStatusAndCursor returns a page of suggestions, all of them when status is empty.
+func (s *ProfileSuggestionService) FindByStatusAndCursor(ctx context.Context, status artist.SuggestionStatus, cursor string, limit int) ([]*models.ProfileSuggestion, string, error) {
+	suggestions, nextCursor, err := s.repo.FindByStatusAndCursor(ctx, status, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	gqlSuggestions := make([]*models.ProfileSuggestion, 0, len(suggestions))
+	for i := range suggestions {
+		gqlSuggestions = append(gqlSuggestions, mapGormProfileSuggestionToGql(&suggestions[i]))
+	}
+	return gqlSuggestions, nextCursor, nil
+}
+
+func (s *ProfileSuggestionService) pending(ctx context.Context, id uuid.UUID) (*artist.ProfileSuggestion, error) {
+	suggestion, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if suggestion.Status != artist.SuggestionPending {
+		return nil, apperror.Conflict("profile suggestion is already %s", suggestion.Status)
+	}
+	return suggestion, nil
+}
+
+var gqlSuggestionStatuses = map[artist.SuggestionStatus]models.ProfileSuggestionStatus{
+	artist.SuggestionPending:    models.ProfileSuggestionStatusPending,
+	artist.SuggestionAccepted:   models.ProfileSuggestionStatusAccepted,
+	artist.SuggestionRejected:   models.ProfileSuggestionStatusRejected,
+	artist.SuggestionAutoLinked: models.ProfileSuggestionStatusAutoLinked,
+}
+
+// MapGqlSuggestionStatus converts a GraphQL suggestion status to the domain status.
+func MapGqlSuggestionStatus(status models.ProfileSuggestionStatus) (artist.SuggestionStatus, bool) {
+	for s, gqlStatus := range gqlSuggestionStatuses {
+		if gqlStatus == status {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+func mapGormProfileSuggestionToGql(s *artist.ProfileSuggestion) *models.ProfileSuggestion {
+	return &models.ProfileSuggestion{
+		ID:              s.ID,
+		ArtistID:        s.ArtistID,
+		Provider:        string(s.Provider),
+		ExternalID:      s.ExternalID,
+		URL:             s.URL,
+		Username:        s.Username,
+		DisplayName:     s.DisplayName,
+		AvatarURL:       s.AvatarURL,
+		City:            s.City,
+		Country:         s.Country,
+		Followers:       s.Followers,
+		Score:           s.Score,
+		NameScore:       s.NameScore,
+		LocationScore:   s.LocationScore,
+		FollowerScore:   s.FollowerScore,
+		LinkedElsewhere: s.LinkedElsewhere,
+		Status:          gqlSuggestionStatuses[s.Status],
+		CreatedAt:       s.CreatedAt,
+		DecidedAt:       s.DecidedAt,
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+// PromotedSetService resolves promoted set URLs to embeddable players and stores the result.
+type PromotedSetService struct {
+	repo     *repository.PromotedSetRepository
+	embedder provider.Embedder
+	accounts provider.AccountTokens
+}
+
+func NewPromotedSetService(repo *repository.PromotedSetRepository, embedder provider.Embedder, accounts provider.AccountTokens) *PromotedSetService {
+	return &PromotedSetService{repo: repo, embedder: embedder, accounts: accounts}
+}
+
+// Resolve validates a promoted set URL and fetches its player, with the artist's connected account when there is
+// one so private share links resolve. The returned errors are meant for the editor.
+func (s *PromotedSetService) Resolve(ctx context.Context, artistID uuid.UUID, rawURL string) (*provider.Embed, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, apperror.Invalid("input.soundcloudPromotedSet", "promoted set %q must be an absolute http(s) URL", rawURL)
+	}
+
+	embed, err := provider.ResolveEmbedFor(ctx, s.embedder, s.accounts, artistID, u.String())
+	switch {
+	case errors.Is(err, provider.ErrNotSupported):
+		return nil, apperror.Invalid("input.soundcloudPromotedSet", "promoted set %q is not a %s track or set URL", rawURL, s.embedder.Name())
+	case errors.Is(err, provider.ErrNotFound):
+		return nil, apperror.Invalid("input.soundcloudPromotedSet", "promoted set %q was not found on %s", rawURL, s.embedder.Name())
+	case err != nil:
+		return nil, apperror.Upstream(err, "promoted set %q could not be resolved", rawURL)
+	}
+	return embed, nil
+}
+
+// Store saves a resolved player as the artist's promoted set.
+func (s *PromotedSetService) Store(ctx context.Context, artistID uuid.UUID, embed *provider.Embed) error {
+	return s.repo.Save(ctx, artist.NewPromotedSet(artistID, s.embedder.Name(), embed, time.Now()))
+}
+
+// Clear removes the artist's promoted set.
+func (s *PromotedSetService) Clear(ctx context.Context, artistID uuid.UUID) error {
+	return s.repo.DeleteByArtistID(ctx, artistID)
+}
+
+// FindByArtistIDs returns the resolved promoted sets of the artists by artist ID, skipping artists without one.
+func (s *PromotedSetService) FindByArtistIDs(ctx context.Context, artistIDs []uuid.UUID) (map[uuid.UUID]*models.PromotedSet, error) {
+	sets, err := s.repo.FindByArtistIDs(ctx, artistIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uuid.UUID]*models.PromotedSet, len(sets))
+	for i := range sets {
+		result[sets[i].ArtistID] = mapGormPromotedSetToGql(&sets[i])
+	}
+	return result, nil
+}
+
+func mapGormPromotedSetToGql(set *artist.PromotedSet) *models.PromotedSet {
+	gqlSet := &models.PromotedSet{
+		URL:           set.URL,
+		ResolvedAt:    set.ResolvedAt,
+		LastCheckedAt: set.LastCheckedAt,
+		LastError:     set.LastError,
+		Broken:        set.Broken,
+	}
+	if set.ResolvedAt != nil {
+		gqlSet.Title = &set.Title
+		gqlSet.ThumbnailURL = &set.ThumbnailURL
+		gqlSet.AuthorName = &set.AuthorName
+		gqlSet.DurationMs = &set.DurationMs
+		gqlSet.EmbedHTML = &set.EmbedHTML
+	}
+	return gqlSet
+}
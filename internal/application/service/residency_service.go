@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/domain/validation"
+	"github.com/blnto/blnto_service/internal/domain/venue"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+type ResidencyService struct {
+	repo *repository.ResidencyRepository
+}
+
+func NewResidencyService(repo *repository.ResidencyRepository) *ResidencyService {
+	return &ResidencyService{repo: repo}
+}
+
+func (s *ResidencyService) Create(ctx context.Context, input models.CreateResidencyInput) (*models.Residency, error) {
+	residency := &venue.Residency{
+		ArtistID:  input.ArtistID,
+		VenueID:   input.VenueID,
+		StartDate: input.StartDate,
+		EndDate:   input.EndDate,
+	}
+	if err := validation.Prefix("input", residency.Validate()); err != nil {
+		return nil, err
+	}
+
+	savedResidency, err := s.repo.Save(ctx, residency)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormResidencyToGqlResidency(savedResidency), nil
+}
+
+func (s *ResidencyService) End(ctx context.Context, id uuid.UUID, endDate time.Time) (*models.Residency, error) {
+	residency, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validation.Prefix("input", residency.End(endDate)); err != nil {
+		return nil, err
+	}
+
+	updatedResidency, err := s.repo.Save(ctx, residency)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormResidencyToGqlResidency(updatedResidency), nil
+}
+
+func (s *ResidencyService) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *ResidencyService) FindByArtistID(ctx context.Context, artistID uuid.UUID) ([]*models.Residency, error) {
+	residencies, err := s.repo.FindByArtistID(ctx, artistID)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormResidenciesToGql(residencies), nil
+}
+
+func (s *ResidencyService) FindByVenueID(ctx context.Context, venueID uuid.UUID, includePast bool) ([]*models.Residency, error) {
+	residencies, err := s.repo.FindByVenueID(ctx, venueID, includePast)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormResidenciesToGql(residencies), nil
+}
+
+// FindActiveForTimetableEntries returns the residencies covering the entries' sets by entry ID, leaving out entries
+// of artists that were not residents.
+func (s *ResidencyService) FindActiveForTimetableEntries(ctx context.Context, entryIDs []uuid.UUID) (map[uuid.UUID]*models.Residency, error) {
+	residencies, err := s.repo.FindActiveForTimetableEntries(ctx, entryIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uuid.UUID]*models.Residency, len(residencies))
+	for entryID, residency := range residencies {
+		result[entryID] = mapGormResidencyToGqlResidency(residency)
+	}
+	return result, nil
+}
+
+func mapGormResidenciesToGql(gormResidencies []*venue.Residency) []*models.Residency {
+	result := make([]*models.Residency, 0, len(gormResidencies))
+	for _, residency := range gormResidencies {
+		result = append(result, mapGormResidencyToGqlResidency(residency))
+	}
+	return result
+}
+
+func mapGormResidencyToGqlResidency(gormResidency *venue.Residency) *models.Residency {
+	return &models.Residency{
+		ID:        gormResidency.ID,
+		ArtistID:  gormResidency.ArtistID,
+		VenueID:   gormResidency.VenueID,
+		StartDate: gormResidency.StartDate,
+		EndDate:   gormResidency.EndDate,
+		IsActive:  gormResidency.IsActiveAt(time.Now()),
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+// SoundCloudAccountService connects artists to their own SoundCloud accounts.
+type SoundCloudAccountService struct {
+	connector  *artistApi.AccountConnector
+	artistRepo *repository.ArtistRepository
+}
+
+func NewSoundCloudAccountService(connector *artistApi.AccountConnector, artistRepo *repository.ArtistRepository) *SoundCloudAccountService {
+	return &SoundCloudAccountService{connector: connector, artistRepo: artistRepo}
+}
+
+// Start begins connecting the artist's account and returns the SoundCloud URL the artist has to visit.
+func (s *SoundCloudAccountService) Start(ctx context.Context, artistID uuid.UUID) (string, error) {
+	if _, err := s.artistRepo.FindByID(ctx, artistID); err != nil {
+		return "", fmt.Errorf("error fetching artist: %w", err)
+	}
+	return s.connector.Start(ctx, artistID)
+}
+
+// Complete finishes connecting an account with the state and code SoundCloud sent back, and returns the ID of
+// the artist the account was connected to.
+func (s *SoundCloudAccountService) Complete(ctx context.Context, state, code string) (uuid.UUID, error) {
+	account, err := s.connector.Complete(ctx, state, code)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return account.ArtistID, nil
+}
+
+// FindByArtistID returns the account the artist connected, or nil if there is none.
+func (s *SoundCloudAccountService) FindByArtistID(ctx context.Context, artistID uuid.UUID) (*models.SoundCloudAccount, error) {
+	account, err := s.connector.Account(ctx, artistID)
+	if err != nil || account == nil {
+		return nil, err
+	}
+	return mapSoundCloudAccountToGql(account), nil
+}
+
+// Disconnect forgets the artist's account and reports whether one was connected.
+func (s *SoundCloudAccountService) Disconnect(ctx context.Context, artistID uuid.UUID) (bool, error) {
+	return s.connector.Disconnect(ctx, artistID)
+}
+
+func mapSoundCloudAccountToGql(account *artistApi.AccountToken) *models.SoundCloudAccount {
+	return &models.SoundCloudAccount{
+		UserID:      account.UserID,
+		Permalink:   account.Permalink,
+		URL:         "https://soundcloud.com/" + account.Permalink,
+		Scope:       account.Scope,
+		ConnectedAt: account.ConnectedAt,
+	}
+}
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"context"
+
 	"github.com/blnto/blnto_service/internal/domain/models"
 	"github.com/blnto/blnto_service/internal/domain/stage"
 	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
 )
 
 type StageService struct {
@@ -14,10 +17,37 @@ func NewStageService(repo *repository.StageRepository) *StageService {
 	return &StageService{repo: repo}
 }
 
+// FindByIDs returns the stages with the given IDs, skipping IDs that do not exist.
+func (s *StageService) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Stage, error) {
+	stages, err := s.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormStagesToGql(stages), nil
+}
+
+// FindByVenueIDs returns the stages of the given venues.
+func (s *StageService) FindByVenueIDs(ctx context.Context, venueIDs []uuid.UUID) ([]*models.Stage, error) {
+	stages, err := s.repo.FindByVenueIDs(ctx, venueIDs)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormStagesToGql(stages), nil
+}
+
+func mapGormStagesToGql(gormStages []stage.Stage) []*models.Stage {
+	gqlStages := make([]*models.Stage, 0, len(gormStages))
+	for i := range gormStages {
+		gqlStages = append(gqlStages, mapGormStageToGqlStage(&gormStages[i]))
+	}
+	return gqlStages
+}
+
 func mapGormStageToGqlStage(gormStage *stage.Stage) *models.Stage {
 	gqlStage := &models.Stage{
-		ID:   gormStage.ID,
-		Name: gormStage.StageName,
+		ID:      gormStage.ID,
+		Name:    gormStage.StageName,
+		VenueID: gormStage.VenueID,
 	}
 
 	return gqlStage
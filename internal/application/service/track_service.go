@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+type TrackService struct {
+	repo *repository.TrackRepository
+}
+
+func NewTrackService(repo *repository.TrackRepository) *TrackService {
+	return &TrackService{repo: repo}
+}
+
+// FindByArtistID returns a page of the tracks the artist currently has published.
+func (s *TrackService) FindByArtistID(ctx context.Context, artistID uuid.UUID, cursor string, limit int) ([]*models.Track, string, error) {
+	tracks, nextCursor, err := s.repo.FindByArtistID(ctx, artistID, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	gqlTracks := make([]*models.Track, 0, len(tracks))
+	for i := range tracks {
+		gqlTracks = append(gqlTracks, mapGormTrackToGqlTrack(&tracks[i]))
+	}
+	return gqlTracks, nextCursor, nil
+}
+
+func mapGormTrackToGqlTrack(track *artist.Track) *models.Track {
+	return &models.Track{
+		ID:            track.ID,
+		Provider:      string(track.Provider),
+		ExternalID:    track.ExternalID,
+		Title:         track.Title,
+		DurationMs:    track.DurationMs,
+		ArtworkURL:    &track.ArtworkURL,
+		Genre:         &track.Genre,
+		PermalinkURL:  track.PermalinkURL,
+		PlaybackCount: track.PlaybackCount,
+		LikesCount:    track.LikesCount,
+		CommentCount:  track.CommentCount,
+		RepostsCount:  track.RepostsCount,
+		PublishedAt:   track.PublishedAt,
+	}
+}
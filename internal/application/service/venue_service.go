@@ -61,6 +61,37 @@ func (s *VenueService) FindAllByCursor(ctx context.Context, cursor string, limit
 	return result, nextCursor, nil
 }
 
+func (s *VenueService) FindByID(ctx context.Context, id uuid.UUID) (*models.Venue, error) {
+	venueModel, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapGormVenueToGqlVenue(venueModel), nil
+}
+
+// FindByIDs returns the venues with the given IDs, skipping IDs that do not exist.
+func (s *VenueService) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Venue, error) {
+	venues, err := s.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.Venue, 0, len(venues))
+	for i := range venues {
+		result = append(result, mapGormVenueToGqlVenue(&venues[i]))
+	}
+	return result, nil
+}
+
+// GetVenues returns a page of venues as stored, with their timestamps.
+func (s *VenueService) GetVenues(ctx context.Context, cursor string, limit int) ([]venue.Venue, string, error) {
+	return s.repo.FindAllByCursor(ctx, cursor, limit)
+}
+
+// GetVenue returns the venue as stored, with its stages and timestamps.
+func (s *VenueService) GetVenue(ctx context.Context, id uuid.UUID) (*venue.Venue, error) {
+	return s.repo.FindByIDWithStages(ctx, id)
+}
+
 func (s *VenueService) Save(ctx context.Context, gqlVenue *models.Venue) (*models.Venue, error) {
 	gormVenue := mapGqlVenueToGormVenue(gqlVenue)
 	savedVenue, err := s.repo.Save(ctx, gormVenue)
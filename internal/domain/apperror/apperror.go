@@ -0,0 +1,147 @@
+// Package apperror classifies the errors services return to clients. Repositories and services return an *Error for
+// failures the client can act on; the GraphQL layer reports its Kind as extensions.code and keeps other errors
+// internal.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kind classifies an error for clients, its value is the code the API reports
+type Kind string
+
+const (
+	// KindNotFound means the requested object does not exist
+	KindNotFound Kind = "NOT_FOUND"
+	// KindValidation means the input was rejected, Fields tells which parts of it
+	KindValidation Kind = "VALIDATION_FAILED"
+	// KindConflict means the request contradicts the current state, such as a value another object already holds
+	KindConflict Kind = "CONFLICT"
+	// KindUnauthorized means the caller may not perform the request
+	KindUnauthorized Kind = "UNAUTHORIZED"
+	// KindUpstream means an external service such as SoundCloud failed, retrying later may help
+	KindUpstream Kind = "UPSTREAM_ERROR"
+	// KindNotImplemented means the operation is part of the schema but not available yet
+	KindNotImplemented Kind = "NOT_IMPLEMENTED"
+	// KindInternal is reported for unexpected failures and for errors without a kind
+	KindInternal Kind = "INTERNAL_SERVER_ERROR"
+)
+
+// Sentinel errors matching an Error of the same kind with errors.Is
+var (
+	ErrNotFound       = errors.New("not found")
+	ErrValidation     = errors.New("validation failed")
+	ErrConflict       = errors.New("conflict")
+	ErrUnauthorized   = errors.New("unauthorized")
+	ErrUpstream       = errors.New("upstream failure")
+	ErrNotImplemented = errors.New("not implemented")
+	ErrInternal       = errors.New("internal error")
+)
+
+// FieldError is the reason one input field was rejected.
+type FieldError struct {
+	// Field is the path of the field from the argument, such as "input.socialMedia.1.link"
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is an error with a Kind.
+type Error struct {
+	Kind    Kind
+	Message string
+	// Fields lists the rejected input fields of validation errors
+	Fields []FieldError
+	// Err is the cause, it is logged but not shown to clients
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the sentinel of the error's kind
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Kind == KindNotFound
+	case ErrValidation:
+		return e.Kind == KindValidation
+	case ErrConflict:
+		return e.Kind == KindConflict
+	case ErrUnauthorized:
+		return e.Kind == KindUnauthorized
+	case ErrUpstream:
+		return e.Kind == KindUpstream
+	case ErrNotImplemented:
+		return e.Kind == KindNotImplemented
+	case ErrInternal:
+		return e.Kind == KindInternal
+	}
+	return false
+}
+
+// NotFound returns the error for a missing object, e.g. NotFound("venue", id). A nil id leaves it out of the message.
+func NotFound(resource string, id interface{}) *Error {
+	if id == nil {
+		return &Error{Kind: KindNotFound, Message: resource + " not found"}
+	}
+	return &Error{Kind: KindNotFound, Message: fmt.Sprintf("%s %v not found", resource, id)}
+}
+
+// Invalid returns the validation error of a single field.
+func Invalid(field string, format string, args ...interface{}) *Error {
+	return Validation(FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validation returns the validation error of all rejected fields, so clients can show them at once.
+func Validation(fields ...FieldError) *Error {
+	messages := make([]string, len(fields))
+	for i, f := range fields {
+		messages[i] = f.Message
+	}
+	return &Error{Kind: KindValidation, Message: strings.Join(messages, "; "), Fields: fields}
+}
+
+// Conflict returns the error for a request that contradicts the current state.
+func Conflict(format string, args ...interface{}) *Error {
+	return &Error{Kind: KindConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// Unauthorized returns the error for a request the caller may not perform.
+func Unauthorized(format string, args ...interface{}) *Error {
+	return &Error{Kind: KindUnauthorized, Message: fmt.Sprintf(format, args...)}
+}
+
+// Upstream returns the error for a failed call to an external service.
+func Upstream(err error, format string, args ...interface{}) *Error {
+	return &Error{Kind: KindUpstream, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// NotImplemented returns the error for an operation of the schema that is not available yet, e.g.
+// NotImplemented("createStage").
+func NotImplemented(operation string) *Error {
+	return &Error{Kind: KindNotImplemented, Message: operation + " is not implemented"}
+}
+
+// Internal returns the error reported for unexpected failures, err is kept for the logs.
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Message: "internal server error", Err: err}
+}
+
+// KindOf returns the kind of the first Error in err's chain, KindInternal if there is none.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindInternal
+}
@@ -1,21 +1,29 @@
 package artist
 
 import (
-	"errors"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
-	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-// Artist represents an artist record in the database
+// Artist represents an artist record in the database.
+//
+// The SC* profile columns (sc_city through sc_country) are deprecated: ExternalProfiles is the source of truth and
+// nothing writes them anymore. They are only read by the external profile backfill and will be dropped once every
+// deployment has run it.
 type Artist struct {
 	ID               uuid.UUID         `gorm:"type:uuid;primaryKey;" json:"id"`
 	Name             string            `gorm:"type:varchar(100);not null;" json:"name"`
 	Location         string            `gorm:"type:varchar(100);" json:"location"`
 	SCPromotedSet    string            `gorm:"type:text;column:sc_promoted_set" json:"soundcloudPromotedSet"`
 	SocialMediaLinks []SocialMediaLink `gorm:"foreignKey:ArtistID" json:"SocialMediaLinks"`
+	ExternalProfiles []ExternalProfile `gorm:"foreignKey:ArtistID" json:"externalProfiles,omitempty"`
+	FieldOverrides   []FieldOverride   `gorm:"foreignKey:ArtistID" json:"fieldOverrides,omitempty"`
 	SCID             *int              `gorm:"column:sc_id;unique"`
 	CreatedAt        time.Time         `json:"-"`
 	UpdatedAt        time.Time         `json:"-"`
@@ -29,6 +37,8 @@ type Artist struct {
 	SCDescription    string            `gorm:"type:text;gorm:column:sc_description"`
 	SCCountry        string            `gorm:"column:sc_country"`
 	SCPermalink      *string           `gorm:"column:sc_permalink;unique"`
+	LastSyncedAt     *time.Time        `gorm:"index" json:"lastSyncedAt,omitempty"`
+	LastDiscoveryAt  *time.Time        `gorm:"index" json:"lastDiscoveryAt,omitempty"`
 }
 
 // BeforeCreate will set a UUID rather than numeric ID.
@@ -37,20 +47,44 @@ func (a *Artist) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
-func (a *Artist) UpdateWithSoundCloudInfo(scArtist artistApi.SCArtist) {
-	a.SCUsername = &scArtist.Username
-	a.SCFullName = scArtist.FullName
-	a.SCAvatarURL = scArtist.AvatarURL
-	a.SCCity = scArtist.City
-	a.SCCountry = scArtist.Country
-	a.SCDescription = scArtist.Description
-	a.SCID = &scArtist.ID
-}
-
+// SetPermalink sets the SoundCloud permalink in its normalized form, nil unlinks the artist.
 func (a *Artist) SetPermalink(permalink *string) error {
-	if permalink != nil && *permalink == "" {
-		return errors.New("permalink cannot be an empty string")
+	if permalink == nil {
+		a.SCPermalink = nil
+		return nil
+	}
+	normalized, err := NormalizePermalink(*permalink)
+	if err != nil {
+		return err
 	}
-	a.SCPermalink = permalink
+	a.SCPermalink = &normalized
 	return nil
 }
+
+// permalinkPattern matches the characters SoundCloud allows in profile permalinks
+var permalinkPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// NormalizePermalink returns the lowercased permalink of a SoundCloud profile given by its permalink or its
+// soundcloud.com URL, e.g. "Some-Artist" or "https://soundcloud.com/some-artist/". Artists store permalinks in this
+// form, so every write and uniqueness check passes them through here first.
+func NormalizePermalink(raw string) (string, error) {
+	permalink := strings.TrimSpace(raw)
+	if permalink == "" {
+		return "", apperror.Invalid("soundcloudPermalink", "permalink cannot be an empty string")
+	}
+	if strings.Contains(permalink, "/") {
+		if !strings.Contains(permalink, "://") {
+			permalink = "https://" + permalink
+		}
+		u, err := url.Parse(permalink)
+		if err != nil || canonicalHost(u.Hostname()) != "soundcloud.com" {
+			return "", apperror.Invalid("soundcloudPermalink", "permalink %q is not a SoundCloud profile", raw)
+		}
+		permalink = strings.Trim(u.Path, "/")
+	}
+	permalink = strings.ToLower(permalink)
+	if !permalinkPattern.MatchString(permalink) {
+		return "", apperror.Invalid("soundcloudPermalink", "permalink %q is not a SoundCloud profile", raw)
+	}
+	return permalink, nil
+}
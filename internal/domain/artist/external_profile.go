@@ -0,0 +1,149 @@
+package artist
+
+import (
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExternalProfile is the profile of an artist on an external metadata provider. Each artist has at most one profile
+// per provider. Priority is copied from the provider when the profile is synced.
+type ExternalProfile struct {
+	ID          uuid.UUID     `gorm:"type:uuid;primaryKey;" json:"id"`
+	ArtistID    uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_external_profiles_artist_provider,priority:1" json:"artistID"`
+	Provider    provider.Name `gorm:"type:varchar(50);not null;uniqueIndex:idx_external_profiles_artist_provider,priority:2;index:idx_external_profiles_provider_external_id,priority:1" json:"provider"`
+	Priority    int           `gorm:"not null;default:0" json:"priority"`
+	ExternalID  string        `gorm:"type:varchar(255);not null;index:idx_external_profiles_provider_external_id,priority:2" json:"externalID"`
+	URL         string        `gorm:"type:text" json:"url"`
+	Username    string        `gorm:"type:varchar(255)" json:"username"`
+	DisplayName string        `gorm:"type:varchar(255)" json:"displayName"`
+	FirstName   string        `gorm:"type:varchar(255)" json:"firstName"`
+	LastName    string        `gorm:"type:varchar(255)" json:"lastName"`
+	AvatarURL   string        `gorm:"type:text" json:"avatarUrl"`
+	Description string        `gorm:"type:text" json:"description"`
+	City        string        `gorm:"type:varchar(255)" json:"city"`
+	Country     string        `gorm:"type:varchar(255)" json:"country"`
+	Followers   int           `gorm:"not null;default:0" json:"followers"`
+	FetchedAt   time.Time     `json:"fetchedAt"`
+	CreatedAt   time.Time     `json:"-"`
+	UpdatedAt   time.Time     `json:"-"`
+}
+
+// TableName overrides the table name used by GORM
+func (ExternalProfile) TableName() string {
+	return "artist_external_profiles"
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (p *ExternalProfile) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
+
+// ApplyProfile copies the provider data onto the stored profile.
+func (p *ExternalProfile) ApplyProfile(profile *provider.Profile, fetchedAt time.Time) {
+	p.ExternalID = profile.ExternalID
+	p.URL = profile.URL
+	p.Username = profile.Username
+	p.DisplayName = profile.DisplayName
+	p.FirstName = profile.FirstName
+	p.LastName = profile.LastName
+	p.AvatarURL = profile.AvatarURL
+	p.Description = profile.Description
+	p.City = profile.City
+	p.Country = profile.Country
+	p.Followers = profile.Followers
+	p.FetchedAt = fetchedAt
+}
+
+// ProfileChange is a field of an external profile that a sync would change.
+type ProfileChange struct {
+	Provider provider.Name
+	Field    string
+	Old      string
+	New      string
+}
+
+// Changes lists the fields that differ between the stored profile and an updated one.
+func (p *ExternalProfile) Changes(updated *ExternalProfile) []ProfileChange {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"externalId", p.ExternalID, updated.ExternalID},
+		{"url", p.URL, updated.URL},
+		{"username", p.Username, updated.Username},
+		{"displayName", p.DisplayName, updated.DisplayName},
+		{"firstName", p.FirstName, updated.FirstName},
+		{"lastName", p.LastName, updated.LastName},
+		{"avatarUrl", p.AvatarURL, updated.AvatarURL},
+		{"description", p.Description, updated.Description},
+		{"city", p.City, updated.City},
+		{"country", p.Country, updated.Country},
+	}
+
+	var changes []ProfileChange
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, ProfileChange{Provider: updated.Provider, Field: f.name, Old: f.old, New: f.new})
+		}
+	}
+	return changes
+}
+
+// ResolvedProfile holds the display fields of an artist, each taken from the highest-priority provider with data.
+type ResolvedProfile struct {
+	Username    string
+	FullName    string
+	FirstName   string
+	LastName    string
+	AvatarURL   string
+	Description string
+	City        string
+	Country     string
+}
+
+// PreferredProfile resolves the display fields from the loaded external profiles, ignoring overrides.
+func (a *Artist) PreferredProfile() ResolvedProfile {
+	profiles := a.sortedProfiles()
+	return resolvedProfile(func(field Field) FieldValue {
+		return a.providerField(field, profiles)
+	})
+}
+
+// FieldValue returns the profile's value for an enrichable field.
+func (p *ExternalProfile) FieldValue(field Field) string {
+	switch field {
+	case FieldUsername:
+		return p.Username
+	case FieldFullName:
+		return p.DisplayName
+	case FieldFirstName:
+		return p.FirstName
+	case FieldLastName:
+		return p.LastName
+	case FieldAvatarURL:
+		return p.AvatarURL
+	case FieldDescription:
+		return p.Description
+	case FieldCity:
+		return p.City
+	case FieldCountry:
+		return p.Country
+	}
+	return ""
+}
+
+// ProfileFor returns the loaded external profile of a provider.
+func (a *Artist) ProfileFor(name provider.Name) (*ExternalProfile, bool) {
+	for i := range a.ExternalProfiles {
+		if a.ExternalProfiles[i].Provider == name {
+			return &a.ExternalProfiles[i], true
+		}
+	}
+	return nil, false
+}
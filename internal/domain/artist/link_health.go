@@ -0,0 +1,79 @@
+package artist
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LinkHealth records the outcome of the periodic reachability checks of a SocialMediaLink.
+type LinkHealth struct {
+	LinkID              uuid.UUID        `gorm:"type:uuid;primaryKey" json:"linkID"`
+	Link                *SocialMediaLink `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+	StatusCode          *int             `json:"statusCode,omitempty"`
+	FinalURL            *string          `gorm:"type:text" json:"finalUrl,omitempty"`
+	LastError           *string          `gorm:"type:text" json:"lastError,omitempty"`
+	LastCheckedAt       *time.Time       `gorm:"index" json:"lastCheckedAt,omitempty"`
+	ConsecutiveFailures int              `gorm:"not null;default:0" json:"consecutiveFailures"`
+	Broken              bool             `gorm:"not null;default:false;index" json:"broken"`
+	CreatedAt           time.Time        `json:"-"`
+	UpdatedAt           time.Time        `json:"-"`
+}
+
+// TableName overrides the table name used by GORM
+func (LinkHealth) TableName() string {
+	return "social_media_link_health"
+}
+
+// CheckResult is the outcome of a single request to a link.
+type CheckResult struct {
+	StatusCode int
+	FinalURL   string
+	Err        error
+	CheckedAt  time.Time
+}
+
+// Failed reports whether the check counts towards the link being broken.
+// Rate limited responses are inconclusive and neither count as failure nor success.
+func (r CheckResult) Failed() bool {
+	return r.Err != nil || (r.StatusCode >= 400 && !r.Inconclusive())
+}
+
+// Inconclusive reports whether the check says nothing about the link's health.
+func (r CheckResult) Inconclusive() bool {
+	return r.Err == nil && r.StatusCode == http.StatusTooManyRequests
+}
+
+// Record applies a check result. A link is flagged as broken once it failed failureThreshold consecutive checks and
+// recovers with the first successful check.
+func (h *LinkHealth) Record(result CheckResult, failureThreshold int) {
+	checkedAt := result.CheckedAt
+	h.LastCheckedAt = &checkedAt
+
+	if result.Err != nil {
+		errMessage := result.Err.Error()
+		h.LastError = &errMessage
+		h.StatusCode = nil
+		h.FinalURL = nil
+	} else {
+		statusCode := result.StatusCode
+		h.StatusCode = &statusCode
+		h.LastError = nil
+		if result.FinalURL != "" {
+			finalURL := result.FinalURL
+			h.FinalURL = &finalURL
+		}
+	}
+
+	switch {
+	case result.Inconclusive():
+		return
+	case result.Failed():
+		h.ConsecutiveFailures++
+	default:
+		h.ConsecutiveFailures = 0
+	}
+
+	h.Broken = failureThreshold > 0 && h.ConsecutiveFailures >= failureThreshold
+}
@@ -0,0 +1,126 @@
+package artist
+
+import (
+	"math"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PopularitySnapshot records an artist's audience on a provider at the time of a sync.
+type PopularitySnapshot struct {
+	ID        uuid.UUID     `gorm:"type:uuid;primaryKey;" json:"id"`
+	ArtistID  uuid.UUID     `gorm:"type:uuid;not null;index:idx_popularity_snapshots_artist_taken,priority:1" json:"artistID"`
+	Provider  provider.Name `gorm:"type:varchar(50);not null;index:idx_popularity_snapshots_provider_taken,priority:1" json:"provider"`
+	Followers int           `gorm:"not null;default:0" json:"followers"`
+	Tracks    int           `gorm:"not null;default:0" json:"tracks"`
+	Plays     int64         `gorm:"not null;default:0" json:"plays"`
+	TakenAt   time.Time     `gorm:"not null;index:idx_popularity_snapshots_artist_taken,priority:2;index:idx_popularity_snapshots_provider_taken,priority:2" json:"takenAt"`
+	CreatedAt time.Time     `json:"-"`
+}
+
+// TableName overrides the table name used by GORM
+func (PopularitySnapshot) TableName() string {
+	return "artist_popularity_snapshots"
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (s *PopularitySnapshot) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
+
+// NewPopularitySnapshot counts the tracks and their plays among the media fetched in a sync.
+func NewPopularitySnapshot(artistID uuid.UUID, name provider.Name, followers int, media []provider.Media, takenAt time.Time) PopularitySnapshot {
+	snapshot := PopularitySnapshot{ArtistID: artistID, Provider: name, Followers: followers, TakenAt: takenAt}
+	for _, m := range media {
+		if m.Kind != provider.Track {
+			continue
+		}
+		snapshot.Tracks++
+		snapshot.Plays += int64(m.Stats.Plays)
+	}
+	return snapshot
+}
+
+// SnapshotRange holds the snapshot an artist's growth over a window is measured from and the last snapshot within it.
+type SnapshotRange struct {
+	ArtistID uuid.UUID
+	First    PopularitySnapshot
+	Last     PopularitySnapshot
+}
+
+// TrendingWeights tune the trending score.
+type TrendingWeights struct {
+	// Followers and Plays weight the growth of the follower and play counts
+	Followers float64
+	Plays     float64
+	// Booking raises the score of a growing artist by this fraction per upcoming booking, up to MaxBookings
+	Booking     float64
+	MaxBookings int
+}
+
+// DefaultTrendingWeights returns the weights used when nothing else is configured.
+func DefaultTrendingWeights() TrendingWeights {
+	return TrendingWeights{
+		Followers:   0.6,
+		Plays:       0.4,
+		Booking:     0.25,
+		MaxBookings: 4,
+	}
+}
+
+// Trend is an artist's growth over a window.
+type Trend struct {
+	ArtistID         uuid.UUID
+	FollowerGrowth   float64
+	PlayGrowth       float64
+	Followers        int
+	Plays            int64
+	UpcomingBookings int
+	Score            float64
+}
+
+// ComputeTrend scores the growth between the first and last snapshot of a window. Growth is logarithmic, so
+// doubling counts the same for small and large artists, and growing artists with upcoming bookings rank higher.
+func ComputeTrend(r SnapshotRange, upcomingBookings int, w TrendingWeights) Trend {
+	trend := Trend{
+		ArtistID:         r.ArtistID,
+		FollowerGrowth:   logGrowth(int64(r.First.Followers), int64(r.Last.Followers)),
+		PlayGrowth:       logGrowth(r.First.Plays, r.Last.Plays),
+		Followers:        r.Last.Followers,
+		Plays:            r.Last.Plays,
+		UpcomingBookings: upcomingBookings,
+	}
+
+	trend.Score = w.Followers*trend.FollowerGrowth + w.Plays*trend.PlayGrowth
+	if trend.Score > 0 {
+		bookings := upcomingBookings
+		if w.MaxBookings > 0 && bookings > w.MaxBookings {
+			bookings = w.MaxBookings
+		}
+		trend.Score *= 1 + w.Booking*float64(bookings)
+	}
+	return trend
+}
+
+func logGrowth(from, to int64) float64 {
+	return math.Log(float64(to+1) / float64(from+1))
+}
+
+// FeaturedPin puts an artist on the featured list at a fixed position until it expires.
+type FeaturedPin struct {
+	ArtistID  uuid.UUID  `gorm:"type:uuid;primaryKey" json:"artistID"`
+	Position  int        `gorm:"not null;default:0" json:"position"`
+	PinnedAt  time.Time  `gorm:"not null" json:"pinnedAt"`
+	ExpiresAt *time.Time `gorm:"index" json:"expiresAt,omitempty"`
+}
+
+// TableName overrides the table name used by GORM
+func (FeaturedPin) TableName() string {
+	return "featured_artist_pins"
+}
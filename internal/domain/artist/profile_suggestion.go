@@ -0,0 +1,225 @@
+package artist
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/google/uuid"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+)
+
+// SuggestionStatus is the state of a profile suggestion.
+type SuggestionStatus string
+
+const (
+	SuggestionPending    SuggestionStatus = "pending"
+	SuggestionAccepted   SuggestionStatus = "accepted"
+	SuggestionRejected   SuggestionStatus = "rejected"
+	SuggestionAutoLinked SuggestionStatus = "auto_linked"
+)
+
+// Weights of the parts of a candidate score. They add up to one.
+const (
+	nameWeight     = 0.7
+	locationWeight = 0.15
+	followerWeight = 0.15
+	// linkedPenalty is subtracted when the profile already belongs to another artist
+	linkedPenalty = 0.5
+	// followerScale is the follower count that earns the full follower score
+	followerScale = 100000
+)
+
+// ProfileSuggestion is a provider profile that discovery found for an artist without a link to that provider.
+// Each candidate is suggested once per artist, so a rejected profile is not suggested again.
+type ProfileSuggestion struct {
+	ID              uuid.UUID        `gorm:"type:uuid;primaryKey;" json:"id"`
+	ArtistID        uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_profile_suggestions_candidate,priority:1" json:"artistID"`
+	Provider        provider.Name    `gorm:"type:varchar(50);not null;uniqueIndex:idx_profile_suggestions_candidate,priority:2" json:"provider"`
+	ExternalID      string           `gorm:"type:varchar(255);not null;uniqueIndex:idx_profile_suggestions_candidate,priority:3" json:"externalID"`
+	URL             string           `gorm:"type:text" json:"url"`
+	Username        string           `gorm:"type:varchar(255)" json:"username"`
+	DisplayName     string           `gorm:"type:varchar(255)" json:"displayName"`
+	AvatarURL       string           `gorm:"type:text" json:"avatarUrl"`
+	City            string           `gorm:"type:varchar(255)" json:"city"`
+	Country         string           `gorm:"type:varchar(255)" json:"country"`
+	Followers       int              `gorm:"not null;default:0" json:"followers"`
+	Score           float64          `gorm:"not null;default:0;index" json:"score"`
+	NameScore       float64          `gorm:"not null;default:0" json:"nameScore"`
+	LocationScore   float64          `gorm:"not null;default:0" json:"locationScore"`
+	FollowerScore   float64          `gorm:"not null;default:0" json:"followerScore"`
+	LinkedElsewhere bool             `gorm:"not null;default:false" json:"linkedElsewhere"`
+	Status          SuggestionStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	DecidedAt       *time.Time       `json:"decidedAt,omitempty"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"-"`
+}
+
+// TableName overrides the table name used by GORM
+func (ProfileSuggestion) TableName() string {
+	return "artist_profile_suggestions"
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (s *ProfileSuggestion) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
+
+// CandidateScore is how well a provider profile matches an artist, between zero and one.
+type CandidateScore struct {
+	Name            float64
+	Location        float64
+	Followers       float64
+	LinkedElsewhere bool
+	Total           float64
+}
+
+// ScoreCandidate rates a search result for the artist. The name counts most; a matching city or country and a
+// larger audience add to it, and a profile that is already linked to another artist is penalised.
+func ScoreCandidate(a *Artist, candidate *provider.Profile, linkedElsewhere bool) CandidateScore {
+	score := CandidateScore{LinkedElsewhere: linkedElsewhere}
+
+	for _, name := range []string{candidate.DisplayName, candidate.Username, permalinkFromURL(candidate.URL)} {
+		score.Name = math.Max(score.Name, NameSimilarity(a.Name, name))
+	}
+	score.Location = locationMatch(a, candidate)
+	if candidate.Followers > 0 {
+		score.Followers = math.Min(1, math.Log10(float64(candidate.Followers)+1)/math.Log10(followerScale))
+	}
+
+	score.Total = nameWeight*score.Name + locationWeight*score.Location + followerWeight*score.Followers
+	if linkedElsewhere {
+		score.Total -= linkedPenalty
+	}
+	score.Total = math.Max(0, math.Min(1, score.Total))
+	return score
+}
+
+// NewProfileSuggestion records a scored candidate for the artist.
+func NewProfileSuggestion(artistID uuid.UUID, name provider.Name, candidate *provider.Profile, score CandidateScore, status SuggestionStatus) ProfileSuggestion {
+	return ProfileSuggestion{
+		ArtistID:        artistID,
+		Provider:        name,
+		ExternalID:      candidate.ExternalID,
+		URL:             candidate.URL,
+		Username:        candidate.Username,
+		DisplayName:     candidate.DisplayName,
+		AvatarURL:       candidate.AvatarURL,
+		City:            candidate.City,
+		Country:         candidate.Country,
+		Followers:       candidate.Followers,
+		Score:           score.Total,
+		NameScore:       score.Name,
+		LocationScore:   score.Location,
+		FollowerScore:   score.Followers,
+		LinkedElsewhere: score.LinkedElsewhere,
+		Status:          status,
+	}
+}
+
+// Decide moves a pending suggestion to its final status.
+func (s *ProfileSuggestion) Decide(status SuggestionStatus, at time.Time) {
+	s.Status = status
+	s.DecidedAt = &at
+}
+
+// Permalink is the last path segment of the suggested profile URL.
+func (s *ProfileSuggestion) Permalink() string {
+	return permalinkFromURL(s.URL)
+}
+
+// NameSimilarity compares two names after folding case, accents and punctuation, returning one for equal names
+// and zero for names with nothing in common.
+func NameSimilarity(a, b string) float64 {
+	a, b = normalizeName(a), normalizeName(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	distance := levenshtein(ra, rb)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	return 1 - float64(distance)/float64(longest)
+}
+
+// NameKey returns the form NameSimilarity compares names in, names with equal keys are considered the same.
+func NameKey(name string) string {
+	return normalizeName(name)
+}
+
+// normalizeName lowercases the name, strips accents and drops everything but letters and digits, so
+// "DJ Émile" and "dj-emile" compare equal.
+func normalizeName(name string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		folded = name
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(folded) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// locationMatch gives a full score when the candidate's city matches the artist's and half a score when only
+// the country does. The artist's free-text location is checked as well as the resolved city and country.
+func locationMatch(a *Artist, candidate *provider.Profile) float64 {
+	known := strings.ToLower(strings.Join([]string{a.Location, a.ResolveField(FieldCity).Value, a.ResolveField(FieldCountry).Value}, " "))
+	contains := func(value string) bool {
+		value = strings.ToLower(strings.TrimSpace(value))
+		return value != "" && strings.Contains(known, value)
+	}
+
+	switch {
+	case contains(candidate.City):
+		return 1
+	case contains(candidate.Country):
+		return 0.5
+	}
+	return 0
+}
+
+func permalinkFromURL(rawURL string) string {
+	trimmed := strings.TrimRight(rawURL, "/")
+	if i := strings.LastIndex(trimmed, "/"); i >= 0 {
+		return trimmed[i+1:]
+	}
+	return trimmed
+}
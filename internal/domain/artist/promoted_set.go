@@ -0,0 +1,68 @@
+package artist
+
+import (
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/google/uuid"
+)
+
+// PromotedSet is the resolved player of an artist's promoted set. It is refreshed periodically so sets that
+// were taken down are noticed.
+type PromotedSet struct {
+	ArtistID            uuid.UUID     `gorm:"type:uuid;primaryKey" json:"artistID"`
+	Provider            provider.Name `gorm:"type:varchar(50);not null" json:"provider"`
+	URL                 string        `gorm:"type:text;not null" json:"url"`
+	Title               string        `gorm:"type:varchar(255)" json:"title"`
+	ThumbnailURL        string        `gorm:"type:text" json:"thumbnailUrl"`
+	AuthorName          string        `gorm:"type:varchar(255)" json:"authorName"`
+	DurationMs          int           `gorm:"not null;default:0" json:"durationMs"`
+	EmbedHTML           string        `gorm:"type:text" json:"embedHtml"`
+	ResolvedAt          *time.Time    `json:"resolvedAt,omitempty"`
+	LastCheckedAt       *time.Time    `gorm:"index" json:"lastCheckedAt,omitempty"`
+	LastError           *string       `gorm:"type:text" json:"lastError,omitempty"`
+	ConsecutiveFailures int           `gorm:"not null;default:0" json:"consecutiveFailures"`
+	Broken              bool          `gorm:"not null;default:false;index" json:"broken"`
+	CreatedAt           time.Time     `json:"-"`
+	UpdatedAt           time.Time     `json:"-"`
+}
+
+// TableName overrides the table name used by GORM
+func (PromotedSet) TableName() string {
+	return "artist_promoted_sets"
+}
+
+// NewPromotedSet creates the promoted set of an artist from a resolved player.
+func NewPromotedSet(artistID uuid.UUID, name provider.Name, embed *provider.Embed, resolvedAt time.Time) *PromotedSet {
+	set := &PromotedSet{ArtistID: artistID, Provider: name}
+	set.Apply(embed, resolvedAt)
+	return set
+}
+
+// Apply stores a successful resolution and clears any earlier failure.
+func (p *PromotedSet) Apply(embed *provider.Embed, resolvedAt time.Time) {
+	p.URL = embed.URL
+	p.Title = embed.Title
+	p.ThumbnailURL = embed.ThumbnailURL
+	p.AuthorName = embed.AuthorName
+	p.DurationMs = int(embed.Duration / time.Millisecond)
+	p.EmbedHTML = embed.HTML
+	p.ResolvedAt = &resolvedAt
+	p.LastCheckedAt = &resolvedAt
+	p.LastError = nil
+	p.ConsecutiveFailures = 0
+	p.Broken = false
+}
+
+// RecordFailure stores a failed resolution. Only conclusive failures, such as the set being gone, count towards
+// flagging it as broken; the last resolved player is kept either way.
+func (p *PromotedSet) RecordFailure(err error, conclusive bool, checkedAt time.Time, failureThreshold int) {
+	message := err.Error()
+	p.LastError = &message
+	p.LastCheckedAt = &checkedAt
+	if !conclusive {
+		return
+	}
+	p.ConsecutiveFailures++
+	p.Broken = failureThreshold > 0 && p.ConsecutiveFailures >= failureThreshold
+}
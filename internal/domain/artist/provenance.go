@@ -0,0 +1,164 @@
+package artist
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Field is an artist field that can be filled in by a metadata provider.
+type Field string
+
+const (
+	FieldUsername    Field = "username"
+	FieldFullName    Field = "fullName"
+	FieldFirstName   Field = "firstName"
+	FieldLastName    Field = "lastName"
+	FieldAvatarURL   Field = "avatarUrl"
+	FieldDescription Field = "description"
+	FieldCity        Field = "city"
+	FieldCountry     Field = "country"
+)
+
+// EnrichableFields lists the fields that track where their value came from.
+var EnrichableFields = []Field{
+	FieldUsername,
+	FieldFullName,
+	FieldFirstName,
+	FieldLastName,
+	FieldAvatarURL,
+	FieldDescription,
+	FieldCity,
+	FieldCountry,
+}
+
+// IsValid reports whether the field is one of the enrichable fields.
+func (f Field) IsValid() bool {
+	for _, field := range EnrichableFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Source is where the value of a field came from. Provider sources use the provider name, e.g. "soundcloud".
+type Source string
+
+const (
+	SourceManual Source = "manual"
+	SourceImport Source = "import"
+)
+
+// FieldOverride is a value set outside of provider sync, either by an editor or by an import. Sync never touches
+// overrides, so the value wins over provider data until it is reset.
+type FieldOverride struct {
+	ArtistID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"artistID"`
+	Field     Field     `gorm:"type:varchar(50);primaryKey" json:"field"`
+	Source    Source    `gorm:"type:varchar(50);not null" json:"source"`
+	Value     string    `gorm:"type:text" json:"value"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName overrides the table name used by GORM
+func (FieldOverride) TableName() string {
+	return "artist_field_overrides"
+}
+
+// FieldValue is the resolved value of a field together with its provenance. Source is empty when no source has a
+// value for the field.
+type FieldValue struct {
+	Field     Field
+	Value     string
+	Source    Source
+	UpdatedAt *time.Time
+}
+
+// SetField records a value for the field that provider sync will not overwrite and returns the stored override.
+func (a *Artist) SetField(field Field, value string, source Source, at time.Time) FieldOverride {
+	override := FieldOverride{ArtistID: a.ID, Field: field, Source: source, Value: value, UpdatedAt: at}
+	for i := range a.FieldOverrides {
+		if a.FieldOverrides[i].Field == field {
+			a.FieldOverrides[i] = override
+			return override
+		}
+	}
+	a.FieldOverrides = append(a.FieldOverrides, override)
+	return override
+}
+
+// ClearField drops the override of the field so it falls back to the provider value.
+func (a *Artist) ClearField(field Field) {
+	for i := range a.FieldOverrides {
+		if a.FieldOverrides[i].Field == field {
+			a.FieldOverrides = append(a.FieldOverrides[:i], a.FieldOverrides[i+1:]...)
+			return
+		}
+	}
+}
+
+// OverrideFor returns the loaded override of the field.
+func (a *Artist) OverrideFor(field Field) (*FieldOverride, bool) {
+	for i := range a.FieldOverrides {
+		if a.FieldOverrides[i].Field == field {
+			return &a.FieldOverrides[i], true
+		}
+	}
+	return nil, false
+}
+
+// ResolveField returns the override of the field if there is one, otherwise the value of the highest-priority
+// provider with data.
+func (a *Artist) ResolveField(field Field) FieldValue {
+	if override, ok := a.OverrideFor(field); ok {
+		updatedAt := override.UpdatedAt
+		return FieldValue{Field: field, Value: override.Value, Source: override.Source, UpdatedAt: &updatedAt}
+	}
+	return a.providerField(field, a.sortedProfiles())
+}
+
+// ResolvedFields resolves every enrichable field.
+func (a *Artist) ResolvedFields() []FieldValue {
+	values := make([]FieldValue, 0, len(EnrichableFields))
+	for _, field := range EnrichableFields {
+		values = append(values, a.ResolveField(field))
+	}
+	return values
+}
+
+// DisplayProfile is like PreferredProfile but lets overrides win over provider data.
+func (a *Artist) DisplayProfile() ResolvedProfile {
+	return resolvedProfile(a.ResolveField)
+}
+
+func (a *Artist) sortedProfiles() []ExternalProfile {
+	profiles := append([]ExternalProfile(nil), a.ExternalProfiles...)
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].Priority > profiles[j].Priority
+	})
+	return profiles
+}
+
+func (a *Artist) providerField(field Field, profiles []ExternalProfile) FieldValue {
+	for i := range profiles {
+		if v := profiles[i].FieldValue(field); v != "" {
+			fetchedAt := profiles[i].FetchedAt
+			return FieldValue{Field: field, Value: v, Source: Source(profiles[i].Provider), UpdatedAt: &fetchedAt}
+		}
+	}
+	return FieldValue{Field: field}
+}
+
+func resolvedProfile(resolve func(Field) FieldValue) ResolvedProfile {
+	return ResolvedProfile{
+		Username:    resolve(FieldUsername).Value,
+		FullName:    resolve(FieldFullName).Value,
+		FirstName:   resolve(FieldFirstName).Value,
+		LastName:    resolve(FieldLastName).Value,
+		AvatarURL:   resolve(FieldAvatarURL).Value,
+		Description: resolve(FieldDescription).Value,
+		City:        resolve(FieldCity).Value,
+		Country:     resolve(FieldCountry).Value,
+	}
+}
@@ -2,32 +2,130 @@ package artist
 
 import (
 	"errors"
-	"github.com/blnto/blnto_service/internal/utils"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/validation"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-	"time"
 )
 
-// SocialMediaLink represents the social media model with soft delete
+// SocialMediaLink represents the social media model with soft delete.
+// A link belongs either to an artist or to a collective, a check constraint enforces exactly one of them.
 type SocialMediaLink struct {
-	ID        uuid.UUID           `gorm:"type:uuid;primaryKey;" json:"id"`
-	ArtistID  uuid.UUID           `gorm:"type:uuid;not null;" json:"-"`
-	Platform  SocialMediaPlatform `gorm:"type:varchar(50);not null;" json:"platform"`
-	Link      string              `gorm:"type:text;not null;" json:"link"`
-	CreatedAt time.Time           `json:"-"`
-	UpdatedAt time.Time           `json:"-"`
-	DeletedAt gorm.DeletedAt      `gorm:"index" json:"-"`
+	ID           uuid.UUID           `gorm:"type:uuid;primaryKey;" json:"id"`
+	ArtistID     *uuid.UUID          `gorm:"type:uuid;index;check:chk_social_media_links_owner,num_nonnulls(artist_id, collective_id) = 1" json:"-"`
+	CollectiveID *uuid.UUID          `gorm:"type:uuid;index" json:"-"`
+	Platform     SocialMediaPlatform `gorm:"type:varchar(50);not null;" json:"platform"`
+	Link         string              `gorm:"type:text;not null;" json:"link"`
+	CreatedAt    time.Time           `json:"-"`
+	UpdatedAt    time.Time           `json:"-"`
+	DeletedAt    gorm.DeletedAt      `gorm:"index" json:"-"`
 }
 
-func (s *SocialMediaLink) ValidateLinkFormat() error {
-	// Implement link format validation logic
-	// For example, using regex to check if it's a valid URL
-	if !utils.IsValidURL(s.Link) {
-		return errors.New("invalid link format")
+// trackingParams are query parameters that only carry tracking information and are dropped from stored links.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"igshid":  true,
+	"igsh":    true,
+	"si":      true,
+	"ref":     true,
+	"ref_src": true,
+	"ref_url": true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+}
+
+// Normalize canonicalizes the link and detects its platform from the URL host.
+// An explicitly set platform must match the detected one for known hosts.
+// The returned validation errors name the rejected field, "link" or "platform".
+func (s *SocialMediaLink) Normalize() error {
+	canonical, detected, err := CanonicalizeLink(s.Link)
+	if err != nil {
+		return apperror.Invalid("link", "%v", err)
+	}
+
+	switch {
+	case s.Platform == "":
+		s.Platform = detected
+	case !s.Platform.IsValid():
+		return apperror.Invalid("platform", "platform '%s' is not allowed", s.Platform)
+	case detected != Website && s.Platform != detected:
+		return apperror.Invalid("platform", "link %s belongs to %s, not %s", canonical, detected, s.Platform)
 	}
+
+	s.Link = canonical
 	return nil
 }
 
+// CanonicalizeLink returns the canonical form of a social media link together with the platform detected from its
+// host. Links are forced to https, hosts are lowercased, tracking parameters and fragments are stripped and the
+// handles in profile paths are lowercased.
+func CanonicalizeLink(rawLink string) (string, SocialMediaPlatform, error) {
+	rawLink = strings.TrimSpace(rawLink)
+	if rawLink != "" && !strings.Contains(rawLink, "://") {
+		rawLink = "https://" + rawLink
+	}
+
+	if !validation.IsURL(rawLink) {
+		return "", "", errors.New("invalid link format")
+	}
+
+	u, err := url.Parse(rawLink)
+	if err != nil || u.Hostname() == "" {
+		return "", "", errors.New("invalid link format")
+	}
+
+	platform := PlatformForHost(u.Hostname())
+
+	u.Scheme = "https"
+	u.User = nil
+	u.Fragment = ""
+	u.Host = strings.ToLower(u.Host)
+	if platform != Website {
+		u.Host = canonicalHost(u.Hostname())
+	}
+
+	query := u.Query()
+	for key := range query {
+		if trackingParams[strings.ToLower(key)] || strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	u.Path = lowercaseHandle(platform, u.Hostname(), strings.TrimRight(u.Path, "/"))
+	u.RawPath = ""
+
+	return u.String(), platform, nil
+}
+
+// NormalizeLinks normalizes all links and rejects duplicates after canonicalization. The validation error lists every
+// rejected link by its index, e.g. "1.link".
+func NormalizeLinks(links []SocialMediaLink) error {
+	var errs validation.Errors
+	seen := make(map[string]bool, len(links))
+	for i := range links {
+		if err := links[i].Normalize(); err != nil {
+			if err := errs.Merge(strconv.Itoa(i), err); err != nil {
+				return err
+			}
+			continue
+		}
+		if seen[links[i].Link] {
+			errs.Add(validation.Path(i, "link"), "duplicate social media link %s", links[i].Link)
+		}
+		seen[links[i].Link] = true
+	}
+	return errs.Err()
+}
+
 // BeforeCreate will set a UUID rather than numeric ID.
 func (s *SocialMediaLink) BeforeCreate(tx *gorm.DB) (err error) {
 	s.ID = uuid.New()
@@ -1,11 +1,119 @@
 package artist
 
+import "strings"
+
 type SocialMediaPlatform string
 
 const (
-	Twitter    SocialMediaPlatform = "Twitter"
-	Facebook   SocialMediaPlatform = "Facebook"
-	Instagram  SocialMediaPlatform = "Instagram"
-	YouTube    SocialMediaPlatform = "YouTube"
-	Soundcloud SocialMediaPlatform = "Soundcloud"
+	Twitter         SocialMediaPlatform = "Twitter"
+	Facebook        SocialMediaPlatform = "Facebook"
+	Instagram       SocialMediaPlatform = "Instagram"
+	YouTube         SocialMediaPlatform = "YouTube"
+	Soundcloud      SocialMediaPlatform = "Soundcloud"
+	ResidentAdvisor SocialMediaPlatform = "ResidentAdvisor"
+	Bandcamp        SocialMediaPlatform = "Bandcamp"
+	Beatport        SocialMediaPlatform = "Beatport"
+	Mixcloud        SocialMediaPlatform = "Mixcloud"
+	TikTok          SocialMediaPlatform = "TikTok"
+	Website         SocialMediaPlatform = "Website"
 )
+
+// platformHosts maps known hosts (without "www." or "m.") to their platform.
+var platformHosts = map[string]SocialMediaPlatform{
+	"twitter.com":         Twitter,
+	"x.com":               Twitter,
+	"facebook.com":        Facebook,
+	"fb.com":              Facebook,
+	"instagram.com":       Instagram,
+	"youtube.com":         YouTube,
+	"youtu.be":            YouTube,
+	"music.youtube.com":   YouTube,
+	"soundcloud.com":      Soundcloud,
+	"on.soundcloud.com":   Soundcloud,
+	"ra.co":               ResidentAdvisor,
+	"residentadvisor.net": ResidentAdvisor,
+	"bandcamp.com":        Bandcamp,
+	"beatport.com":        Beatport,
+	"mixcloud.com":        Mixcloud,
+	"tiktok.com":          TikTok,
+	"vm.tiktok.com":       TikTok,
+}
+
+// handlePlatforms are platforms whose profile paths are case-insensitive handles.
+var handlePlatforms = map[SocialMediaPlatform]bool{
+	Twitter:         true,
+	Instagram:       true,
+	Soundcloud:      true,
+	ResidentAdvisor: true,
+	Mixcloud:        true,
+	TikTok:          true,
+}
+
+// shortLinkHosts are hosts of share links whose paths are case-sensitive tokens rather than handles.
+var shortLinkHosts = map[string]bool{
+	"on.soundcloud.com": true,
+	"vm.tiktok.com":     true,
+}
+
+// IsValid reports whether the platform is one of the supported platforms.
+func (p SocialMediaPlatform) IsValid() bool {
+	switch p {
+	case Twitter, Facebook, Instagram, YouTube, Soundcloud, ResidentAdvisor, Bandcamp, Beatport, Mixcloud, TikTok, Website:
+		return true
+	}
+	return false
+}
+
+// PlatformForHost detects the platform from a URL host. Unknown hosts are treated as a Website.
+func PlatformForHost(host string) SocialMediaPlatform {
+	host = canonicalHost(host)
+	if platform, ok := platformHosts[host]; ok {
+		return platform
+	}
+	// Bandcamp profiles live on their own subdomain, e.g. artist.bandcamp.com
+	if strings.HasSuffix(host, ".bandcamp.com") {
+		return Bandcamp
+	}
+	return Website
+}
+
+func canonicalHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, prefix := range []string{"www.", "m.", "mobile."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	return host
+}
+
+// lowercaseHandle lowercases the handle segment of a profile path, such as the user of soundcloud.com/User/track or
+// the DJ of ra.co/dj/Name. Paths of short links and of SoundCloud secret share links carry case-sensitive tokens and
+// are kept as they are.
+func lowercaseHandle(platform SocialMediaPlatform, host, path string) string {
+	if !handlePlatforms[platform] || shortLinkHosts[host] {
+		return path
+	}
+	segments := strings.Split(path, "/")
+	if platform == Soundcloud && hasSecretToken(segments) {
+		return path
+	}
+
+	// segments[0] is the empty string before the leading slash
+	handle := 1
+	if platform == ResidentAdvisor && len(segments) > 2 && strings.EqualFold(segments[1], "dj") {
+		handle = 2
+	}
+	for i := 1; i <= handle && i < len(segments); i++ {
+		segments[i] = strings.ToLower(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+// hasSecretToken reports whether a SoundCloud path is a private share link, e.g. /user/track/s-AbCdE
+func hasSecretToken(segments []string) bool {
+	for _, segment := range segments {
+		if len(segment) > 2 && strings.HasPrefix(segment, "s-") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package artist
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SyncRunStatus is the state of an artist sync run.
+type SyncRunStatus string
+
+const (
+	SyncRunning     SyncRunStatus = "running"
+	SyncInterrupted SyncRunStatus = "interrupted"
+	SyncCompleted   SyncRunStatus = "completed"
+	SyncFailed      SyncRunStatus = "failed"
+)
+
+// SyncRun records one pass of the artist sync. A run that is running or interrupted is resumed by the next
+// sync: it keeps its StaleBefore cutoff, so artists synced before the interruption are not synced twice.
+type SyncRun struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;" json:"id"`
+	Status      SyncRunStatus  `gorm:"type:varchar(20);not null;index" json:"status"`
+	StaleBefore time.Time      `gorm:"not null" json:"staleBefore"`
+	StartedAt   time.Time      `gorm:"not null" json:"startedAt"`
+	FinishedAt  *time.Time     `json:"finishedAt,omitempty"`
+	Total       int            `gorm:"not null;default:0" json:"total"`
+	Succeeded   int            `gorm:"not null;default:0" json:"succeeded"`
+	Failed      int            `gorm:"not null;default:0" json:"failed"`
+	Errors      []SyncRunError `gorm:"foreignKey:RunID" json:"errors,omitempty"`
+	//gorm additional fields
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// SyncRunError is the failure of a single artist within a sync run.
+type SyncRunError struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;" json:"id"`
+	RunID     uuid.UUID `gorm:"type:uuid;not null;index:idx_sync_run_errors_run_artist,priority:1" json:"runID"`
+	ArtistID  uuid.UUID `gorm:"type:uuid;not null;index:idx_sync_run_errors_run_artist,priority:2" json:"artistID"`
+	Error     string    `gorm:"type:text;not null" json:"error"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName overrides the table name used by GORM
+func (SyncRun) TableName() string {
+	return "artist_sync_runs"
+}
+
+// TableName overrides the table name used by GORM
+func (SyncRunError) TableName() string {
+	return "artist_sync_run_errors"
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (r *SyncRun) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (e *SyncRunError) BeforeCreate(tx *gorm.DB) (err error) {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return
+}
+
+// NewSyncRun starts a run over the artists last synced before staleBefore.
+func NewSyncRun(staleBefore, now time.Time) *SyncRun {
+	return &SyncRun{Status: SyncRunning, StaleBefore: staleBefore, StartedAt: now}
+}
+
+// IsResumable reports whether the run stopped before it finished.
+func (r *SyncRun) IsResumable() bool {
+	return r.Status == SyncRunning || r.Status == SyncInterrupted
+}
+
+// Finish closes the run with the given status.
+func (r *SyncRun) Finish(status SyncRunStatus, now time.Time) {
+	r.Status = status
+	r.FinishedAt = &now
+}
@@ -0,0 +1,66 @@
+package artist
+
+import (
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Track is a track an artist published on an external provider. Tracks are upserted by provider and external ID
+// during sync; a track that is no longer returned by the provider keeps its row and gets RemovedAt set.
+type Track struct {
+	ID            uuid.UUID     `gorm:"type:uuid;primaryKey;" json:"id"`
+	ArtistID      uuid.UUID     `gorm:"type:uuid;not null;index" json:"artistID"`
+	Provider      provider.Name `gorm:"type:varchar(50);not null;uniqueIndex:idx_artist_tracks_provider_external_id,priority:1" json:"provider"`
+	ExternalID    string        `gorm:"type:varchar(255);not null;uniqueIndex:idx_artist_tracks_provider_external_id,priority:2" json:"externalID"`
+	Title         string        `gorm:"type:varchar(255);not null" json:"title"`
+	DurationMs    int           `json:"durationMs"`
+	ArtworkURL    string        `gorm:"type:text" json:"artworkUrl"`
+	Genre         string        `gorm:"type:varchar(100)" json:"genre"`
+	PermalinkURL  string        `gorm:"type:text" json:"permalinkUrl"`
+	PlaybackCount int           `json:"playbackCount"`
+	LikesCount    int           `json:"likesCount"`
+	CommentCount  int           `json:"commentCount"`
+	RepostsCount  int           `json:"repostsCount"`
+	PublishedAt   *time.Time    `json:"publishedAt,omitempty"`
+	LastSeenAt    time.Time     `gorm:"not null" json:"lastSeenAt"`
+	RemovedAt     *time.Time    `gorm:"index" json:"removedAt,omitempty"`
+	//gorm additional fields
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// TableName overrides the table name used by GORM
+func (Track) TableName() string {
+	return "artist_tracks"
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (t *Track) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return
+}
+
+// NewTrackFromMedia builds the track of an artist from provider media seen at seenAt.
+func NewTrackFromMedia(artistID uuid.UUID, name provider.Name, media provider.Media, seenAt time.Time) Track {
+	return Track{
+		ArtistID:      artistID,
+		Provider:      name,
+		ExternalID:    media.ExternalID,
+		Title:         media.Title,
+		DurationMs:    int(media.Duration / time.Millisecond),
+		ArtworkURL:    media.ArtworkURL,
+		Genre:         media.Genre,
+		PermalinkURL:  media.URL,
+		PlaybackCount: media.Stats.Plays,
+		LikesCount:    media.Stats.Likes,
+		CommentCount:  media.Stats.Comments,
+		RepostsCount:  media.Stats.Reposts,
+		PublishedAt:   media.PublishedAt,
+		LastSeenAt:    seenAt,
+	}
+}
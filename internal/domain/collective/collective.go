@@ -0,0 +1,75 @@
+package collective
+
+import (
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Kind distinguishes crews from labels. Both can host events and take over stages.
+type Kind string
+
+const (
+	Crew  Kind = "Crew"
+	Label Kind = "Label"
+)
+
+// Collective represents a crew or label that hosts events and stage takeovers. Names are unique among collectives
+// that are not deleted.
+type Collective struct {
+	ID               uuid.UUID                `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name             string                   `gorm:"type:varchar(100);not null;uniqueIndex:idx_collectives_active_name,where:deleted_at IS NULL" json:"name"`
+	Description      *string                  `gorm:"type:text" json:"description,omitempty"`
+	Kind             Kind                     `gorm:"type:varchar(20);not null;default:'Crew'" json:"kind"`
+	SocialMediaLinks []artist.SocialMediaLink `gorm:"foreignKey:CollectiveID" json:"socialMediaLinks,omitempty"`
+	Memberships      []*Membership            `gorm:"foreignKey:CollectiveID" json:"memberships,omitempty"`
+	//gorm additional fields
+	CreatedAt time.Time      `json:"-"`
+	UpdatedAt time.Time      `json:"-"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// Membership links an artist to a collective. An artist is a member at most once, a removed member can join again.
+type Membership struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CollectiveID uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_collective_memberships_active_member,priority:1,where:deleted_at IS NULL" json:"collectiveID"`
+	ArtistID     uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_collective_memberships_active_member,priority:2,where:deleted_at IS NULL;index" json:"artistID"`
+	Artist       *artist.Artist `json:"artist,omitempty"`
+	Role         *string        `gorm:"type:varchar(100)" json:"role,omitempty"`
+	//gorm additional fields
+	CreatedAt time.Time      `json:"-"`
+	UpdatedAt time.Time      `json:"-"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName overrides the table name used by GORM
+func (Membership) TableName() string {
+	return "collective_memberships"
+}
+
+// IsValid reports whether the kind is one of the known collective kinds.
+func (k Kind) IsValid() bool {
+	switch k {
+	case Crew, Label:
+		return true
+	}
+	return false
+}
+
+// BeforeCreate Collective hook
+func (c *Collective) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
+
+// BeforeCreate Membership hook
+func (m *Membership) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,52 @@
+package collective
+
+import (
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/stage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrTakeoverOverlap = apperror.Conflict("stage is already taken over during this time range")
+
+// Takeover assigns a collective as host of a stage for a time range.
+type Takeover struct {
+	ID           uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CollectiveID uuid.UUID    `gorm:"type:uuid;not null;index" json:"collectiveID"`
+	StageID      uuid.UUID    `gorm:"type:uuid;not null;index:idx_stage_takeovers_stage_start,priority:1" json:"stageID"`
+	Stage        *stage.Stage `json:"stage,omitempty"`
+	StartTime    time.Time    `gorm:"not null;index:idx_stage_takeovers_stage_start,priority:2" json:"startTime"`
+	EndTime      time.Time    `gorm:"not null" json:"endTime"`
+	//gorm additional fields
+	CreatedAt time.Time      `json:"-"`
+	UpdatedAt time.Time      `json:"-"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName overrides the table name used by GORM
+func (Takeover) TableName() string {
+	return "stage_takeovers"
+}
+
+// Overlaps reports whether both takeovers claim the same stage at the same time.
+func (t *Takeover) Overlaps(other *Takeover) bool {
+	return t.StageID == other.StageID && t.StartTime.Before(other.EndTime) && other.StartTime.Before(t.EndTime)
+}
+
+// BeforeCreate Takeover hook
+func (t *Takeover) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return t.Validate()
+}
+
+// Validate checks that the takeover ends after it starts.
+func (t *Takeover) Validate() error {
+	if !t.StartTime.Before(t.EndTime) {
+		return apperror.Invalid("endTime", "takeover start time must be before its end time")
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package event
+
+import (
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/stage"
+)
+
+// AppearanceFilter narrows the timetable entries returned for an artist.
+// When neither flag is set (or both are) all appearances are returned.
+type AppearanceFilter struct {
+	Upcoming bool
+	Past     bool
+}
+
+// OnlyUpcoming reports whether the filter selects future appearances only.
+func (f AppearanceFilter) OnlyUpcoming() bool {
+	return f.Upcoming && !f.Past
+}
+
+// OnlyPast reports whether the filter selects past appearances only.
+func (f AppearanceFilter) OnlyPast() bool {
+	return f.Past && !f.Upcoming
+}
+
+// PerformanceStats aggregates the sets an artist has already played.
+type PerformanceStats struct {
+	TotalSets        int
+	TotalHoursPlayed float64
+	FirstAppearance  *time.Time
+	LastAppearance   *time.Time
+	FavouriteStage   *stage.Stage
+}
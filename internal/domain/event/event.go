@@ -1,23 +1,24 @@
 package event
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/blnto/blnto_service/internal/domain/artist"
 	"github.com/blnto/blnto_service/internal/domain/stage"
+	"github.com/blnto/blnto_service/internal/domain/validation"
 	"github.com/blnto/blnto_service/internal/domain/venue"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Event struct {
-	ID        uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	VenueID   uuid.UUID         `gorm:"type:uuid;not null" json:"venueID"`
-	Venue     *venue.Venue      `gorm:"foreignKey:VenueID" json:"venue"`
-	StartDate time.Time         `gorm:"not null" json:"startDate"`
-	EndDate   time.Time         `gorm:"not null" json:"endDate"`
-	Timetable []*TimetableEntry `gorm:"foreignKey:EventID" json:"timetable,omitempty"`
+	ID               uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	VenueID          uuid.UUID         `gorm:"type:uuid;not null" json:"venueID"`
+	Venue            *venue.Venue      `gorm:"foreignKey:VenueID" json:"venue"`
+	HostCollectiveID *uuid.UUID        `gorm:"type:uuid;index" json:"hostCollectiveID,omitempty"`
+	StartDate        time.Time         `gorm:"not null" json:"startDate"`
+	EndDate          time.Time         `gorm:"not null" json:"endDate"`
+	Timetable        []*TimetableEntry `gorm:"foreignKey:EventID" json:"timetable,omitempty"`
 	//gorm additional fields
 	CreatedAt time.Time      `json:"-"`
 	UpdatedAt time.Time      `json:"-"`
@@ -27,11 +28,12 @@ type Event struct {
 type TimetableEntry struct {
 	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	EventID   uuid.UUID      `gorm:"type:uuid;foreignKey:EventID" json:"eventID"`
+	Event     *Event         `gorm:"foreignKey:EventID" json:"event,omitempty"`
 	StageID   uuid.UUID      `gorm:"type:uuid;foreignKey:StageID" json:"stageID"`
 	Stage     *stage.Stage   `json:"stage,omitempty"`
-	ArtistID  uuid.UUID      `gorm:"type:uuid;foreignKey:ArtistID" json:"artistID"`
+	ArtistID  uuid.UUID      `gorm:"type:uuid;foreignKey:ArtistID;index:idx_timetable_entries_artist_start,priority:1" json:"artistID"`
 	Artist    *artist.Artist `json:"artist,omitempty"`
-	StartTime time.Time      `json:"startTime,omitempty"`
+	StartTime time.Time      `gorm:"index:idx_timetable_entries_artist_start,priority:2" json:"startTime,omitempty"`
 	EndTime   time.Time      `json:"endTime,omitempty"`
 	//gorm additinonal fields
 	CreatedAt time.Time      `json:"-"`
@@ -50,7 +52,10 @@ func (e *Event) AddTimetableEntry(entry *TimetableEntry) error {
 }
 func (e *Event) validateTimetableEntry(entry *TimetableEntry) error {
 	if entry.StartTime.Before(e.StartDate) || entry.EndTime.After(e.EndDate) {
-		return fmt.Errorf("timetable entry times must be within the event start and end dates")
+		var errs validation.Errors
+		errs.Check(!entry.StartTime.Before(e.StartDate), "startTime", "timetable entry times must be within the event start and end dates")
+		errs.Check(!entry.EndTime.After(e.EndDate), "endTime", "timetable entry times must be within the event start and end dates")
+		return errs.Err()
 	}
 	return nil
 }
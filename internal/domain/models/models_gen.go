@@ -11,24 +11,52 @@ import (
 	"github.com/google/uuid"
 )
 
-type Artist struct {
-	ID                    uuid.UUID      `json:"id"`
-	Name                  string         `json:"name"`
-	Location              *string        `json:"location,omitempty"`
-	City                  *string        `json:"city,omitempty"`
-	Country               *string        `json:"country,omitempty"`
-	AvatarURL             *string        `json:"avatarUrl,omitempty"`
-	FirstName             *string        `json:"firstName,omitempty"`
-	LastName              *string        `json:"lastName,omitempty"`
-	FullName              *string        `json:"fullName,omitempty"`
-	Username              *string        `json:"username,omitempty"`
-	Description           *string        `json:"description,omitempty"`
-	SoundcloudID          *int           `json:"soundcloudId,omitempty"`
-	SoundcloudPermalink   *string        `json:"soundcloudPermalink,omitempty"`
-	SoundcloudPromotedSet *string        `json:"soundcloudPromotedSet,omitempty"`
-	SocialMediaLinks      []*SocialMedia `json:"socialMediaLinks,omitempty"`
+type Node interface {
+	IsNode()
+	GetID() uuid.UUID
+}
+
+type AddCollectiveMemberInput struct {
+	CollectiveID uuid.UUID `json:"collectiveID"`
+	ArtistID     uuid.UUID `json:"artistID"`
+	Role         *string   `json:"role,omitempty"`
 }
 
+type AppearanceConnection struct {
+	Edges    []*TimeTableEntryEdge `json:"edges"`
+	PageInfo *PageInfo             `json:"pageInfo"`
+}
+
+type Artist struct {
+	ID                    uuid.UUID               `json:"id"`
+	Name                  string                  `json:"name"`
+	Location              *string                 `json:"location,omitempty"`
+	City                  *string                 `json:"city,omitempty"`
+	Country               *string                 `json:"country,omitempty"`
+	AvatarURL             *string                 `json:"avatarUrl,omitempty"`
+	FirstName             *string                 `json:"firstName,omitempty"`
+	LastName              *string                 `json:"lastName,omitempty"`
+	FullName              *string                 `json:"fullName,omitempty"`
+	Username              *string                 `json:"username,omitempty"`
+	Description           *string                 `json:"description,omitempty"`
+	SoundcloudID          *int                    `json:"soundcloudId,omitempty"`
+	SoundcloudPermalink   *string                 `json:"soundcloudPermalink,omitempty"`
+	SoundcloudPromotedSet *string                 `json:"soundcloudPromotedSet,omitempty"`
+	SocialMediaLinks      []*SocialMedia          `json:"socialMediaLinks,omitempty"`
+	Appearances           *AppearanceConnection   `json:"appearances,omitempty"`
+	PerformanceStats      *ArtistPerformanceStats `json:"performanceStats,omitempty"`
+	Residencies           []*Residency            `json:"residencies,omitempty"`
+	Collectives           []*Collective           `json:"collectives,omitempty"`
+	ExternalProfiles      []*ExternalProfile      `json:"externalProfiles,omitempty"`
+	FieldSources          []*ArtistFieldSource    `json:"fieldSources"`
+	PromotedSet           *PromotedSet            `json:"promotedSet,omitempty"`
+	SoundcloudAccount     *SoundCloudAccount      `json:"soundcloudAccount,omitempty"`
+	Tracks                *TrackConnection        `json:"tracks,omitempty"`
+}
+
+func (Artist) IsNode()               {}
+func (this Artist) GetID() uuid.UUID { return this.ID }
+
 type ArtistConnection struct {
 	Edges    []*ArtistEdge `json:"edges,omitempty"`
 	PageInfo *PageInfo     `json:"pageInfo,omitempty"`
@@ -39,12 +67,63 @@ type ArtistEdge struct {
 	Cursor *string `json:"cursor,omitempty"`
 }
 
+type ArtistFieldSource struct {
+	Field     ArtistField `json:"field"`
+	Source    *string     `json:"source,omitempty"`
+	UpdatedAt *time.Time  `json:"updatedAt,omitempty"`
+}
+
+type ArtistPerformanceStats struct {
+	TotalSets        int        `json:"totalSets"`
+	TotalHoursPlayed float64    `json:"totalHoursPlayed"`
+	FirstAppearance  *time.Time `json:"firstAppearance,omitempty"`
+	LastAppearance   *time.Time `json:"lastAppearance,omitempty"`
+	FavouriteStage   *Stage     `json:"favouriteStage,omitempty"`
+}
+
 type ArtistSearchInput struct {
 	SearchTerm *string `json:"searchTerm,omitempty"`
 	After      *string `json:"after,omitempty"`
 	First      *int    `json:"first,omitempty"`
 }
 
+type BrokenSocialMediaLinkConnection struct {
+	Edges    []*BrokenSocialMediaLinkEdge `json:"edges"`
+	PageInfo *PageInfo                    `json:"pageInfo"`
+}
+
+type BrokenSocialMediaLinkEdge struct {
+	Node   *SocialMedia `json:"node"`
+	Cursor string       `json:"cursor"`
+}
+
+type Collective struct {
+	ID               uuid.UUID           `json:"id"`
+	Name             string              `json:"name"`
+	Description      *string             `json:"description,omitempty"`
+	Kind             CollectiveKind      `json:"kind"`
+	SocialMediaLinks []*SocialMedia      `json:"socialMediaLinks,omitempty"`
+	Members          []*CollectiveMember `json:"members"`
+	HostedEvents     []*Event            `json:"hostedEvents"`
+	Takeovers        []*StageTakeover    `json:"takeovers"`
+}
+
+type CollectiveConnection struct {
+	Edges    []*CollectiveEdge `json:"edges"`
+	PageInfo *PageInfo         `json:"pageInfo"`
+}
+
+type CollectiveEdge struct {
+	Node   *Collective `json:"node"`
+	Cursor string      `json:"cursor"`
+}
+
+type CollectiveMember struct {
+	ID     uuid.UUID `json:"id"`
+	Artist *Artist   `json:"artist"`
+	Role   *string   `json:"role,omitempty"`
+}
+
 type CreateArtistInput struct {
 	Name                  string                    `json:"name"`
 	Location              *string                   `json:"location,omitempty"`
@@ -53,15 +132,29 @@ type CreateArtistInput struct {
 	SocialMedia           []*CreateSocialMediaInput `json:"socialMedia,omitempty"`
 }
 
+type CreateCollectiveInput struct {
+	Name        string                    `json:"name"`
+	Description *string                   `json:"description,omitempty"`
+	Kind        *CollectiveKind           `json:"kind,omitempty"`
+	SocialMedia []*CreateSocialMediaInput `json:"socialMedia,omitempty"`
+}
+
 type CreateEventInput struct {
 	VenueID   uuid.UUID `json:"venueID"`
 	StartDate time.Time `json:"startDate"`
 	EndDate   time.Time `json:"endDate"`
 }
 
+type CreateResidencyInput struct {
+	ArtistID  uuid.UUID  `json:"artistID"`
+	VenueID   uuid.UUID  `json:"venueID"`
+	StartDate time.Time  `json:"startDate"`
+	EndDate   *time.Time `json:"endDate,omitempty"`
+}
+
 type CreateSocialMediaInput struct {
-	Platform SocialMediaPlatform `json:"platform"`
-	Link     string              `json:"link"`
+	Platform *SocialMediaPlatform `json:"platform,omitempty"`
+	Link     string               `json:"link"`
 }
 
 type CreateStageInput struct {
@@ -69,6 +162,13 @@ type CreateStageInput struct {
 	VenueID uuid.UUID `json:"venueID"`
 }
 
+type CreateStageTakeoverInput struct {
+	CollectiveID uuid.UUID `json:"collectiveID"`
+	StageID      uuid.UUID `json:"stageID"`
+	StartTime    time.Time `json:"startTime"`
+	EndTime      time.Time `json:"endTime"`
+}
+
 type CreateTimetableEntryInput struct {
 	EventID    uuid.UUID  `json:"eventID"`
 	StageID    uuid.UUID  `json:"stageID"`
@@ -106,14 +206,25 @@ type DeleteTimetableEntryInput struct {
 	ID uuid.UUID `json:"id"`
 }
 
+type EndResidencyInput struct {
+	ID      uuid.UUID `json:"id"`
+	EndDate time.Time `json:"endDate"`
+}
+
 type Event struct {
-	ID        uuid.UUID         `json:"id"`
-	Venue     *Venue            `json:"venue"`
-	StartDate time.Time         `json:"startDate"`
-	EndDate   time.Time         `json:"endDate"`
-	Timetable []*TimetableEntry `json:"timetable,omitempty"`
+	ID               uuid.UUID         `json:"id"`
+	VenueID          uuid.UUID         `json:"venueID"`
+	Venue            *Venue            `json:"venue"`
+	HostCollectiveID *uuid.UUID        `json:"hostCollectiveID,omitempty"`
+	Host             *Collective       `json:"host,omitempty"`
+	StartDate        time.Time         `json:"startDate"`
+	EndDate          time.Time         `json:"endDate"`
+	Timetable        []*TimetableEntry `json:"timetable,omitempty"`
 }
 
+func (Event) IsNode()               {}
+func (this Event) GetID() uuid.UUID { return this.ID }
+
 type EventConnection struct {
 	Edges    []*EventEdge `json:"edges,omitempty"`
 	PageInfo *PageInfo    `json:"pageInfo,omitempty"`
@@ -124,16 +235,118 @@ type EventEdge struct {
 	Node   *Event  `json:"node,omitempty"`
 }
 
+type ExternalProfile struct {
+	Provider    string    `json:"provider"`
+	Priority    int       `json:"priority"`
+	ExternalID  string    `json:"externalId"`
+	URL         string    `json:"url"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"displayName"`
+	AvatarURL   string    `json:"avatarUrl"`
+	Description string    `json:"description"`
+	City        string    `json:"city"`
+	Country     string    `json:"country"`
+	FetchedAt   time.Time `json:"fetchedAt"`
+}
+
+type FeaturedArtistPin struct {
+	Artist    *Artist    `json:"artist"`
+	Position  int        `json:"position"`
+	PinnedAt  time.Time  `json:"pinnedAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
 type PageInfo struct {
 	EndCursor   *string `json:"endCursor,omitempty"`
 	HasNextPage *bool   `json:"hasNextPage,omitempty"`
 }
 
+type ProfileSuggestion struct {
+	ID              uuid.UUID               `json:"id"`
+	ArtistID        uuid.UUID               `json:"artistID"`
+	Artist          *Artist                 `json:"artist,omitempty"`
+	Provider        string                  `json:"provider"`
+	ExternalID      string                  `json:"externalId"`
+	URL             string                  `json:"url"`
+	Username        string                  `json:"username"`
+	DisplayName     string                  `json:"displayName"`
+	AvatarURL       string                  `json:"avatarUrl"`
+	City            string                  `json:"city"`
+	Country         string                  `json:"country"`
+	Followers       int                     `json:"followers"`
+	Score           float64                 `json:"score"`
+	NameScore       float64                 `json:"nameScore"`
+	LocationScore   float64                 `json:"locationScore"`
+	FollowerScore   float64                 `json:"followerScore"`
+	LinkedElsewhere bool                    `json:"linkedElsewhere"`
+	Status          ProfileSuggestionStatus `json:"status"`
+	CreatedAt       time.Time               `json:"createdAt"`
+	DecidedAt       *time.Time              `json:"decidedAt,omitempty"`
+}
+
+type ProfileSuggestionConnection struct {
+	Edges    []*ProfileSuggestionEdge `json:"edges"`
+	PageInfo *PageInfo                `json:"pageInfo"`
+}
+
+type ProfileSuggestionEdge struct {
+	Node   *ProfileSuggestion `json:"node"`
+	Cursor string             `json:"cursor"`
+}
+
+type PromotedSet struct {
+	URL           string     `json:"url"`
+	Title         *string    `json:"title,omitempty"`
+	ThumbnailURL  *string    `json:"thumbnailUrl,omitempty"`
+	AuthorName    *string    `json:"authorName,omitempty"`
+	DurationMs    *int       `json:"durationMs,omitempty"`
+	EmbedHTML     *string    `json:"embedHtml,omitempty"`
+	ResolvedAt    *time.Time `json:"resolvedAt,omitempty"`
+	LastCheckedAt *time.Time `json:"lastCheckedAt,omitempty"`
+	LastError     *string    `json:"lastError,omitempty"`
+	Broken        bool       `json:"broken"`
+}
+
+type Residency struct {
+	ID        uuid.UUID  `json:"id"`
+	ArtistID  uuid.UUID  `json:"artistID"`
+	Artist    *Artist    `json:"artist,omitempty"`
+	VenueID   uuid.UUID  `json:"venueID"`
+	Venue     *Venue     `json:"venue,omitempty"`
+	StartDate time.Time  `json:"startDate"`
+	EndDate   *time.Time `json:"endDate,omitempty"`
+	IsActive  bool       `json:"isActive"`
+}
+
+type SetEventHostInput struct {
+	EventID      uuid.UUID  `json:"eventID"`
+	CollectiveID *uuid.UUID `json:"collectiveID,omitempty"`
+}
+
 type SocialMedia struct {
-	ID       uuid.UUID           `json:"id"`
-	Platform SocialMediaPlatform `json:"platform"`
-	Link     string              `json:"link"`
-	ArtistID uuid.UUID           `json:"artistId"`
+	ID           uuid.UUID              `json:"id"`
+	Platform     SocialMediaPlatform    `json:"platform"`
+	Link         string                 `json:"link"`
+	ArtistID     *uuid.UUID             `json:"artistId,omitempty"`
+	CollectiveID *uuid.UUID             `json:"collectiveId,omitempty"`
+	Health       *SocialMediaLinkHealth `json:"health,omitempty"`
+}
+
+type SocialMediaLinkHealth struct {
+	StatusCode          *int       `json:"statusCode,omitempty"`
+	FinalURL            *string    `json:"finalUrl,omitempty"`
+	LastError           *string    `json:"lastError,omitempty"`
+	LastCheckedAt       *time.Time `json:"lastCheckedAt,omitempty"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	Broken              bool       `json:"broken"`
+}
+
+type SoundCloudAccount struct {
+	UserID      int       `json:"userId"`
+	Permalink   string    `json:"permalink"`
+	URL         string    `json:"url"`
+	Scope       string    `json:"scope"`
+	ConnectedAt time.Time `json:"connectedAt"`
 }
 
 type Stage struct {
@@ -142,30 +355,85 @@ type Stage struct {
 	VenueID uuid.UUID `json:"venueID"`
 }
 
+func (Stage) IsNode()               {}
+func (this Stage) GetID() uuid.UUID { return this.ID }
+
+type StageTakeover struct {
+	ID           uuid.UUID   `json:"id"`
+	CollectiveID uuid.UUID   `json:"collectiveID"`
+	Collective   *Collective `json:"collective,omitempty"`
+	Stage        *Stage      `json:"stage,omitempty"`
+	StartTime    time.Time   `json:"startTime"`
+	EndTime      time.Time   `json:"endTime"`
+}
+
 type TimeTableEntryEdge struct {
 	Cursor string          `json:"cursor"`
 	Node   *TimetableEntry `json:"node"`
 }
 
 type TimetableEntry struct {
-	ID         uuid.UUID  `json:"id"`
-	EventID    uuid.UUID  `json:"eventID"`
-	StageID    uuid.UUID  `json:"stageID"`
-	Stage      *Stage     `json:"stage,omitempty"`
-	ArtistID   uuid.UUID  `json:"artistID"`
-	Artist     *Artist    `json:"artist,omitempty"`
-	WeekNumber *int       `json:"weekNumber,omitempty"`
-	Year       *int       `json:"year,omitempty"`
-	Day        *string    `json:"day,omitempty"`
-	StartTime  *time.Time `json:"startTime,omitempty"`
-	EndTime    *time.Time `json:"endTime,omitempty"`
-}
+	ID            uuid.UUID  `json:"id"`
+	EventID       uuid.UUID  `json:"eventID"`
+	Event         *Event     `json:"event,omitempty"`
+	StageID       uuid.UUID  `json:"stageID"`
+	Stage         *Stage     `json:"stage,omitempty"`
+	ArtistID      uuid.UUID  `json:"artistID"`
+	Artist        *Artist    `json:"artist,omitempty"`
+	WeekNumber    *int       `json:"weekNumber,omitempty"`
+	Year          *int       `json:"year,omitempty"`
+	Day           *string    `json:"day,omitempty"`
+	StartTime     *time.Time `json:"startTime,omitempty"`
+	EndTime       *time.Time `json:"endTime,omitempty"`
+	IsResident    bool       `json:"isResident"`
+	ResidentSince *time.Time `json:"residentSince,omitempty"`
+	IsDebut       bool       `json:"isDebut"`
+}
+
+func (TimetableEntry) IsNode()               {}
+func (this TimetableEntry) GetID() uuid.UUID { return this.ID }
 
 type TimetableEntryConnection struct {
 	Edges    []*TimetableEntry `json:"edges"`
 	PageInfo *PageInfo         `json:"pageInfo"`
 }
 
+type Track struct {
+	ID            uuid.UUID  `json:"id"`
+	Provider      string     `json:"provider"`
+	ExternalID    string     `json:"externalId"`
+	Title         string     `json:"title"`
+	DurationMs    int        `json:"durationMs"`
+	ArtworkURL    *string    `json:"artworkUrl,omitempty"`
+	Genre         *string    `json:"genre,omitempty"`
+	PermalinkURL  string     `json:"permalinkUrl"`
+	PlaybackCount int        `json:"playbackCount"`
+	LikesCount    int        `json:"likesCount"`
+	CommentCount  int        `json:"commentCount"`
+	RepostsCount  int        `json:"repostsCount"`
+	PublishedAt   *time.Time `json:"publishedAt,omitempty"`
+}
+
+type TrackConnection struct {
+	Edges    []*TrackEdge `json:"edges"`
+	PageInfo *PageInfo    `json:"pageInfo"`
+}
+
+type TrackEdge struct {
+	Node   *Track `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+type TrendingArtist struct {
+	Artist           *Artist `json:"artist"`
+	Score            float64 `json:"score"`
+	FollowerGrowth   float64 `json:"followerGrowth"`
+	PlayGrowth       float64 `json:"playGrowth"`
+	Followers        int     `json:"followers"`
+	Plays            int     `json:"plays"`
+	UpcomingBookings int     `json:"upcomingBookings"`
+}
+
 type UpdateArtistInput struct {
 	ID                    uuid.UUID                 `json:"id"`
 	Name                  *string                   `json:"name,omitempty"`
@@ -173,6 +441,21 @@ type UpdateArtistInput struct {
 	SoundcloudPromotedSet *string                   `json:"soundcloudPromotedSet,omitempty"`
 	SoundcloudPermalink   *string                   `json:"soundcloudPermalink,omitempty"`
 	SocialMedia           []*UpdateSocialMediaInput `json:"socialMedia,omitempty"`
+	Username              *string                   `json:"username,omitempty"`
+	FullName              *string                   `json:"fullName,omitempty"`
+	FirstName             *string                   `json:"firstName,omitempty"`
+	LastName              *string                   `json:"lastName,omitempty"`
+	AvatarURL             *string                   `json:"avatarUrl,omitempty"`
+	Description           *string                   `json:"description,omitempty"`
+	City                  *string                   `json:"city,omitempty"`
+	Country               *string                   `json:"country,omitempty"`
+}
+
+type UpdateCollectiveInput struct {
+	ID          uuid.UUID       `json:"id"`
+	Name        *string         `json:"name,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	Kind        *CollectiveKind `json:"kind,omitempty"`
 }
 
 type UpdateSocialMediaInput struct {
@@ -188,6 +471,9 @@ type Venue struct {
 	Stages      []*Stage  `json:"stages,omitempty"`
 }
 
+func (Venue) IsNode()               {}
+func (this Venue) GetID() uuid.UUID { return this.ID }
+
 type VenueConnection struct {
 	Edges    []*VenueEdge `json:"edges"`
 	PageInfo *PageInfo    `json:"pageInfo"`
@@ -198,6 +484,145 @@ type VenueEdge struct {
 	Cursor string `json:"cursor"`
 }
 
+type ArtistField string
+
+const (
+	ArtistFieldUsername    ArtistField = "Username"
+	ArtistFieldFullName    ArtistField = "FullName"
+	ArtistFieldFirstName   ArtistField = "FirstName"
+	ArtistFieldLastName    ArtistField = "LastName"
+	ArtistFieldAvatarURL   ArtistField = "AvatarUrl"
+	ArtistFieldDescription ArtistField = "Description"
+	ArtistFieldCity        ArtistField = "City"
+	ArtistFieldCountry     ArtistField = "Country"
+)
+
+var AllArtistField = []ArtistField{
+	ArtistFieldUsername,
+	ArtistFieldFullName,
+	ArtistFieldFirstName,
+	ArtistFieldLastName,
+	ArtistFieldAvatarURL,
+	ArtistFieldDescription,
+	ArtistFieldCity,
+	ArtistFieldCountry,
+}
+
+func (e ArtistField) IsValid() bool {
+	switch e {
+	case ArtistFieldUsername, ArtistFieldFullName, ArtistFieldFirstName, ArtistFieldLastName, ArtistFieldAvatarURL, ArtistFieldDescription, ArtistFieldCity, ArtistFieldCountry:
+		return true
+	}
+	return false
+}
+
+func (e ArtistField) String() string {
+	return string(e)
+}
+
+func (e *ArtistField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ArtistField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ArtistField", str)
+	}
+	return nil
+}
+
+func (e ArtistField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CollectiveKind string
+
+const (
+	CollectiveKindCrew  CollectiveKind = "Crew"
+	CollectiveKindLabel CollectiveKind = "Label"
+)
+
+var AllCollectiveKind = []CollectiveKind{
+	CollectiveKindCrew,
+	CollectiveKindLabel,
+}
+
+func (e CollectiveKind) IsValid() bool {
+	switch e {
+	case CollectiveKindCrew, CollectiveKindLabel:
+		return true
+	}
+	return false
+}
+
+func (e CollectiveKind) String() string {
+	return string(e)
+}
+
+func (e *CollectiveKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CollectiveKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CollectiveKind", str)
+	}
+	return nil
+}
+
+func (e CollectiveKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ProfileSuggestionStatus string
+
+const (
+	ProfileSuggestionStatusPending    ProfileSuggestionStatus = "Pending"
+	ProfileSuggestionStatusAccepted   ProfileSuggestionStatus = "Accepted"
+	ProfileSuggestionStatusRejected   ProfileSuggestionStatus = "Rejected"
+	ProfileSuggestionStatusAutoLinked ProfileSuggestionStatus = "AutoLinked"
+)
+
+var AllProfileSuggestionStatus = []ProfileSuggestionStatus{
+	ProfileSuggestionStatusPending,
+	ProfileSuggestionStatusAccepted,
+	ProfileSuggestionStatusRejected,
+	ProfileSuggestionStatusAutoLinked,
+}
+
+func (e ProfileSuggestionStatus) IsValid() bool {
+	switch e {
+	case ProfileSuggestionStatusPending, ProfileSuggestionStatusAccepted, ProfileSuggestionStatusRejected, ProfileSuggestionStatusAutoLinked:
+		return true
+	}
+	return false
+}
+
+func (e ProfileSuggestionStatus) String() string {
+	return string(e)
+}
+
+func (e *ProfileSuggestionStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProfileSuggestionStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProfileSuggestionStatus", str)
+	}
+	return nil
+}
+
+func (e ProfileSuggestionStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type SocialMediaPlatform string
 
 const (
@@ -207,6 +632,11 @@ const (
 	SocialMediaPlatformYouTube         SocialMediaPlatform = "YouTube"
 	SocialMediaPlatformSoundcloud      SocialMediaPlatform = "Soundcloud"
 	SocialMediaPlatformResidentAdvisor SocialMediaPlatform = "ResidentAdvisor"
+	SocialMediaPlatformBandcamp        SocialMediaPlatform = "Bandcamp"
+	SocialMediaPlatformBeatport        SocialMediaPlatform = "Beatport"
+	SocialMediaPlatformMixcloud        SocialMediaPlatform = "Mixcloud"
+	SocialMediaPlatformTikTok          SocialMediaPlatform = "TikTok"
+	SocialMediaPlatformWebsite         SocialMediaPlatform = "Website"
 )
 
 var AllSocialMediaPlatform = []SocialMediaPlatform{
@@ -216,11 +646,16 @@ var AllSocialMediaPlatform = []SocialMediaPlatform{
 	SocialMediaPlatformYouTube,
 	SocialMediaPlatformSoundcloud,
 	SocialMediaPlatformResidentAdvisor,
+	SocialMediaPlatformBandcamp,
+	SocialMediaPlatformBeatport,
+	SocialMediaPlatformMixcloud,
+	SocialMediaPlatformTikTok,
+	SocialMediaPlatformWebsite,
 }
 
 func (e SocialMediaPlatform) IsValid() bool {
 	switch e {
-	case SocialMediaPlatformTwitter, SocialMediaPlatformFacebook, SocialMediaPlatformInstagram, SocialMediaPlatformYouTube, SocialMediaPlatformSoundcloud, SocialMediaPlatformResidentAdvisor:
+	case SocialMediaPlatformTwitter, SocialMediaPlatformFacebook, SocialMediaPlatformInstagram, SocialMediaPlatformYouTube, SocialMediaPlatformSoundcloud, SocialMediaPlatformResidentAdvisor, SocialMediaPlatformBandcamp, SocialMediaPlatformBeatport, SocialMediaPlatformMixcloud, SocialMediaPlatformTikTok, SocialMediaPlatformWebsite:
 		return true
 	}
 	return false
@@ -246,3 +681,46 @@ func (e *SocialMediaPlatform) UnmarshalGQL(v interface{}) error {
 func (e SocialMediaPlatform) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+type TrendingWindow string
+
+const (
+	TrendingWindowDay   TrendingWindow = "Day"
+	TrendingWindowWeek  TrendingWindow = "Week"
+	TrendingWindowMonth TrendingWindow = "Month"
+)
+
+var AllTrendingWindow = []TrendingWindow{
+	TrendingWindowDay,
+	TrendingWindowWeek,
+	TrendingWindowMonth,
+}
+
+func (e TrendingWindow) IsValid() bool {
+	switch e {
+	case TrendingWindowDay, TrendingWindowWeek, TrendingWindowMonth:
+		return true
+	}
+	return false
+}
+
+func (e TrendingWindow) String() string {
+	return string(e)
+}
+
+func (e *TrendingWindow) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TrendingWindow(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TrendingWindow", str)
+	}
+	return nil
+}
+
+func (e TrendingWindow) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Name identifies an artist metadata provider.
+type Name string
+
+const (
+	SoundCloud      Name = "soundcloud"
+	ResidentAdvisor Name = "residentadvisor"
+)
+
+var (
+	// ErrNotSupported is returned when a provider cannot handle a URL or operation.
+	ErrNotSupported = errors.New("not supported by provider")
+	// ErrNotFound is returned when the provider has no profile or media for the requested ID or URL.
+	ErrNotFound = errors.New("not found at provider")
+)
+
+// UnavailableError is returned while a provider refuses calls, for example because it kept failing and
+// its circuit breaker opened. Callers should pause until RetryAt.
+type UnavailableError struct {
+	Provider Name
+	RetryAt  time.Time
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("%s unavailable until %s", e.Provider, e.RetryAt.Format(time.RFC3339))
+}
+
+// Profile is an artist profile as reported by a provider.
+type Profile struct {
+	ExternalID  string
+	URL         string
+	Username    string
+	DisplayName string
+	FirstName   string
+	LastName    string
+	AvatarURL   string
+	Description string
+	City        string
+	Country     string
+	Followers   int
+}
+
+// MediaKind distinguishes the kinds of media a provider can return.
+type MediaKind string
+
+const (
+	Track    MediaKind = "track"
+	Playlist MediaKind = "playlist"
+)
+
+// Media is a piece of published media such as a track or a set.
+type Media struct {
+	ExternalID  string
+	Kind        MediaKind
+	Title       string
+	URL         string
+	ArtworkURL  string
+	Duration    time.Duration
+	Genre       string
+	PublishedAt *time.Time
+	Stats       MediaStats
+}
+
+// MediaStats are the engagement counters reported for a piece of media.
+type MediaStats struct {
+	Plays    int
+	Likes    int
+	Comments int
+	Reposts  int
+}
+
+// Embed is the embeddable player of a piece of media.
+type Embed struct {
+	// URL is the canonical URL of the media
+	URL          string
+	Title        string
+	ThumbnailURL string
+	AuthorName   string
+	// HTML is the player markup to embed
+	HTML     string
+	Duration time.Duration
+}
+
+// Provider enriches artists with metadata from an external service.
+type Provider interface {
+	// Name identifies the provider
+	Name() Name
+	// Priority decides which provider wins when several have data for the same field, higher wins
+	Priority() int
+	// Supports reports whether the provider can resolve the given profile URL
+	Supports(rawURL string) bool
+	// ResolveURL turns a profile URL into the provider's external ID
+	ResolveURL(ctx context.Context, rawURL string) (string, error)
+	// FetchProfile loads the profile for an external ID
+	FetchProfile(ctx context.Context, externalID string) (*Profile, error)
+	// FetchMedia loads the media published by an external ID
+	FetchMedia(ctx context.Context, externalID string) ([]Media, error)
+}
+
+// Searcher is implemented by providers that can look up profiles by artist name.
+type Searcher interface {
+	Provider
+	// SearchProfiles returns up to limit profiles matching the query, best matches first
+	SearchProfiles(ctx context.Context, query string, limit int) ([]Profile, error)
+}
+
+// Embedder is implemented by providers that can turn a media URL into an embeddable player.
+type Embedder interface {
+	Provider
+	// ResolveEmbed returns the player of the media, ErrNotSupported for URLs the provider cannot embed
+	ResolveEmbed(ctx context.Context, mediaURL string) (*Embed, error)
+}
+
+// AccountEmbedder is implemented by embedders that can also resolve media only an account can see, such as
+// private share links, with that account's access token.
+type AccountEmbedder interface {
+	Embedder
+	// ResolveEmbedAs returns the player of the media as seen by the owner of the access token
+	ResolveEmbedAs(ctx context.Context, mediaURL, accessToken string) (*Embed, error)
+}
+
+// AccountTokens hands out the access tokens of the accounts artists connected to a provider.
+type AccountTokens interface {
+	// AccessToken returns a valid access token of the artist's account, or "" when the artist connected none
+	AccessToken(ctx context.Context, artistID uuid.UUID) (string, error)
+}
+
+// ResolveEmbedFor resolves the media with the account the artist connected, so the artist's private share links
+// resolve too. Without a usable account it falls back to the embedder's own credentials.
+func ResolveEmbedFor(ctx context.Context, e Embedder, accounts AccountTokens, artistID uuid.UUID, mediaURL string) (*Embed, error) {
+	if accountEmbedder, ok := e.(AccountEmbedder); ok && accounts != nil && artistID != uuid.Nil {
+		if token, err := accounts.AccessToken(ctx, artistID); err == nil && token != "" {
+			return accountEmbedder.ResolveEmbedAs(ctx, mediaURL, token)
+		}
+	}
+	return e.ResolveEmbed(ctx, mediaURL)
+}
@@ -0,0 +1,60 @@
+package provider
+
+import "sort"
+
+// Registry holds the configured providers ordered by priority, highest first.
+type Registry struct {
+	providers []Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	registry := &Registry{}
+	for _, p := range providers {
+		registry.Register(p)
+	}
+	return registry
+}
+
+// Register adds a provider, replacing any provider registered under the same name.
+func (r *Registry) Register(p Provider) {
+	for i, existing := range r.providers {
+		if existing.Name() == p.Name() {
+			r.providers[i] = p
+			r.sort()
+			return
+		}
+	}
+	r.providers = append(r.providers, p)
+	r.sort()
+}
+
+// All returns the providers ordered by priority, highest first.
+func (r *Registry) All() []Provider {
+	return append([]Provider(nil), r.providers...)
+}
+
+// Get returns the provider with the given name.
+func (r *Registry) Get(name Name) (Provider, bool) {
+	for _, p := range r.providers {
+		if p.Name() == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// ForURL returns the highest-priority provider that supports the URL.
+func (r *Registry) ForURL(rawURL string) (Provider, bool) {
+	for _, p := range r.providers {
+		if p.Supports(rawURL) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func (r *Registry) sort() {
+	sort.SliceStable(r.providers, func(i, j int) bool {
+		return r.providers[i].Priority() > r.providers[j].Priority()
+	})
+}
@@ -0,0 +1,86 @@
+// Package validation checks input values and collects the rejected fields, so a request reports all of them at once
+// instead of failing on the first.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+)
+
+// Errors collects rejected fields. The zero value is ready to use.
+type Errors struct {
+	fields []apperror.FieldError
+}
+
+// Add rejects the field.
+func (e *Errors) Add(field string, format string, args ...interface{}) {
+	e.fields = append(e.fields, apperror.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Check rejects the field unless ok holds.
+func (e *Errors) Check(ok bool, field string, format string, args ...interface{}) {
+	if !ok {
+		e.Add(field, format, args...)
+	}
+}
+
+// Merge adds the fields of a validation error, with their paths under prefix. Any other error is returned as is, so
+// callers can stop on failures that are not about the input.
+func (e *Errors) Merge(prefix string, err error) error {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Kind != apperror.KindValidation {
+		return err
+	}
+	for _, f := range appErr.Fields {
+		e.fields = append(e.fields, apperror.FieldError{Field: Path(prefix, f.Field), Message: f.Message})
+	}
+	return nil
+}
+
+// Prefix moves the fields of a validation error under prefix, e.g. Prefix("input", residency.Validate()). Any other
+// error, and nil, is returned as is.
+func Prefix(prefix string, err error) error {
+	var errs Errors
+	if err := errs.Merge(prefix, err); err != nil {
+		return err
+	}
+	return errs.Err()
+}
+
+// Empty reports whether no field was rejected.
+func (e *Errors) Empty() bool {
+	return len(e.fields) == 0
+}
+
+// Err returns the validation error of all rejected fields, nil if there are none.
+func (e *Errors) Err() error {
+	if e.Empty() {
+		return nil
+	}
+	return apperror.Validation(e.fields...)
+}
+
+// Path joins field path segments, e.g. Path("input", "socialMedia", 1, "link") is "input.socialMedia.1.link".
+func Path(segments ...interface{}) string {
+	parts := make([]string, 0, len(segments))
+	for _, s := range segments {
+		if part := fmt.Sprint(s); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// IsURL reports whether s is an absolute http or https URL with a host.
+func IsURL(s string) bool {
+	u, err := url.ParseRequestURI(s)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return (scheme == "http" || scheme == "https") && u.Host != ""
+}
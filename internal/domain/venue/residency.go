@@ -0,0 +1,73 @@
+package venue
+
+import (
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrResidencyOverlap = apperror.Conflict("artist already has a residency at this venue during this period")
+
+// Residency marks an artist as a resident of a venue for a period of time.
+// An open-ended residency has no EndDate.
+type Residency struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ArtistID  uuid.UUID  `gorm:"type:uuid;not null;index:idx_residencies_artist_venue,priority:1" json:"artistID"`
+	VenueID   uuid.UUID  `gorm:"type:uuid;not null;index:idx_residencies_artist_venue,priority:2" json:"venueID"`
+	StartDate time.Time  `gorm:"not null" json:"startDate"`
+	EndDate   *time.Time `json:"endDate,omitempty"`
+	//gorm additional fields
+	CreatedAt time.Time      `json:"-"`
+	UpdatedAt time.Time      `json:"-"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// IsActiveAt reports whether the residency covers the given point in time.
+func (r *Residency) IsActiveAt(t time.Time) bool {
+	if t.Before(r.StartDate) {
+		return false
+	}
+	return r.EndDate == nil || !t.After(*r.EndDate)
+}
+
+// Overlaps reports whether both residencies make the same artist a resident of the same venue on some day.
+func (r *Residency) Overlaps(other *Residency) bool {
+	if r.ArtistID != other.ArtistID || r.VenueID != other.VenueID {
+		return false
+	}
+	startsBeforeOtherEnds := other.EndDate == nil || !r.StartDate.After(*other.EndDate)
+	otherStartsBeforeEnd := r.EndDate == nil || !other.StartDate.After(*r.EndDate)
+	return startsBeforeOtherEnds && otherStartsBeforeEnd
+}
+
+// End closes the residency at the given date.
+func (r *Residency) End(endDate time.Time) error {
+	if endDate.Before(r.StartDate) {
+		return apperror.Invalid("endDate", "residency end date must not be before its start date")
+	}
+	r.EndDate = &endDate
+	return nil
+}
+
+// BeforeCreate Residency hook
+func (r *Residency) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return r.Validate()
+}
+
+// BeforeUpdate Residency hook
+func (r *Residency) BeforeUpdate(tx *gorm.DB) error {
+	return r.Validate()
+}
+
+// Validate checks that the residency does not end before it starts.
+func (r *Residency) Validate() error {
+	if r.EndDate != nil && r.EndDate.Before(r.StartDate) {
+		return apperror.Invalid("endDate", "residency end date must not be before its start date")
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package artistApi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidState is returned for callbacks whose state is unknown, expired or was already used
+	ErrInvalidState = apperror.Unauthorized("authorization request is unknown or expired")
+	// ErrAccountTaken is returned when the SoundCloud account is already connected to another artist
+	ErrAccountTaken = apperror.Conflict("soundcloud account is connected to another artist")
+)
+
+// ArtistLinker stores the SoundCloud identity of a connected account on the artist
+type ArtistLinker interface {
+	LinkSoundCloudAccount(ctx context.Context, artistID uuid.UUID, scID int, permalink string) error
+}
+
+// AccountConnector runs the authorization code flow with PKCE that lets artists connect their own SoundCloud
+// account, and hands out the tokens of connected accounts.
+type AccountConnector struct {
+	client *SoundCloudClient
+	store  AccountStore
+	linker ArtistLinker
+	now    func() time.Time
+
+	// refreshes serializes token refreshes, SoundCloud refresh tokens can only be used once
+	refreshes sync.Mutex
+}
+
+// NewAccountConnector creates a connector whose authorization requests expire after the client's AuthorizationTTL.
+func NewAccountConnector(client *SoundCloudClient, store AccountStore, linker ArtistLinker) *AccountConnector {
+	return &AccountConnector{client: client, store: store, linker: linker, now: time.Now}
+}
+
+// Start begins an authorization request for the artist and returns the SoundCloud URL to send the artist to.
+func (c *AccountConnector) Start(ctx context.Context, artistID uuid.UUID) (string, error) {
+	verifier, err := NewCodeVerifier()
+	if err != nil {
+		return "", fmt.Errorf("error generating code verifier: %w", err)
+	}
+	state, err := randomURLToken(24)
+	if err != nil {
+		return "", fmt.Errorf("error generating state: %w", err)
+	}
+
+	authorizationURL, err := c.client.AuthorizationURL(state, CodeChallenge(verifier))
+	if err != nil {
+		return "", err
+	}
+	err = c.store.SaveState(ctx, &AuthorizationState{
+		State:        state,
+		ArtistID:     artistID,
+		CodeVerifier: verifier,
+		ExpiresAt:    c.now().Add(c.client.config.AuthorizationTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error saving authorization state: %w", err)
+	}
+	return authorizationURL, nil
+}
+
+// Complete finishes the authorization request identified by state: it exchanges the code for the user's token,
+// looks up the user the token belongs to and links that user to the artist. It returns the connected account.
+func (c *AccountConnector) Complete(ctx context.Context, state, code string) (*AccountToken, error) {
+	pending, err := c.store.TakeState(ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("error loading authorization state: %w", err)
+	}
+	if pending == nil || !c.now().Before(pending.ExpiresAt) {
+		return nil, ErrInvalidState
+	}
+
+	response, err := c.client.ExchangeAuthorizationCode(ctx, code, pending.CodeVerifier)
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging authorization code: %w", err)
+	}
+	me, err := c.client.FetchMe(ctx, response.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching connected account: %w", err)
+	}
+
+	existing, err := c.store.AccountByUserID(ctx, me.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ArtistID != pending.ArtistID {
+		return nil, ErrAccountTaken
+	}
+
+	now := c.now()
+	account := &AccountToken{ArtistID: pending.ArtistID, UserID: me.ID, Permalink: me.Permalink, ConnectedAt: now}
+	account.apply(response, now)
+
+	// The account is saved before the artist is changed, so a failed save leaves the artist as it was. A failed link
+	// puts the previous account back.
+	previous, err := c.store.Account(ctx, pending.ArtistID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.store.SaveAccount(ctx, account); err != nil {
+		return nil, fmt.Errorf("error saving soundcloud account: %w", err)
+	}
+	if err := c.linker.LinkSoundCloudAccount(ctx, pending.ArtistID, me.ID, me.Permalink); err != nil {
+		if restoreErr := c.restoreAccount(context.WithoutCancel(ctx), pending.ArtistID, previous); restoreErr != nil {
+			err = errors.Join(err, fmt.Errorf("error restoring previous account: %w", restoreErr))
+		}
+		return nil, fmt.Errorf("error linking soundcloud account: %w", err)
+	}
+	return account, nil
+}
+
+// restoreAccount puts back the account the artist had connected before, or removes the new one if there was none
+func (c *AccountConnector) restoreAccount(ctx context.Context, artistID uuid.UUID, previous *AccountToken) error {
+	if previous != nil {
+		return c.store.SaveAccount(ctx, previous)
+	}
+	_, err := c.store.DeleteAccount(ctx, artistID)
+	return err
+}
+
+// Account returns the account the artist connected, or nil if there is none.
+func (c *AccountConnector) Account(ctx context.Context, artistID uuid.UUID) (*AccountToken, error) {
+	return c.store.Account(ctx, artistID)
+}
+
+// Disconnect forgets the artist's account and reports whether one was connected. The artist keeps the permalink
+// and SoundCloud ID.
+func (c *AccountConnector) Disconnect(ctx context.Context, artistID uuid.UUID) (bool, error) {
+	return c.store.DeleteAccount(ctx, artistID)
+}
+
+// AccessToken returns a valid access token of the artist's account, refreshing it when it is about to expire,
+// or "" when the artist connected no account.
+func (c *AccountConnector) AccessToken(ctx context.Context, artistID uuid.UUID) (string, error) {
+	account, err := c.store.Account(ctx, artistID)
+	if err != nil || account == nil {
+		return "", err
+	}
+	if c.now().Add(tokenExpirySkew).Before(account.AccessTokenExpiry) {
+		return account.AccessToken, nil
+	}
+
+	c.refreshes.Lock()
+	defer c.refreshes.Unlock()
+
+	// Another caller may have refreshed the token while this one waited
+	account, err = c.store.Account(ctx, artistID)
+	if err != nil || account == nil {
+		return "", err
+	}
+	if c.now().Add(tokenExpirySkew).Before(account.AccessTokenExpiry) {
+		return account.AccessToken, nil
+	}
+
+	response, err := c.client.RefreshAccessToken(ctx, account.RefreshToken)
+	if err != nil {
+		return "", fmt.Errorf("error refreshing token of artist %s: %w", artistID, err)
+	}
+
+	account.apply(response, c.now())
+	if err := c.store.SaveAccount(ctx, account); err != nil {
+		return "", fmt.Errorf("error saving soundcloud account: %w", err)
+	}
+	return account.AccessToken, nil
+}
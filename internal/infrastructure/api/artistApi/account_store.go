@@ -0,0 +1,252 @@
+package artistApi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/infrastructure/secrets"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AccountToken is the token of a SoundCloud account an artist connected, kept apart from the app's own token.
+// In the database AccessToken and RefreshToken hold ciphertexts sealed with the key named by KeyID.
+type AccountToken struct {
+	ArtistID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID            int       `gorm:"not null;uniqueIndex"`
+	Permalink         string    `gorm:"type:varchar(255);not null"`
+	AccessToken       string    `gorm:"type:text;not null"`
+	RefreshToken      string    `gorm:"type:text;not null"`
+	KeyID             string    `gorm:"type:varchar(64);not null;default:''"`
+	Scope             string    `gorm:"type:varchar(255);not null;default:''"`
+	AccessTokenExpiry time.Time `gorm:"not null"`
+	ConnectedAt       time.Time `gorm:"not null"`
+	UpdatedAt         time.Time
+}
+
+// TableName overrides the table name used by GORM
+func (AccountToken) TableName() string {
+	return "soundcloud_account_tokens"
+}
+
+// apply copies a token response onto the account
+func (t *AccountToken) apply(response *AccessTokenResponse, now time.Time) {
+	t.AccessToken = response.AccessToken
+	if response.RefreshToken != "" {
+		t.RefreshToken = response.RefreshToken
+	}
+	t.Scope = response.Scope
+	t.AccessTokenExpiry = now.Add(time.Duration(response.ExpiresIn) * time.Second)
+}
+
+// AuthorizationState is an authorization request in progress. It ties the state SoundCloud echoes back to the
+// artist and the PKCE code verifier, which is sealed like the tokens.
+type AuthorizationState struct {
+	State        string    `gorm:"type:varchar(64);primaryKey"`
+	ArtistID     uuid.UUID `gorm:"type:uuid;not null"`
+	CodeVerifier string    `gorm:"type:text;not null"`
+	KeyID        string    `gorm:"type:varchar(64);not null;default:''"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+// TableName overrides the table name used by GORM
+func (AuthorizationState) TableName() string {
+	return "soundcloud_authorization_states"
+}
+
+// AccountStore persists connected accounts and the authorization requests in progress
+type AccountStore interface {
+	SaveState(ctx context.Context, state *AuthorizationState) error
+	// TakeState removes the state and returns it, or nil if it is unknown or was already taken
+	TakeState(ctx context.Context, state string) (*AuthorizationState, error)
+	// Account returns the account the artist connected, or nil if there is none
+	Account(ctx context.Context, artistID uuid.UUID) (*AccountToken, error)
+	// AccountByUserID returns the artist's account with the SoundCloud user ID, or nil if no artist connected it
+	AccountByUserID(ctx context.Context, userID int) (*AccountToken, error)
+	SaveAccount(ctx context.Context, account *AccountToken) error
+	// DeleteAccount removes the artist's account and reports whether there was one
+	DeleteAccount(ctx context.Context, artistID uuid.UUID) (bool, error)
+}
+
+// DBAccountStore keeps accounts and states in the database, secrets encrypted with the keyring's current key
+type DBAccountStore struct {
+	db      *gorm.DB
+	keyring *secrets.Keyring
+}
+
+func NewDBAccountStore(db *gorm.DB, keyring *secrets.Keyring) *DBAccountStore {
+	return &DBAccountStore{db: db, keyring: keyring}
+}
+
+func (s *DBAccountStore) SaveState(ctx context.Context, state *AuthorizationState) error {
+	stored := *state
+	keyID, verifier, err := s.keyring.Encrypt(state.CodeVerifier)
+	if err != nil {
+		return fmt.Errorf("error encrypting code verifier: %w", err)
+	}
+	stored.KeyID, stored.CodeVerifier = keyID, verifier
+	// Drop the requests that were never completed while at it
+	if err := s.db.WithContext(ctx).Delete(&AuthorizationState{}, "expires_at < ?", time.Now()).Error; err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Create(&stored).Error
+}
+
+func (s *DBAccountStore) TakeState(ctx context.Context, state string) (*AuthorizationState, error) {
+	var taken []AuthorizationState
+	// Deleting with RETURNING lets only one callback use the state
+	err := s.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("state = ?", state).
+		Delete(&taken).Error
+	if err != nil || len(taken) == 0 {
+		return nil, err
+	}
+
+	verifier, err := s.keyring.Decrypt(taken[0].KeyID, taken[0].CodeVerifier)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting code verifier: %w", err)
+	}
+	taken[0].CodeVerifier = verifier
+	return &taken[0], nil
+}
+
+func (s *DBAccountStore) Account(ctx context.Context, artistID uuid.UUID) (*AccountToken, error) {
+	return s.findAccount(ctx, "artist_id = ?", artistID)
+}
+
+func (s *DBAccountStore) AccountByUserID(ctx context.Context, userID int) (*AccountToken, error) {
+	return s.findAccount(ctx, "user_id = ?", userID)
+}
+
+func (s *DBAccountStore) findAccount(ctx context.Context, query string, arg interface{}) (*AccountToken, error) {
+	var account AccountToken
+	if err := s.db.WithContext(ctx).Where(query, arg).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	accessToken, err := s.keyring.Decrypt(account.KeyID, account.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting access token: %w", err)
+	}
+	refreshToken, err := s.keyring.Decrypt(account.KeyID, account.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting refresh token: %w", err)
+	}
+	account.AccessToken, account.RefreshToken = accessToken, refreshToken
+	return &account, nil
+}
+
+func (s *DBAccountStore) SaveAccount(ctx context.Context, account *AccountToken) error {
+	stored := *account
+	keyID, accessToken, err := s.keyring.Encrypt(account.AccessToken)
+	if err != nil {
+		return fmt.Errorf("error encrypting access token: %w", err)
+	}
+	_, refreshToken, err := s.keyring.Encrypt(account.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("error encrypting refresh token: %w", err)
+	}
+	stored.KeyID, stored.AccessToken, stored.RefreshToken = keyID, accessToken, refreshToken
+
+	return s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&stored).Error
+}
+
+// Reencrypt rewrites every account not sealed with the current key and returns the number of accounts rewritten.
+// Pending authorization states are left alone, they expire within minutes.
+func (s *DBAccountStore) Reencrypt(ctx context.Context) (int, error) {
+	var stale []AccountToken
+	if err := s.db.WithContext(ctx).Where("key_id <> ?", s.keyring.CurrentKeyID()).Find(&stale).Error; err != nil {
+		return 0, err
+	}
+
+	for i := range stale {
+		account, err := s.Account(ctx, stale[i].ArtistID)
+		if err != nil {
+			return i, fmt.Errorf("account of artist %s: %w", stale[i].ArtistID, err)
+		}
+		if err := s.SaveAccount(ctx, account); err != nil {
+			return i, fmt.Errorf("account of artist %s: %w", stale[i].ArtistID, err)
+		}
+	}
+	return len(stale), nil
+}
+
+func (s *DBAccountStore) DeleteAccount(ctx context.Context, artistID uuid.UUID) (bool, error) {
+	result := s.db.WithContext(ctx).Delete(&AccountToken{}, "artist_id = ?", artistID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// MemoryAccountStore keeps accounts and states in memory, for offline runs and tests
+type MemoryAccountStore struct {
+	mu       sync.Mutex
+	states   map[string]AuthorizationState
+	accounts map[uuid.UUID]AccountToken
+}
+
+func NewMemoryAccountStore() *MemoryAccountStore {
+	return &MemoryAccountStore{states: make(map[string]AuthorizationState), accounts: make(map[uuid.UUID]AccountToken)}
+}
+
+func (s *MemoryAccountStore) SaveState(ctx context.Context, state *AuthorizationState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.State] = *state
+	return nil
+}
+
+func (s *MemoryAccountStore) TakeState(ctx context.Context, state string) (*AuthorizationState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	taken, ok := s.states[state]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.states, state)
+	return &taken, nil
+}
+
+func (s *MemoryAccountStore) Account(ctx context.Context, artistID uuid.UUID) (*AccountToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if account, ok := s.accounts[artistID]; ok {
+		return &account, nil
+	}
+	return nil, nil
+}
+
+func (s *MemoryAccountStore) AccountByUserID(ctx context.Context, userID int) (*AccountToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, account := range s.accounts {
+		if account.UserID == userID {
+			return &account, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryAccountStore) SaveAccount(ctx context.Context, account *AccountToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[account.ArtistID] = *account
+	return nil
+}
+
+func (s *MemoryAccountStore) DeleteAccount(ctx context.Context, artistID uuid.UUID) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.accounts[artistID]
+	delete(s.accounts, artistID)
+	return ok, nil
+}
@@ -0,0 +1,103 @@
+package artistApi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+const (
+	authorizeURL = "https://secure.soundcloud.com/authorize" // Authorization endpoint of the code flow
+
+	grantAuthorizationCode = "authorization_code"
+)
+
+// ErrConnectionsDisabled is returned when no redirect URL is configured for account connections
+var ErrConnectionsDisabled = errors.New("soundcloud account connections are not configured")
+
+// NewCodeVerifier returns a random PKCE code verifier as described in RFC 7636
+func NewCodeVerifier() (string, error) {
+	return randomURLToken(32)
+}
+
+// CodeChallenge derives the S256 code challenge sent with the authorization request from the verifier
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomURLToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthorizationURL returns the SoundCloud page on which a user grants access to their account. SoundCloud sends
+// the user back to the redirect URL with the state and an authorization code.
+func (sc *SoundCloudClient) AuthorizationURL(state, codeChallenge string) (string, error) {
+	if sc.config.RedirectURL == "" {
+		return "", ErrConnectionsDisabled
+	}
+	query := url.Values{}
+	query.Set("client_id", sc.config.ClientID)
+	query.Set("redirect_uri", sc.config.RedirectURL)
+	query.Set("response_type", "code")
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	query.Set("state", state)
+	return sc.config.AuthorizeURL + "?" + query.Encode(), nil
+}
+
+// ExchangeAuthorizationCode exchanges the code SoundCloud sent to the redirect URL for the user's token
+func (sc *SoundCloudClient) ExchangeAuthorizationCode(ctx context.Context, code, codeVerifier string) (*AccessTokenResponse, error) {
+	if sc.config.RedirectURL == "" {
+		return nil, ErrConnectionsDisabled
+	}
+	data := url.Values{}
+	data.Set("grant_type", grantAuthorizationCode)
+	data.Set("client_id", sc.config.ClientID)
+	data.Set("client_secret", sc.config.ClientSecret)
+	data.Set("redirect_uri", sc.config.RedirectURL)
+	data.Set("code_verifier", codeVerifier)
+	data.Set("code", code)
+	return sc.postTokenRequest(ctx, data)
+}
+
+// FetchMe fetches the user who owns the access token
+func (sc *SoundCloudClient) FetchMe(ctx context.Context, accessToken string) (*SCArtist, error) {
+	var me SCArtist
+	meURL := fmt.Sprintf("%s/me", sc.config.BaseURL)
+	if err := sc.getJSONAs(ctx, meURL, accessToken, &me); err != nil {
+		return nil, err
+	}
+	if me.ID == 0 {
+		return nil, &APIError{Kind: KindPermanent, URL: meURL, Err: errors.New("response has no user id")}
+	}
+	return &me, nil
+}
+
+// ResolveMediaAs resolves the URL of a track or playlist with a user's access token, which also resolves the
+// user's private share links
+func (sc *SoundCloudClient) ResolveMediaAs(ctx context.Context, mediaURL, accessToken string) (*SCMedia, error) {
+	var media SCMedia
+	resolveURL := fmt.Sprintf("%s/resolve?url=%s", sc.config.BaseURL, url.QueryEscape(mediaURL))
+	if err := sc.getJSONAs(ctx, resolveURL, accessToken, &media); err != nil {
+		return nil, err
+	}
+	if media.Kind != "track" && media.Kind != "playlist" {
+		return nil, &APIError{Kind: KindNotFound, URL: resolveURL, Err: errors.New("resolved resource is not a track or playlist")}
+	}
+	return &media, nil
+}
+
+// getJSONAs performs a GET request with a user's access token. Unlike getJSON a rejected token is not replaced,
+// the caller refreshes user tokens.
+func (sc *SoundCloudClient) getJSONAs(ctx context.Context, requestURL, accessToken string, v interface{}) error {
+	return sc.do(ctx, authorizedGet(ctx, requestURL, &OAuthToken{AccessToken: accessToken}), v)
+}
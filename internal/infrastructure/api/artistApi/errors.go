@@ -0,0 +1,126 @@
+package artistApi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/provider"
+)
+
+// ErrorKind classifies failed SoundCloud calls so callers can decide how to react
+type ErrorKind string
+
+const (
+	// KindNotFound means the resource does not exist, retrying will not help
+	KindNotFound ErrorKind = "not_found"
+	// KindRateLimited means SoundCloud asked us to slow down, retry after RetryAfter
+	KindRateLimited ErrorKind = "rate_limited"
+	// KindAuth means the credentials or token were rejected, a new token may succeed
+	KindAuth ErrorKind = "auth"
+	// KindTransient covers network failures, timeouts and server errors that may succeed on retry
+	KindTransient ErrorKind = "transient"
+	// KindPermanent covers any other rejected request, including access denied to a valid token, and responses that
+	// cannot be decoded
+	KindPermanent ErrorKind = "permanent"
+)
+
+// Sentinel errors matching an APIError of the same kind with errors.Is
+var (
+	ErrNotFound    = errors.New("soundcloud: not found")
+	ErrRateLimited = errors.New("soundcloud: rate limited")
+	ErrAuth        = errors.New("soundcloud: authorization failed")
+	ErrTransient   = errors.New("soundcloud: transient failure")
+	ErrPermanent   = errors.New("soundcloud: request failed")
+)
+
+// APIError is returned for every failed SoundCloud call
+type APIError struct {
+	Kind       ErrorKind
+	StatusCode int
+	// RetryAfter is the delay SoundCloud asked for, zero when it sent none
+	RetryAfter time.Duration
+	URL        string
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("soundcloud %s", e.Kind)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	if e.URL != "" {
+		msg += " for " + e.URL
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the sentinel of the error's kind, and provider.ErrNotFound for missing resources
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound, provider.ErrNotFound:
+		return e.Kind == KindNotFound
+	case ErrRateLimited:
+		return e.Kind == KindRateLimited
+	case ErrAuth:
+		return e.Kind == KindAuth
+	case ErrTransient:
+		return e.Kind == KindTransient
+	case ErrPermanent:
+		return e.Kind == KindPermanent
+	}
+	return false
+}
+
+// Retryable reports whether the call may succeed when repeated
+func (e *APIError) Retryable() bool {
+	return e.Kind == KindTransient || e.Kind == KindRateLimited
+}
+
+// classifyStatus turns a non-2xx response into an APIError
+func classifyStatus(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode, URL: resp.Request.URL.String()}
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		apiErr.Kind = KindNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		apiErr.Kind = KindRateLimited
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	case resp.StatusCode == http.StatusUnauthorized:
+		apiErr.Kind = KindAuth
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout:
+		apiErr.Kind = KindTransient
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	default:
+		apiErr.Kind = KindPermanent
+	}
+	return apiErr
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+package fakesoundcloud
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+)
+
+// Fixtures is the canned data served by the fake server. Tracks are keyed by user ID. Private tracks are only
+// served to their owner's token, except through oEmbed which works for their secret share links.
+type Fixtures struct {
+	Users         []artistApi.SCArtist        `json:"users"`
+	Tracks        map[int][]artistApi.SCTrack `json:"tracks"`
+	PrivateTracks map[int][]artistApi.SCTrack `json:"privateTracks"`
+}
+
+// LoadFixtures reads fixtures from a JSON file.
+func LoadFixtures(path string) (Fixtures, error) {
+	var fixtures Fixtures
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fixtures, err
+	}
+	err = json.Unmarshal(data, &fixtures)
+	return fixtures, err
+}
+
+// DefaultFixtures returns three artists, one of them with enough tracks to span several pages. The third shares
+// part of its name with the first so name searches return more than one candidate. The first also has a private
+// track shared through a secret link.
+func DefaultFixtures() Fixtures {
+	return Fixtures{
+		Users: []artistApi.SCArtist{
+			{
+				ID:          1001,
+				Permalink:   "fixture-artist",
+				Username:    "Fixture Artist",
+				FullName:    "Fixture Artist",
+				FirstName:   "Fixture",
+				LastName:    "Artist",
+				City:        "Berlin",
+				Country:     "Germany",
+				Description: "Resident at nowhere in particular.",
+				AvatarURL:   "https://i1.sndcdn.com/avatars-fixture-large.jpg",
+				Followers:   12500,
+			},
+			{
+				ID:        1002,
+				Permalink: "quiet-artist",
+				Username:  "Quiet Artist",
+				City:      "Lisbon",
+				Country:   "Portugal",
+				Followers: 40,
+			},
+			{
+				ID:        1003,
+				Permalink: "fixture-artist-fanpage",
+				Username:  "Fixture Artist Fanpage",
+				City:      "Ohio",
+				Country:   "United States",
+				Followers: 15,
+			},
+		},
+		Tracks: map[int][]artistApi.SCTrack{
+			1001: {
+				fixtureTrack(5001, "fixture-artist", "Opening Set", "Techno", 3600000, "2023/01/14 23:00:00 +0000"),
+				fixtureTrack(5002, "fixture-artist", "Closing Set", "Techno", 5400000, "2023/02/11 06:00:00 +0000"),
+				fixtureTrack(5003, "fixture-artist", "Radio Show 01", "House", 3540000, "2023/03/02 18:00:00 +0000"),
+				fixtureTrack(5004, "fixture-artist", "Radio Show 02", "House", 3580000, "2023/04/06 18:00:00 +0000"),
+				fixtureTrack(5005, "fixture-artist", "Edit", "Electronic", 412000, "2023/05/19 12:00:00 +0000"),
+			},
+		},
+		PrivateTracks: map[int][]artistApi.SCTrack{
+			1001: {
+				privateFixtureTrack(fixtureTrack(5101, "fixture-artist", "Unreleased Set", "Techno", 7200000, "2023/06/03 04:00:00 +0000"), "s-fixtureSecret"),
+			},
+		},
+	}
+}
+
+// privateFixtureTrack appends the secret token of the share link to the permalink URL
+func privateFixtureTrack(track artistApi.SCTrack, secret string) artistApi.SCTrack {
+	track.PermalinkURL += "/" + secret
+	return track
+}
+
+func fixtureTrack(id int, permalink, title, genre string, durationMs int, createdAt string) artistApi.SCTrack {
+	return artistApi.SCTrack{
+		ID:            id,
+		Title:         title,
+		Duration:      durationMs,
+		Genre:         genre,
+		PlaybackCount: id % 1000 * 100,
+		FavoriteCount: id % 1000 * 10,
+		CommentCount:  id % 1000,
+		RepostsCount:  id % 100,
+		PermalinkURL:  "https://soundcloud.com/" + permalink + "/" + strings.ReplaceAll(strings.ToLower(title), " ", "-"),
+		CreatedAt:     createdAt,
+	}
+}
@@ -0,0 +1,589 @@
+// Package fakesoundcloud serves a small, in-memory imitation of the SoundCloud API so the client and the sync
+// CLI can run without network access. It implements resolve, user search, users, me, tracks, user tracks with
+// linked_partitioning, oEmbed, the authorization page of the code flow and the oauth2/token endpoint, and can be
+// switched into failure modes.
+package fakesoundcloud
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+)
+
+// Mode makes the server fail requests in a specific way.
+type Mode string
+
+const (
+	ModeOK           Mode = ""
+	ModeUnauthorized Mode = "unauthorized"
+	ModeForbidden    Mode = "forbidden"
+	ModeNotFound     Mode = "not_found"
+	ModeRateLimited  Mode = "rate_limited"
+	ModeServerError  Mode = "server_error"
+	ModeMalformed    Mode = "malformed"
+	ModeSlow         Mode = "slow"
+)
+
+// Endpoint names used by Requests.
+const (
+	EndpointToken   = "token"
+	EndpointResolve = "resolve"
+	EndpointSearch  = "search"
+	EndpointUsers   = "users"
+	EndpointTracks  = "tracks"
+	EndpointTrack   = "track"
+	EndpointOEmbed  = "oembed"
+	EndpointMe      = "me"
+)
+
+// Server is the fake SoundCloud API. Use Handler with httptest.NewServer or http.ListenAndServe.
+type Server struct {
+	// PageSize caps the tracks per page regardless of the limit requested, zero means no cap
+	PageSize int
+	// Delay is applied to every request in ModeSlow
+	Delay time.Duration
+	// TokenTTL is the lifetime of issued access tokens
+	TokenTTL time.Duration
+	// RetryAfter is sent with rate limited responses
+	RetryAfter time.Duration
+
+	mu            sync.Mutex
+	fixtures      Fixtures
+	clientID      string
+	clientSecret  string
+	mode          Mode
+	failNext      int
+	failMode      Mode
+	accessTokens  map[string]time.Time
+	refreshTokens map[string]bool
+	requests      map[string]int
+	// signedIn is the user who grants access on the authorization page, zero declines
+	signedIn int
+	codes    map[string]authorizationCode
+	// tokenUsers maps access and refresh tokens issued through the code flow to their user
+	tokenUsers map[string]int
+}
+
+// authorizationCode is a code issued by the authorization page, redeemable once
+type authorizationCode struct {
+	userID        int
+	redirectURI   string
+	codeChallenge string
+}
+
+// New creates a fake serving the fixtures. An empty clientID accepts any client credentials.
+func New(fixtures Fixtures, clientID, clientSecret string) *Server {
+	return &Server{
+		Delay:         2 * time.Second,
+		TokenTTL:      time.Hour,
+		RetryAfter:    time.Second,
+		fixtures:      fixtures,
+		clientID:      clientID,
+		clientSecret:  clientSecret,
+		accessTokens:  make(map[string]time.Time),
+		refreshTokens: make(map[string]bool),
+		requests:      make(map[string]int),
+		codes:         make(map[string]authorizationCode),
+		tokenUsers:    make(map[string]int),
+	}
+}
+
+// SignIn makes the authorization page grant access to the user's account, zero makes it decline.
+func (s *Server) SignIn(userID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signedIn = userID
+}
+
+// SetMode makes every following request fail according to the mode, ModeOK restores normal behaviour.
+func (s *Server) SetMode(mode Mode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mode = mode
+}
+
+// FailNext makes the next n requests fail according to the mode, then serves normally again.
+func (s *Server) FailNext(n int, mode Mode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failNext = n
+	s.failMode = mode
+}
+
+// ExpireTokens invalidates every issued access token, refresh tokens stay valid.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for token := range s.accessTokens {
+		s.accessTokens[token] = time.Time{}
+	}
+}
+
+// RevokeRefreshTokens invalidates every issued refresh token.
+func (s *Server) RevokeRefreshTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshTokens = make(map[string]bool)
+}
+
+// Requests returns how many requests an endpoint has received.
+func (s *Server) Requests(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[endpoint]
+}
+
+// Handler returns the HTTP handler serving the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth2/token", s.handleToken)
+	mux.HandleFunc("/authorize", s.handleAuthorize)
+	mux.HandleFunc("/me", s.authorized(EndpointMe, s.handleMe))
+	mux.HandleFunc("/resolve", s.authorized(EndpointResolve, s.handleResolve))
+	mux.HandleFunc("/users", s.authorized(EndpointSearch, s.handleSearch))
+	mux.HandleFunc("/users/", s.handleUsers)
+	mux.HandleFunc("/tracks/", s.authorized(EndpointTrack, s.handleTrack))
+	mux.HandleFunc("/oembed", s.handleOEmbed)
+	return mux
+}
+
+// handleUsers routes /users/{id} and /users/{id}/tracks.
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/users/"), "/"), "/")
+	switch {
+	case len(parts) == 1:
+		s.authorized(EndpointUsers, func(w http.ResponseWriter, r *http.Request) {
+			s.handleUser(w, parts[0])
+		})(w, r)
+	case len(parts) == 2 && parts[1] == "tracks":
+		s.authorized(EndpointTracks, func(w http.ResponseWriter, r *http.Request) {
+			s.handleTracks(w, r, parts[0])
+		})(w, r)
+	default:
+		writeError(w, http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if s.failed(EndpointToken, w) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	if s.clientID != "" && (r.PostForm.Get("client_id") != s.clientID || r.PostForm.Get("client_secret") != s.clientSecret) {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var userID int
+	switch r.PostForm.Get("grant_type") {
+	case "client_credentials":
+	case "refresh_token":
+		refreshToken := r.PostForm.Get("refresh_token")
+		if !s.refreshTokens[refreshToken] {
+			writeError(w, http.StatusBadRequest)
+			return
+		}
+		delete(s.refreshTokens, refreshToken)
+		userID = s.tokenUsers[refreshToken]
+	case "authorization_code":
+		code, ok := s.codes[r.PostForm.Get("code")]
+		delete(s.codes, r.PostForm.Get("code"))
+		if !ok || code.redirectURI != r.PostForm.Get("redirect_uri") || codeChallenge(r.PostForm.Get("code_verifier")) != code.codeChallenge {
+			writeError(w, http.StatusBadRequest)
+			return
+		}
+		userID = code.userID
+	default:
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+
+	response := artistApi.AccessTokenResponse{
+		AccessToken:  randomToken(),
+		RefreshToken: randomToken(),
+		ExpiresIn:    int(s.TokenTTL / time.Second),
+		Scope:        "",
+	}
+	s.accessTokens[response.AccessToken] = time.Now().Add(s.TokenTTL)
+	s.refreshTokens[response.RefreshToken] = true
+	if userID != 0 {
+		response.Scope = "non-expiring"
+		s.tokenUsers[response.AccessToken] = userID
+		s.tokenUsers[response.RefreshToken] = userID
+	}
+	writeJSON(w, response)
+}
+
+// handleAuthorize plays the authorization page: it sends the browser back to the redirect URI with a code for the
+// signed in user, or with error=access_denied when nobody is signed in.
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirect.Scheme == "" || query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	if s.clientID != "" && query.Get("client_id") != s.clientID {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	params := redirect.Query()
+	params.Set("state", query.Get("state"))
+	if s.signedIn == 0 {
+		params.Set("error", "access_denied")
+	} else {
+		code := randomToken()
+		s.codes[code] = authorizationCode{userID: s.signedIn, redirectURI: query.Get("redirect_uri"), codeChallenge: query.Get("code_challenge")}
+		params.Set("code", code)
+	}
+	s.mu.Unlock()
+
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// handleMe returns the user who granted the token, app tokens have no user.
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	userID := s.tokenUsers[requestToken(r)]
+	for _, user := range s.fixtures.Users {
+		if userID != 0 && user.ID == userID {
+			writeJSON(w, user)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound)
+}
+
+func (s *Server) handleResolve(w http.ResponseWriter, r *http.Request) {
+	target, err := url.Parse(r.URL.Query().Get("url"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	segments := strings.Split(strings.Trim(target.Path, "/"), "/")
+	permalink := strings.ToLower(segments[0])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(segments) > 1 {
+		if track, ok := s.findTrack(target.Path, s.tokenUsers[requestToken(r)]); ok {
+			http.Redirect(w, r, fmt.Sprintf("/tracks/%d", track.ID), http.StatusFound)
+			return
+		}
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	for _, user := range s.fixtures.Users {
+		if strings.ToLower(user.Permalink) == permalink {
+			// SoundCloud answers resolve with a redirect to the resource
+			http.Redirect(w, r, fmt.Sprintf("/users/%d", user.ID), http.StatusFound)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound)
+}
+
+// handleSearch returns the users whose username, full name or permalink contains the query, ignoring case.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	users := []artistApi.SCArtist{}
+	for _, user := range s.fixtures.Users {
+		if len(users) == limit {
+			break
+		}
+		for _, field := range []string{user.Username, user.FullName, user.Permalink} {
+			if query != "" && strings.Contains(strings.ToLower(field), query) {
+				users = append(users, user)
+				break
+			}
+		}
+	}
+	writeJSON(w, users)
+}
+
+func (s *Server) handleUser(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.fixtures.Users {
+		if strconv.Itoa(user.ID) == id {
+			writeJSON(w, user)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound)
+}
+
+func (s *Server) handleTracks(w http.ResponseWriter, r *http.Request, id string) {
+	userID, err := strconv.Atoi(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.hasUser(userID) {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	tracks := s.fixtures.Tracks[userID]
+
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	if s.PageSize > 0 && limit > s.PageSize {
+		limit = s.PageSize
+	}
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	if offset > len(tracks) {
+		offset = len(tracks)
+	}
+	end := offset + limit
+	if end > len(tracks) {
+		end = len(tracks)
+	}
+
+	page := struct {
+		Collection []artistApi.SCTrack `json:"collection"`
+		NextHref   string              `json:"next_href,omitempty"`
+	}{Collection: tracks[offset:end]}
+	if page.Collection == nil {
+		page.Collection = []artistApi.SCTrack{}
+	}
+
+	if query.Get("linked_partitioning") != "" && end < len(tracks) {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		next := url.Values{}
+		next.Set("linked_partitioning", "true")
+		next.Set("limit", strconv.Itoa(limit))
+		next.Set("offset", strconv.Itoa(end))
+		page.NextHref = fmt.Sprintf("%s://%s/users/%d/tracks?%s", scheme, r.Host, userID, next.Encode())
+	}
+	writeJSON(w, page)
+}
+
+// fakeTrack is a track as returned by resolve, which tells the kind of the resource
+type fakeTrack struct {
+	artistApi.SCTrack
+	Kind string `json:"kind"`
+}
+
+func (s *Server) handleTrack(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/tracks/"), "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, track := range s.visibleTracks(s.tokenUsers[requestToken(r)]) {
+		if strconv.Itoa(track.ID) == id {
+			writeJSON(w, fakeTrack{SCTrack: track, Kind: "track"})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound)
+}
+
+// handleOEmbed describes a track like soundcloud.com/oembed does. It needs no token.
+func (s *Server) handleOEmbed(w http.ResponseWriter, r *http.Request) {
+	if s.failed(EndpointOEmbed, w) {
+		return
+	}
+	target, err := url.Parse(r.URL.Query().Get("url"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	track, ok := s.findTrack(target.Path, anyUser)
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	author := s.trackAuthor(track.ID)
+
+	writeJSON(w, artistApi.SCOEmbed{
+		Version:      "1.0",
+		Type:         "rich",
+		ProviderName: "SoundCloud",
+		Title:        fmt.Sprintf("%s by %s", track.Title, author.Username),
+		ThumbnailURL: author.AvatarURL,
+		HTML: fmt.Sprintf(`<iframe width="100%%" height="400" scrolling="no" frameborder="no" src="https://w.soundcloud.com/player/?visual=true&url=%s"></iframe>`,
+			url.QueryEscape(track.PermalinkURL)),
+		AuthorName: author.Username,
+		AuthorURL:  "https://soundcloud.com/" + author.Permalink,
+	})
+}
+
+// anyUser makes visibleTracks include every private track
+const anyUser = -1
+
+// visibleTracks returns the public tracks and the private tracks of the user.
+func (s *Server) visibleTracks(userID int) []artistApi.SCTrack {
+	var visible []artistApi.SCTrack
+	for _, tracks := range s.fixtures.Tracks {
+		visible = append(visible, tracks...)
+	}
+	for owner, tracks := range s.fixtures.PrivateTracks {
+		if owner == userID || userID == anyUser {
+			visible = append(visible, tracks...)
+		}
+	}
+	return visible
+}
+
+// findTrack looks a track visible to the user up by the path of its permalink URL.
+func (s *Server) findTrack(path string, userID int) (artistApi.SCTrack, bool) {
+	path = strings.ToLower(strings.Trim(path, "/"))
+	for _, track := range s.visibleTracks(userID) {
+		permalinkURL, err := url.Parse(track.PermalinkURL)
+		if err == nil && strings.ToLower(strings.Trim(permalinkURL.Path, "/")) == path {
+			return track, true
+		}
+	}
+	return artistApi.SCTrack{}, false
+}
+
+func (s *Server) trackAuthor(trackID int) artistApi.SCArtist {
+	for _, user := range s.fixtures.Users {
+		for _, tracks := range [][]artistApi.SCTrack{s.fixtures.Tracks[user.ID], s.fixtures.PrivateTracks[user.ID]} {
+			for _, track := range tracks {
+				if track.ID == trackID {
+					return user
+				}
+			}
+		}
+	}
+	return artistApi.SCArtist{}
+}
+
+func (s *Server) hasUser(id int) bool {
+	for _, user := range s.fixtures.Users {
+		if user.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// authorized counts the request, applies the failure mode and checks the OAuth header before calling next.
+func (s *Server) authorized(endpoint string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.failed(endpoint, w) {
+			return
+		}
+		token := requestToken(r)
+
+		s.mu.Lock()
+		expiry, ok := s.accessTokens[token]
+		s.mu.Unlock()
+
+		if !ok || time.Now().After(expiry) {
+			writeError(w, http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// failed counts the request and writes the failure for the active mode. It reports whether the request was handled.
+func (s *Server) failed(endpoint string, w http.ResponseWriter) bool {
+	s.mu.Lock()
+	s.requests[endpoint]++
+	mode := s.mode
+	if s.failNext > 0 {
+		s.failNext--
+		mode = s.failMode
+	}
+	delay, retryAfter := s.Delay, s.RetryAfter
+	s.mu.Unlock()
+
+	switch mode {
+	case ModeOK:
+		return false
+	case ModeSlow:
+		time.Sleep(delay)
+		return false
+	case ModeUnauthorized:
+		writeError(w, http.StatusUnauthorized)
+	case ModeForbidden:
+		writeError(w, http.StatusForbidden)
+	case ModeNotFound:
+		writeError(w, http.StatusNotFound)
+	case ModeRateLimited:
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+		writeError(w, http.StatusTooManyRequests)
+	case ModeServerError:
+		writeError(w, http.StatusInternalServerError)
+	case ModeMalformed:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write([]byte(`{"collection": [`))
+	default:
+		writeError(w, http.StatusInternalServerError)
+	}
+	return true
+}
+
+func requestToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "OAuth ")
+}
+
+// codeChallenge derives the S256 PKCE challenge of a verifier
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": http.StatusText(status)})
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -2,27 +2,29 @@ package artistApi
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
-	"io"
-	"log"
+	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
+	"github.com/blnto/blnto_service/internal/infrastructure/secrets"
 	"gorm.io/gorm"
 )
 
 const (
-	tokenURL     = "https://api.soundcloud.com/oauth2/token" // Token endpoint
-	clientID     = "tSJvk12uBEeL3YjyxxQ4IDloTcloGJzl"
-	clientSecret = "JU9HgHJ7VXJgu4lzFH6BqscnqUOwQj7D"
+	tokenURL = "https://api.soundcloud.com/oauth2/token" // Token endpoint
 )
 
+// OAuthToken is a SoundCloud token. In the database AccessToken and RefreshToken hold ciphertexts sealed with
+// the key named by KeyID; an empty KeyID marks a row written before encryption was introduced.
 type OAuthToken struct {
 	gorm.Model
-	AccessToken        string    `gorm:"type:varchar(255);not null"`
-	RefreshToken       string    `gorm:"type:varchar(255);not null"`
+	AccessToken        string    `gorm:"type:text;not null"`
+	RefreshToken       string    `gorm:"type:text;not null"`
+	KeyID              string    `gorm:"type:varchar(64);not null;default:''"`
 	AccessTokenExpiry  time.Time `gorm:"not null"`
 	RefreshTokenExpiry time.Time
 }
@@ -40,159 +42,191 @@ func (OAuthToken) TableName() string {
 	return "oauth_tokens"
 }
 
-// RequestAccessToken requests an access token using client credentials
-func RequestAccessToken() (*AccessTokenResponse, error) {
-	data := url.Values{}
-	data.Set("grant_type", "client_credentials")
-	data.Set("client_id", clientID)
-	data.Set("client_secret", clientSecret)
+// TokenStore persists the OAuth token used for SoundCloud requests
+type TokenStore interface {
+	// Latest returns the most recent token, or nil if none is stored
+	Latest() (*OAuthToken, error)
+	// Save stores the token from a token response
+	Save(token *AccessTokenResponse) (*OAuthToken, error)
+}
 
-	req, err := http.NewRequest("POST", tokenURL, bytes.NewBufferString(data.Encode()))
-	if err != nil {
-		return nil, err
-	}
+// DBTokenStore keeps the token in the oauth_tokens table, encrypted with the keyring's current key
+type DBTokenStore struct {
+	db      *gorm.DB
+	keyring *secrets.Keyring
+}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Accept", "application/json; charset=utf-8")
+func NewDBTokenStore(db *gorm.DB, keyring *secrets.Keyring) *DBTokenStore {
+	return &DBTokenStore{db: db, keyring: keyring}
+}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
+func (s *DBTokenStore) Latest() (*OAuthToken, error) {
+	var token OAuthToken
+	result := s.db.Order("access_token_expiry DESC").First(&token)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// No token record found
+			return nil, nil
 		}
-	}(resp.Body)
-
-	var tokenResponse AccessTokenResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return nil, result.Error
+	}
+	if err := s.decrypt(&token); err != nil {
 		return nil, err
 	}
-
-	return &tokenResponse, nil
+	return &token, nil
 }
 
-func RefreshAccessToken(clientID, clientSecret, refreshToken string) (*AccessTokenResponse, error) {
-	data := url.Values{}
-	data.Set("grant_type", "refresh_token")
-	data.Set("client_id", clientID)
-	data.Set("client_secret", clientSecret)
-	data.Set("refresh_token", refreshToken)
+func (s *DBTokenStore) Save(token *AccessTokenResponse) (*OAuthToken, error) {
+	expiryTime := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
 
-	req, err := http.NewRequest("POST", tokenURL, bytes.NewBufferString(data.Encode()))
-	if err != nil {
-		return nil, err
+	// A single token row is kept and overwritten
+	var existingToken OAuthToken
+	result := s.db.First(&existingToken)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, result.Error
 	}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Accept", "application/json; charset=utf-8")
+	existingToken.AccessToken = token.AccessToken
+	existingToken.RefreshToken = token.RefreshToken
+	existingToken.AccessTokenExpiry = expiryTime
+	existingToken.RefreshTokenExpiry = expiryTime
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
+	stored := existingToken
+	if err := s.encrypt(&stored); err != nil {
 		return nil, err
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-		}
-	}(resp.Body)
-
-	var tokenResponse AccessTokenResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+	if err := s.db.Save(&stored).Error; err != nil {
 		return nil, err
 	}
 
-	return &tokenResponse, nil
+	existingToken.Model = stored.Model
+	existingToken.KeyID = stored.KeyID
+	return &existingToken, nil
 }
 
-func StartTokenRefreshScheduler(db *gorm.DB) {
-	ticker := time.NewTicker(30 * time.Minute) // Adjust the duration according to your needs
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				refreshTokenIfNeeded(db, clientID, clientSecret)
-			}
+// Reencrypt rewrites every token row not sealed with the current key, including plaintext rows from before
+// encryption, and returns the number of rows rewritten.
+func (s *DBTokenStore) Reencrypt() (int, error) {
+	var tokens []OAuthToken
+	if err := s.db.Unscoped().Where("key_id <> ?", s.keyring.CurrentKeyID()).Find(&tokens).Error; err != nil {
+		return 0, err
+	}
+
+	for i := range tokens {
+		if err := s.decrypt(&tokens[i]); err != nil {
+			return i, fmt.Errorf("token %d: %w", tokens[i].ID, err)
+		}
+		if err := s.encrypt(&tokens[i]); err != nil {
+			return i, fmt.Errorf("token %d: %w", tokens[i].ID, err)
+		}
+		err := s.db.Unscoped().Model(&OAuthToken{}).Where("id = ?", tokens[i].ID).Updates(map[string]interface{}{
+			"access_token":  tokens[i].AccessToken,
+			"refresh_token": tokens[i].RefreshToken,
+			"key_id":        tokens[i].KeyID,
+		}).Error
+		if err != nil {
+			return i, fmt.Errorf("token %d: %w", tokens[i].ID, err)
 		}
-	}()
+	}
+	return len(tokens), nil
 }
 
-func refreshTokenIfNeeded(db *gorm.DB, clientID, clientSecret string) {
-	// Retrieve the latest token from the database
-	token, err := GetLatestToken(db)
+func (s *DBTokenStore) encrypt(token *OAuthToken) error {
+	keyID, accessToken, err := s.keyring.Encrypt(token.AccessToken)
 	if err != nil {
-		log.Printf("Error retrieving token: %v", err)
-		return
+		return fmt.Errorf("error encrypting access token: %w", err)
 	}
+	_, refreshToken, err := s.keyring.Encrypt(token.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("error encrypting refresh token: %w", err)
+	}
+	token.KeyID, token.AccessToken, token.RefreshToken = keyID, accessToken, refreshToken
+	return nil
+}
 
-	// Check if the token is close to expiry
-	if time.Now().Add(10 * time.Minute).After(token.AccessTokenExpiry) { // 10 minutes before expiry
-		log.Printf("Asking for new token")
-		refreshedToken, err := RefreshAccessToken(clientID, clientSecret, token.RefreshToken)
-		if err != nil {
-			log.Printf("Error refreshing token: %v", err)
-			return
-		}
+func (s *DBTokenStore) decrypt(token *OAuthToken) error {
+	// Rows written before encryption hold plaintext
+	if token.KeyID == "" {
+		return nil
+	}
+	accessToken, err := s.keyring.Decrypt(token.KeyID, token.AccessToken)
+	if err != nil {
+		return fmt.Errorf("error decrypting access token: %w", err)
+	}
+	refreshToken, err := s.keyring.Decrypt(token.KeyID, token.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("error decrypting refresh token: %w", err)
+	}
+	token.AccessToken, token.RefreshToken = accessToken, refreshToken
+	return nil
+}
 
-		log.Printf("Save new token for new token: %v", refreshedToken.AccessToken)
-		// Save the refreshed token
-		result, err := SaveToken(db, refreshedToken)
-		if err != nil {
-			log.Printf("Error saving refreshed token: %v", err)
-		}
-		log.Printf("Saved new token: %v", result.AccessToken)
+// MemoryTokenStore keeps the token in memory, for offline runs and tests
+type MemoryTokenStore struct {
+	mu    sync.Mutex
+	token *OAuthToken
+}
+
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{}
+}
+
+func (s *MemoryTokenStore) Latest() (*OAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == nil {
+		return nil, nil
 	}
+	token := *s.token
+	return &token, nil
 }
 
-func SaveToken(db *gorm.DB, token *AccessTokenResponse) (*OAuthToken, error) {
+func (s *MemoryTokenStore) Save(token *AccessTokenResponse) (*OAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	expiryTime := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
-
-	// Assuming you have a way to identify the existing token record (e.g., a single record or based on client ID)
-	var existingToken OAuthToken
-	result := db.First(&existingToken)
-
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		// Insert new token if no existing token is found
-		newToken := OAuthToken{
-			AccessToken:        token.AccessToken,
-			RefreshToken:       token.RefreshToken,
-			AccessTokenExpiry:  expiryTime,
-			RefreshTokenExpiry: expiryTime,
-		}
-		result = db.Create(&newToken)
-		if result.Error != nil {
-			return nil, result.Error
-		}
-		return &newToken, nil
-	} else if result.Error != nil {
-		return nil, result.Error
+	s.token = &OAuthToken{
+		AccessToken:        token.AccessToken,
+		RefreshToken:       token.RefreshToken,
+		AccessTokenExpiry:  expiryTime,
+		RefreshTokenExpiry: expiryTime,
 	}
+	stored := *s.token
+	return &stored, nil
+}
 
-	// Update existing token
-	existingToken.AccessToken = token.AccessToken
-	existingToken.RefreshToken = token.RefreshToken
-	existingToken.AccessTokenExpiry = expiryTime
-	existingToken.RefreshTokenExpiry = expiryTime
+// RequestAccessToken requests an access token using client credentials
+func (sc *SoundCloudClient) RequestAccessToken(ctx context.Context) (*AccessTokenResponse, error) {
+	data := url.Values{}
+	data.Set("grant_type", "client_credentials")
+	data.Set("client_id", sc.config.ClientID)
+	data.Set("client_secret", sc.config.ClientSecret)
+	return sc.postTokenRequest(ctx, data)
+}
 
-	result = db.Save(&existingToken)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return &existingToken, nil
+// RefreshAccessToken exchanges a refresh token for a new access token
+func (sc *SoundCloudClient) RefreshAccessToken(ctx context.Context, refreshToken string) (*AccessTokenResponse, error) {
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("client_id", sc.config.ClientID)
+	data.Set("client_secret", sc.config.ClientSecret)
+	data.Set("refresh_token", refreshToken)
+	return sc.postTokenRequest(ctx, data)
 }
 
-func GetLatestToken(db *gorm.DB) (*OAuthToken, error) {
-	var token OAuthToken
-	result := db.Order("access_token_expiry DESC").First(&token)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			// No token record found
-			return nil, nil
+func (sc *SoundCloudClient) postTokenRequest(ctx context.Context, data url.Values) (*AccessTokenResponse, error) {
+	var tokenResponse AccessTokenResponse
+	err := sc.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", sc.config.TokenURL, bytes.NewBufferString(data.Encode()))
+		if err != nil {
+			return nil, err
 		}
-		return nil, result.Error
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Accept", "application/json; charset=utf-8")
+		return req, nil
+	}, &tokenResponse)
+	if err != nil {
+		return nil, err
 	}
-	return &token, nil
+	return &tokenResponse, nil
 }
@@ -0,0 +1,147 @@
+package artistApi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// tokenBucket limits the request rate: it holds up to burst tokens and refills at rate tokens per second
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait blocks until a token is available or the context is done
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker stops calls after threshold consecutive failures. Once cooldown has passed a single probe
+// call is let through; its success closes the breaker, its failure opens it again.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Allow reports whether a call may proceed, and if not, when calls will be allowed again
+func (cb *circuitBreaker) Allow() (bool, time.Time) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case breakerOpen:
+		retryAt := cb.openedAt.Add(cb.cooldown)
+		if time.Now().Before(retryAt) {
+			return false, retryAt
+		}
+		cb.state = breakerHalfOpen
+		cb.probing = true
+		return true, time.Time{}
+	case breakerHalfOpen:
+		if cb.probing {
+			return false, time.Now().Add(cb.cooldown)
+		}
+		cb.probing = true
+		return true, time.Time{}
+	}
+	return true, time.Time{}
+}
+
+// Success records a successful call and closes the breaker
+func (cb *circuitBreaker) Success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.state = breakerClosed
+	cb.failures = 0
+	cb.probing = false
+}
+
+// Failure records a failed call and opens the breaker when the threshold is reached or a probe failed
+func (cb *circuitBreaker) Failure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures++
+	cb.probing = false
+	if cb.state == breakerHalfOpen || cb.failures >= cb.threshold {
+		cb.state = breakerOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// Cancel releases the probe of a call that ended without an outcome, e.g. because its context was cancelled,
+// so the next call may probe instead of the breaker staying half-open for good
+func (cb *circuitBreaker) Cancel() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == breakerHalfOpen {
+		cb.probing = false
+	}
+}
+
+// backoff returns the delay before retry attempt n (starting at 0): base doubled per attempt, capped at max
+func backoff(base, max time.Duration, attempt int) time.Duration {
+	delay := base << uint(attempt)
+	if delay <= 0 || delay > max {
+		return max
+	}
+	return delay
+}
+
+// sleep waits for d or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -1,26 +1,161 @@
 package artistApi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
-	"go.uber.org/zap"
-	"gorm.io/gorm"
+	"github.com/blnto/blnto_service/internal/domain/provider"
 )
 
 const (
 	baseAPIURL = "https://api.soundcloud.com"
+	oEmbedURL  = "https://soundcloud.com/oembed"
+
+	// The example values in .env, rejected so a copied .env fails at startup instead of at the first request
+	placeholderClientID     = "your_client_id"
+	placeholderClientSecret = "your_client_secret"
 )
 
+// Config holds the settings of the SoundCloud client. Zero values fall back to DefaultConfig.
+type Config struct {
+	BaseURL      string
+	TokenURL     string
+	OEmbedURL    string
+	AuthorizeURL string
+	// RedirectURL is where SoundCloud sends artists back after they connected their account, empty disables
+	// account connections
+	RedirectURL  string
+	ClientID     string
+	ClientSecret string
+	Timeout      time.Duration
+	// Transport is used for every request, nil means http.DefaultTransport
+	Transport http.RoundTripper
+	// RequestsPerSecond and Burst configure the client-side token bucket
+	RequestsPerSecond float64
+	Burst             int
+	// MaxRetries is the number of retries for transient and rate limited failures, negative disables retries
+	MaxRetries  int
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between retries; a longer Retry-After is returned to the caller instead
+	MaxBackoff time.Duration
+	// BreakerThreshold consecutive failures open the circuit breaker for BreakerCooldown
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// TokenRefreshBefore is how long before expiry the token manager's Run loop refreshes the token
+	TokenRefreshBefore time.Duration
+	// AuthorizationTTL is how long an artist has to complete connecting their account
+	AuthorizationTTL time.Duration
+}
+
+// DefaultConfig returns the settings for the public SoundCloud API. Credentials have no default.
+func DefaultConfig() Config {
+	return Config{
+		BaseURL:      baseAPIURL,
+		TokenURL:     tokenURL,
+		OEmbedURL:    oEmbedURL,
+		AuthorizeURL: authorizeURL,
+		Timeout:      10 * time.Second,
+
+		RequestsPerSecond: 2,
+		Burst:             5,
+		MaxRetries:        3,
+		BaseBackoff:       500 * time.Millisecond,
+		MaxBackoff:        30 * time.Second,
+		BreakerThreshold:  5,
+		BreakerCooldown:   time.Minute,
+
+		TokenRefreshBefore: 10 * time.Minute,
+		AuthorizationTTL:   10 * time.Minute,
+	}
+}
+
+// Validate checks that the credentials are set to real values and the URLs are absolute, after applying the defaults.
+func (c Config) Validate() error {
+	c = c.withDefaults()
+	var errs []error
+	if c.ClientID == "" || c.ClientID == placeholderClientID {
+		errs = append(errs, errors.New("soundcloud client id is not configured"))
+	}
+	if c.ClientSecret == "" || c.ClientSecret == placeholderClientSecret {
+		errs = append(errs, errors.New("soundcloud client secret is not configured"))
+	}
+	urls := map[string]string{"base url": c.BaseURL, "token url": c.TokenURL, "oembed url": c.OEmbedURL, "authorize url": c.AuthorizeURL}
+	if c.RedirectURL != "" {
+		urls["redirect url"] = c.RedirectURL
+	}
+	for name, raw := range urls {
+		if u, err := url.Parse(raw); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("soundcloud %s %q is not an absolute URL", name, raw))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.BaseURL == "" {
+		c.BaseURL = defaults.BaseURL
+	}
+	if c.TokenURL == "" {
+		c.TokenURL = defaults.TokenURL
+	}
+	if c.OEmbedURL == "" {
+		c.OEmbedURL = defaults.OEmbedURL
+	}
+	if c.AuthorizeURL == "" {
+		c.AuthorizeURL = defaults.AuthorizeURL
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaults.Timeout
+	}
+	if c.RequestsPerSecond <= 0 {
+		c.RequestsPerSecond = defaults.RequestsPerSecond
+	}
+	if c.Burst <= 0 {
+		c.Burst = defaults.Burst
+	}
+	if c.MaxRetries == 0 {
+		c.MaxRetries = defaults.MaxRetries
+	} else if c.MaxRetries < 0 {
+		c.MaxRetries = 0
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = defaults.BaseBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = defaults.MaxBackoff
+	}
+	if c.BreakerThreshold <= 0 {
+		c.BreakerThreshold = defaults.BreakerThreshold
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = defaults.BreakerCooldown
+	}
+	if c.TokenRefreshBefore <= 0 {
+		c.TokenRefreshBefore = defaults.TokenRefreshBefore
+	}
+	if c.AuthorizationTTL <= 0 {
+		c.AuthorizationTTL = defaults.AuthorizationTTL
+	}
+	c.BaseURL = strings.TrimSuffix(c.BaseURL, "/")
+	return c
+}
+
 // SoundCloudClient holds the configuration for the API client
 type SoundCloudClient struct {
-	DB           *gorm.DB
-	ClientID     string
-	clientSecret string
+	tokens     *TokenManager
+	config     Config
+	httpClient *http.Client
+	limiter    *tokenBucket
+	breaker    *circuitBreaker
 }
 
 // SCArtist Artist represents the artist data structure for SoundCloud
@@ -35,133 +170,285 @@ type SCArtist struct {
 	Description string `json:"description"`
 	Country     string `json:"country"`
 	Permalink   string `json:"permalink"`
+	Followers   int    `json:"followers_count"`
 }
 
 type SCTrack struct {
+	ID            int    `json:"id"`
+	Title         string `json:"title"`
+	Duration      int    `json:"duration"` // milliseconds
+	ArtworkURL    string `json:"artwork_url"`
+	Genre         string `json:"genre"`
+	PlaybackCount int    `json:"playback_count"`
+	FavoriteCount int    `json:"favoritings_count"`
+	CommentCount  int    `json:"comment_count"`
+	RepostsCount  int    `json:"reposts_count"`
+	PermalinkURL  string `json:"permalink_url"`
+	CreatedAt     string `json:"created_at"`
 	HTTPMp3URL    string `json:"http_mp3_128_url"`
 	HLSMp3URL     string `json:"hls_mp3_128_url"`
 	HLSOpusURL    string `json:"hls_opus_64_url"`
 	PreviewMp3URL string `json:"preview_mp3_128_url"`
 }
 
-func NewClient(db *gorm.DB) *SoundCloudClient {
-	return &SoundCloudClient{DB: db}
+// SCMedia is a track or playlist as returned by resolve
+type SCMedia struct {
+	ID           int    `json:"id"`
+	Kind         string `json:"kind"`
+	Title        string `json:"title"`
+	Duration     int    `json:"duration"` // milliseconds
+	ArtworkURL   string `json:"artwork_url"`
+	PermalinkURL string `json:"permalink_url"`
 }
 
-// FetchArtistByPermalink fetches an artist by their permalink
-func (sc *SoundCloudClient) FetchArtistByPermalink(permalink string) (*SCArtist, error) {
-	token, err := GetLatestToken(sc.DB)
+// SCOEmbed is the oEmbed description of a track or playlist
+type SCOEmbed struct {
+	Version      string `json:"version"`
+	Type         string `json:"type"`
+	ProviderName string `json:"provider_name"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	HTML         string `json:"html"`
+	AuthorName   string `json:"author_name"`
+	AuthorURL    string `json:"author_url"`
+}
 
-	if err != nil {
-		return nil, err
-	}
-	// Check if the token is close to expiry and refresh if necessary
-	if time.Now().After(token.AccessTokenExpiry) {
+// scTrackPage is a page of tracks returned when linked_partitioning is enabled
+type scTrackPage struct {
+	Collection []SCTrack `json:"collection"`
+	NextHref   string    `json:"next_href"`
+}
 
-		refreshedToken, refreshErr := RefreshAccessToken(clientID, clientSecret, token.RefreshToken)
-		if refreshErr != nil {
-			return nil, err
-		}
+// tracksPageSize is the largest page SoundCloud serves
+const tracksPageSize = 200
 
-		token, err = SaveToken(sc.DB, refreshedToken)
+// scTimeLayout is the timestamp format used by the SoundCloud API
+const scTimeLayout = "2006/01/02 15:04:05 -0700"
 
-		if err != nil {
-			zap.L().Error("failed to save token", zap.Error(err))
-			return nil, err
+// PublishedAt parses the track's creation time, or returns nil when SoundCloud sent an unknown format.
+func (t SCTrack) PublishedAt() *time.Time {
+	for _, layout := range []string{scTimeLayout, time.RFC3339} {
+		if parsed, err := time.Parse(layout, t.CreatedAt); err == nil {
+			return &parsed
 		}
 	}
+	return nil
+}
 
-	// Construct the request URL
-	resolveURL := fmt.Sprintf("https://api.soundcloud.com/resolve?url=%s", url.QueryEscape(permalink))
-	// Create an HTTP client and request
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", resolveURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create new request: %w", err)
+func NewClient(tokens TokenStore, config Config) *SoundCloudClient {
+	config = config.withDefaults()
+	sc := &SoundCloudClient{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout, Transport: config.Transport},
+		limiter:    newTokenBucket(config.RequestsPerSecond, config.Burst),
+		breaker:    newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
 	}
+	sc.tokens = NewTokenManager(tokens, sc, config.TokenRefreshBefore)
+	return sc
+}
 
-	// Set headers
-	req.Header.Set("Accept", "application/json; charset=utf-8")
-	req.Header.Set("Authorization", fmt.Sprintf("OAuth %s", token.AccessToken))
+// Tokens returns the manager of the client's OAuth token
+func (sc *SoundCloudClient) Tokens() *TokenManager {
+	return sc.tokens
+}
 
-	// Perform the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+// FetchArtistByPermalink fetches an artist by their permalink
+func (sc *SoundCloudClient) FetchArtistByPermalink(ctx context.Context, permalink string) (*SCArtist, error) {
+	var artist SCArtist
+	resolveURL := fmt.Sprintf("%s/resolve?url=%s", sc.config.BaseURL, url.QueryEscape(permalink))
+	if err := sc.getJSON(ctx, resolveURL, &artist); err != nil {
+		return nil, err
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-		}
-	}(resp.Body)
+	if artist.ID == 0 {
+		return nil, &APIError{Kind: KindNotFound, URL: resolveURL, Err: errors.New("resolved resource is not a user")}
+	}
+	return &artist, nil
+}
 
-	// Decode the response
+// FetchArtistById fetches an artist by their SoundCloud ID
+func (sc *SoundCloudClient) FetchArtistById(ctx context.Context, id string) (*SCArtist, error) {
 	var artist SCArtist
-	if err := json.NewDecoder(resp.Body).Decode(&artist); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	userURL := fmt.Sprintf("%s/users/%s", sc.config.BaseURL, url.PathEscape(id))
+	if err := sc.getJSON(ctx, userURL, &artist); err != nil {
+		return nil, err
+	}
+	if artist.ID == 0 {
+		return nil, &APIError{Kind: KindPermanent, URL: userURL, Err: errors.New("response has no user id")}
 	}
-
 	return &artist, nil
 }
 
-// FetchArtistById fetches an artist by their SoundCloud ID
-func (sc *SoundCloudClient) FetchArtistById(id string) (*SCArtist, error) {
-	// Implement the API call here
-	token, err := GetLatestToken(sc.DB)
+// SearchUsers searches SoundCloud users by name
+func (sc *SoundCloudClient) SearchUsers(ctx context.Context, query string, limit int) ([]SCArtist, error) {
+	var users []SCArtist
+	searchURL := fmt.Sprintf("%s/users?q=%s&limit=%d", sc.config.BaseURL, url.QueryEscape(query), limit)
+	if err := sc.getJSON(ctx, searchURL, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
 
+// ResolveMedia resolves the URL of a track or playlist
+func (sc *SoundCloudClient) ResolveMedia(ctx context.Context, mediaURL string) (*SCMedia, error) {
+	var media SCMedia
+	resolveURL := fmt.Sprintf("%s/resolve?url=%s", sc.config.BaseURL, url.QueryEscape(mediaURL))
+	if err := sc.getJSON(ctx, resolveURL, &media); err != nil {
+		return nil, err
+	}
+	if media.Kind != "track" && media.Kind != "playlist" {
+		return nil, &APIError{Kind: KindNotFound, URL: resolveURL, Err: errors.New("resolved resource is not a track or playlist")}
+	}
+	return &media, nil
+}
+
+// FetchOEmbed fetches the oEmbed description of a track or playlist. The oEmbed endpoint is public and is called
+// without a token.
+func (sc *SoundCloudClient) FetchOEmbed(ctx context.Context, mediaURL string) (*SCOEmbed, error) {
+	var embed SCOEmbed
+	requestURL := fmt.Sprintf("%s?format=json&url=%s", sc.config.OEmbedURL, url.QueryEscape(mediaURL))
+	err := sc.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json; charset=utf-8")
+		return req, nil
+	}, &embed)
 	if err != nil {
 		return nil, err
 	}
-	// Check if the token is close to expiry and refresh if necessary
-	if time.Now().After(token.AccessTokenExpiry) {
-		refreshedToken, refreshErr := RefreshAccessToken(clientID, clientSecret, token.RefreshToken)
-		if refreshErr != nil {
+	if embed.HTML == "" {
+		return nil, &APIError{Kind: KindPermanent, URL: requestURL, Err: errors.New("oembed response has no player html")}
+	}
+	return &embed, nil
+}
+
+// FetchTracksByArtistId fetches all tracks of the artist, following the linked_partitioning pages
+func (sc *SoundCloudClient) FetchTracksByArtistId(ctx context.Context, artistId string) ([]SCTrack, error) {
+	var tracks []SCTrack
+	nextURL := fmt.Sprintf("%s/users/%s/tracks?linked_partitioning=true&limit=%d", sc.config.BaseURL, url.PathEscape(artistId), tracksPageSize)
+	for nextURL != "" {
+		var page scTrackPage
+		if err := sc.getJSON(ctx, nextURL, &page); err != nil {
 			return nil, err
 		}
+		tracks = append(tracks, page.Collection...)
+		nextURL = page.NextHref
+	}
+
+	return tracks, nil
+}
+
+// getJSON performs an authorized GET request and decodes the JSON response into v. When SoundCloud rejects
+// the token it is replaced once and the request repeated.
+func (sc *SoundCloudClient) getJSON(ctx context.Context, requestURL string, v interface{}) error {
+	token, err := sc.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
 
-		token, err = SaveToken(sc.DB, refreshedToken)
+	err = sc.do(ctx, authorizedGet(ctx, requestURL, token), v)
+	if !errors.Is(err, ErrAuth) {
+		return err
+	}
 
+	token, err = sc.tokens.Invalidate(ctx, token)
+	if err != nil {
+		return err
+	}
+	return sc.do(ctx, authorizedGet(ctx, requestURL, token), v)
+}
+
+func authorizedGet(ctx context.Context, requestURL string, token *OAuthToken) func() (*http.Request, error) {
+	return func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 		if err != nil {
-			zap.L().Error("failed to save token", zap.Error(err))
 			return nil, err
 		}
+		req.Header.Set("Accept", "application/json; charset=utf-8")
+		req.Header.Set("Authorization", fmt.Sprintf("OAuth %s", token.AccessToken))
+		return req, nil
 	}
+}
 
-	// Construct the request URL
-	resolveURL := fmt.Sprintf("https://api.soundcloud.com/users/%s", id)
-	// Create an HTTP client and request
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", resolveURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create new request: %w", err)
+// do sends the request built by newRequest through the rate limiter and circuit breaker and decodes the JSON
+// response into v. Transient and rate limited failures are retried with exponential backoff, honouring
+// Retry-After when SoundCloud sends one.
+func (sc *SoundCloudClient) do(ctx context.Context, newRequest func() (*http.Request, error), v interface{}) error {
+	for attempt := 0; ; attempt++ {
+		err := sc.attempt(ctx, newRequest, v)
+		if err == nil {
+			return nil
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.Retryable() || attempt >= sc.config.MaxRetries {
+			return err
+		}
+
+		delay := jitter(backoff(sc.config.BaseBackoff, sc.config.MaxBackoff, attempt))
+		if apiErr.RetryAfter > 0 {
+			if apiErr.RetryAfter > sc.config.MaxBackoff {
+				return err
+			}
+			delay = apiErr.RetryAfter
+		}
+		if sleepErr := sleep(ctx, delay); sleepErr != nil {
+			return err
+		}
 	}
+}
 
-	// Set headers
-	req.Header.Set("Accept", "application/json; charset=utf-8")
-	req.Header.Set("Authorization", fmt.Sprintf("OAuth %s", token.AccessToken))
+// attempt performs a single call
+func (sc *SoundCloudClient) attempt(ctx context.Context, newRequest func() (*http.Request, error), v interface{}) error {
+	// Wait for the limiter before asking the breaker, a half-open probe must not be taken by a call that never runs
+	if err := sc.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	req, err := newRequest()
+	if err != nil {
+		return fmt.Errorf("failed to create new request: %w", err)
+	}
+	if ok, retryAt := sc.breaker.Allow(); !ok {
+		return &provider.UnavailableError{Provider: provider.SoundCloud, RetryAt: retryAt}
+	}
 
-	// Perform the request
-	resp, err := client.Do(req)
+	resp, err := sc.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		if ctx.Err() != nil {
+			sc.breaker.Cancel()
+			return ctx.Err()
+		}
+		sc.breaker.Failure()
+		return &APIError{Kind: KindTransient, URL: req.URL.String(), Err: err}
 	}
 	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-		}
+		_ = Body.Close()
 	}(resp.Body)
 
-	// Decode the response
-	var artist SCArtist
-	if err := json.NewDecoder(resp.Body).Decode(&artist); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := classifyStatus(resp)
+		if apiErr.Retryable() {
+			sc.breaker.Failure()
+		} else {
+			sc.breaker.Success()
+		}
+		return apiErr
 	}
+	sc.breaker.Success()
 
-	return &artist, nil
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return &APIError{Kind: KindPermanent, StatusCode: resp.StatusCode, URL: req.URL.String(), Err: fmt.Errorf("failed to decode response: %w", err)}
+	}
+	return nil
 }
 
-// FetchTracksByArtistId fetches tracks by the artist's SoundCloud ID
-func (sc *SoundCloudClient) FetchTracksByArtistId(artistId string) ([]SCTrack, error) {
-	return nil, nil
-	// Implement the API call here
+// jitter spreads retries of concurrent callers by randomising the upper half of the delay
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)))
 }
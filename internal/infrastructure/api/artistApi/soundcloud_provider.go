@@ -0,0 +1,186 @@
+package artistApi
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/provider"
+)
+
+// SoundCloudPriority is the default priority of the SoundCloud provider.
+const SoundCloudPriority = 100
+
+// SoundCloudProvider adapts SoundCloudClient to the provider.Provider interface.
+type SoundCloudProvider struct {
+	client   *SoundCloudClient
+	priority int
+}
+
+func NewSoundCloudProvider(client *SoundCloudClient, priority int) *SoundCloudProvider {
+	return &SoundCloudProvider{client: client, priority: priority}
+}
+
+func (p *SoundCloudProvider) Name() provider.Name {
+	return provider.SoundCloud
+}
+
+func (p *SoundCloudProvider) Priority() int {
+	return p.priority
+}
+
+// Supports reports whether the URL points at soundcloud.com.
+func (p *SoundCloudProvider) Supports(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "www."), "m.")
+	return host == "soundcloud.com"
+}
+
+// ResolveURL resolves a SoundCloud profile URL to the SoundCloud user ID.
+func (p *SoundCloudProvider) ResolveURL(ctx context.Context, rawURL string) (string, error) {
+	if !p.Supports(rawURL) {
+		return "", provider.ErrNotSupported
+	}
+	scArtist, err := p.client.FetchArtistByPermalink(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(scArtist.ID), nil
+}
+
+func (p *SoundCloudProvider) FetchProfile(ctx context.Context, externalID string) (*provider.Profile, error) {
+	scArtist, err := p.client.FetchArtistById(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+	return mapSCArtistToProfile(scArtist), nil
+}
+
+// FetchMedia loads every track the SoundCloud user has published.
+func (p *SoundCloudProvider) FetchMedia(ctx context.Context, externalID string) ([]provider.Media, error) {
+	tracks, err := p.client.FetchTracksByArtistId(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	media := make([]provider.Media, 0, len(tracks))
+	for _, track := range tracks {
+		media = append(media, mapSCTrackToMedia(track))
+	}
+	return media, nil
+}
+
+// SearchProfiles searches SoundCloud users by name.
+func (p *SoundCloudProvider) SearchProfiles(ctx context.Context, query string, limit int) ([]provider.Profile, error) {
+	users, err := p.client.SearchUsers(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]provider.Profile, 0, len(users))
+	for i := range users {
+		profiles = append(profiles, *mapSCArtistToProfile(&users[i]))
+	}
+	return profiles, nil
+}
+
+// ResolveEmbed resolves a SoundCloud track or set URL to its player. oEmbed supplies the title, thumbnail and
+// player HTML, the API the duration.
+func (p *SoundCloudProvider) ResolveEmbed(ctx context.Context, mediaURL string) (*provider.Embed, error) {
+	return p.resolveEmbed(ctx, mediaURL, p.client.ResolveMedia)
+}
+
+// ResolveEmbedAs resolves the URL like ResolveEmbed with the access token of a connected account, so the
+// account's private share links resolve too.
+func (p *SoundCloudProvider) ResolveEmbedAs(ctx context.Context, mediaURL, accessToken string) (*provider.Embed, error) {
+	return p.resolveEmbed(ctx, mediaURL, func(ctx context.Context, mediaURL string) (*SCMedia, error) {
+		return p.client.ResolveMediaAs(ctx, mediaURL, accessToken)
+	})
+}
+
+func (p *SoundCloudProvider) resolveEmbed(ctx context.Context, mediaURL string, resolve func(ctx context.Context, mediaURL string) (*SCMedia, error)) (*provider.Embed, error) {
+	if !p.Supports(mediaURL) || !isMediaURL(mediaURL) {
+		return nil, provider.ErrNotSupported
+	}
+
+	oEmbed, err := p.client.FetchOEmbed(ctx, mediaURL)
+	if err != nil {
+		return nil, err
+	}
+	media, err := resolve(ctx, mediaURL)
+	if err != nil {
+		return nil, err
+	}
+
+	embed := &provider.Embed{
+		URL:          media.PermalinkURL,
+		Title:        oEmbed.Title,
+		ThumbnailURL: oEmbed.ThumbnailURL,
+		AuthorName:   oEmbed.AuthorName,
+		HTML:         oEmbed.HTML,
+		Duration:     time.Duration(media.Duration) * time.Millisecond,
+	}
+	if embed.URL == "" {
+		embed.URL = mediaURL
+	}
+	return embed, nil
+}
+
+// isMediaURL reports whether the URL points below a profile, e.g. /artist/track or /artist/sets/name.
+func isMediaURL(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	return len(segments) >= 2 && segments[0] != "" && segments[1] != ""
+}
+
+func mapSCArtistToProfile(scArtist *SCArtist) *provider.Profile {
+	profile := &provider.Profile{
+		ExternalID:  strconv.Itoa(scArtist.ID),
+		Username:    scArtist.Username,
+		DisplayName: scArtist.FullName,
+		FirstName:   scArtist.FirstName,
+		LastName:    scArtist.LastName,
+		AvatarURL:   scArtist.AvatarURL,
+		Description: scArtist.Description,
+		City:        scArtist.City,
+		Country:     scArtist.Country,
+		Followers:   scArtist.Followers,
+	}
+	if scArtist.Permalink != "" {
+		profile.URL = profileURL(scArtist.Permalink)
+	}
+	return profile
+}
+
+// profileURL returns the URL of the SoundCloud profile with the permalink
+func profileURL(permalink string) string {
+	return "https://soundcloud.com/" + permalink
+}
+
+func mapSCTrackToMedia(track SCTrack) provider.Media {
+	return provider.Media{
+		ExternalID:  strconv.Itoa(track.ID),
+		Kind:        provider.Track,
+		Title:       track.Title,
+		URL:         track.PermalinkURL,
+		ArtworkURL:  track.ArtworkURL,
+		Duration:    time.Duration(track.Duration) * time.Millisecond,
+		Genre:       track.Genre,
+		PublishedAt: track.PublishedAt(),
+		Stats: provider.MediaStats{
+			Plays:    track.PlaybackCount,
+			Likes:    track.FavoriteCount,
+			Comments: track.CommentCount,
+			Reposts:  track.RepostsCount,
+		},
+	}
+}
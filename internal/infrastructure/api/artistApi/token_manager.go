@@ -0,0 +1,214 @@
+package artistApi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+const (
+	grantRefreshToken      = "refresh_token"
+	grantClientCredentials = "client_credentials"
+
+	// tokenExpirySkew treats tokens this close to expiry as expired, so they do not run out mid-request
+	tokenExpirySkew = 30 * time.Second
+)
+
+var tokenRefreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "soundcloud_token_refresh_total",
+	Help: "SoundCloud OAuth token requests by grant type and outcome.",
+}, []string{"grant", "outcome"})
+
+var tokenExpiry = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "soundcloud_token_expiry_timestamp_seconds",
+	Help: "Expiry of the cached SoundCloud access token as a Unix timestamp.",
+})
+
+// TokenIssuer requests tokens from the OAuth token endpoint
+type TokenIssuer interface {
+	RequestAccessToken(ctx context.Context) (*AccessTokenResponse, error)
+	RefreshAccessToken(ctx context.Context, refreshToken string) (*AccessTokenResponse, error)
+}
+
+// TokenManager hands out a valid access token. The token is cached in memory and backed by a TokenStore;
+// concurrent callers that need a new token share a single refresh.
+type TokenManager struct {
+	store         TokenStore
+	issuer        TokenIssuer
+	refreshBefore time.Duration
+
+	mu       sync.Mutex
+	cached   *OAuthToken
+	inflight *tokenCall
+}
+
+// tokenCall is a refresh in progress, callers wait on done
+type tokenCall struct {
+	done  chan struct{}
+	token *OAuthToken
+	err   error
+}
+
+// NewTokenManager creates a manager whose Run loop refreshes the token once it expires within refreshBefore.
+func NewTokenManager(store TokenStore, issuer TokenIssuer, refreshBefore time.Duration) *TokenManager {
+	return &TokenManager{store: store, issuer: issuer, refreshBefore: refreshBefore}
+}
+
+// Token returns a token that is not about to expire, obtaining a new one when needed.
+func (m *TokenManager) Token(ctx context.Context) (*OAuthToken, error) {
+	m.mu.Lock()
+	if m.cached == nil {
+		stored, err := m.store.Latest()
+		if err != nil {
+			m.mu.Unlock()
+			return nil, fmt.Errorf("error loading soundcloud token: %w", err)
+		}
+		m.cached = stored
+	}
+	if m.cached != nil && time.Now().Add(tokenExpirySkew).Before(m.cached.AccessTokenExpiry) {
+		token := m.cached
+		m.mu.Unlock()
+		return token, nil
+	}
+	call := m.startRefreshLocked(ctx)
+	m.mu.Unlock()
+
+	return call.wait(ctx)
+}
+
+// Invalidate drops the rejected token and obtains a new one. If another caller already replaced the token,
+// that token is returned without a new request.
+func (m *TokenManager) Invalidate(ctx context.Context, rejected *OAuthToken) (*OAuthToken, error) {
+	m.mu.Lock()
+	if m.cached != nil && rejected != nil && m.cached.AccessToken != rejected.AccessToken {
+		token := m.cached
+		m.mu.Unlock()
+		return token, nil
+	}
+	if m.cached != nil {
+		m.cached.AccessTokenExpiry = time.Time{}
+	}
+	call := m.startRefreshLocked(ctx)
+	m.mu.Unlock()
+
+	return call.wait(ctx)
+}
+
+// Run refreshes the token ahead of its expiry every interval until the context is done.
+func (m *TokenManager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.refreshIfExpiring(ctx); err != nil && ctx.Err() == nil {
+				zap.L().Error("failed to refresh soundcloud token", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (m *TokenManager) refreshIfExpiring(ctx context.Context) error {
+	m.mu.Lock()
+	if m.cached == nil {
+		stored, err := m.store.Latest()
+		if err != nil {
+			m.mu.Unlock()
+			return err
+		}
+		m.cached = stored
+	}
+	// Nothing to keep fresh until the first token is requested
+	if m.cached == nil || time.Now().Add(m.refreshBefore).Before(m.cached.AccessTokenExpiry) {
+		m.mu.Unlock()
+		return nil
+	}
+	call := m.startRefreshLocked(ctx)
+	m.mu.Unlock()
+
+	_, err := call.wait(ctx)
+	return err
+}
+
+// startRefreshLocked joins the refresh in progress or starts one. The refresh is detached from the caller's
+// cancellation so one impatient caller does not fail the others. m.mu must be held.
+func (m *TokenManager) startRefreshLocked(ctx context.Context) *tokenCall {
+	if m.inflight != nil {
+		return m.inflight
+	}
+	call := &tokenCall{done: make(chan struct{})}
+	m.inflight = call
+	current := m.cached
+
+	go func() {
+		token, err := m.obtain(context.WithoutCancel(ctx), current)
+
+		m.mu.Lock()
+		if err == nil {
+			m.cached = token
+			tokenExpiry.Set(float64(token.AccessTokenExpiry.Unix()))
+		}
+		m.inflight = nil
+		m.mu.Unlock()
+
+		call.token, call.err = token, err
+		close(call.done)
+	}()
+	return call
+}
+
+// obtain refreshes the current token, falling back to client credentials when there is no refresh token or
+// SoundCloud rejects it.
+func (m *TokenManager) obtain(ctx context.Context, current *OAuthToken) (*OAuthToken, error) {
+	if current != nil && current.RefreshToken != "" {
+		response, err := m.issuer.RefreshAccessToken(ctx, current.RefreshToken)
+		switch {
+		case err == nil:
+			tokenRefreshes.WithLabelValues(grantRefreshToken, "success").Inc()
+			if response.RefreshToken == "" {
+				response.RefreshToken = current.RefreshToken
+			}
+			return m.save(response)
+		case errors.Is(err, ErrAuth) || errors.Is(err, ErrPermanent):
+			tokenRefreshes.WithLabelValues(grantRefreshToken, "rejected").Inc()
+			zap.L().Warn("soundcloud rejected the refresh token, requesting a new token", zap.Error(err))
+		default:
+			tokenRefreshes.WithLabelValues(grantRefreshToken, "error").Inc()
+			return nil, fmt.Errorf("error refreshing soundcloud token: %w", err)
+		}
+	}
+
+	response, err := m.issuer.RequestAccessToken(ctx)
+	if err != nil {
+		tokenRefreshes.WithLabelValues(grantClientCredentials, "error").Inc()
+		return nil, fmt.Errorf("error requesting soundcloud token: %w", err)
+	}
+	tokenRefreshes.WithLabelValues(grantClientCredentials, "success").Inc()
+	return m.save(response)
+}
+
+func (m *TokenManager) save(response *AccessTokenResponse) (*OAuthToken, error) {
+	token, err := m.store.Save(response)
+	if err != nil {
+		return nil, fmt.Errorf("error saving soundcloud token: %w", err)
+	}
+	return token, nil
+}
+
+func (c *tokenCall) wait(ctx context.Context) (*OAuthToken, error) {
+	select {
+	case <-c.done:
+		return c.token, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,194 @@
+// Package residentadvisor reads artist and event pages of Resident Advisor. RA has no public API, pages are parsed
+// from the JSON-LD they embed, falling back to their Open Graph tags.
+package residentadvisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/provider"
+)
+
+const (
+	baseURL = "https://ra.co"
+
+	// maxPageSize caps the bytes read of a page, RA pages are well below
+	maxPageSize = 5 << 20
+)
+
+// Config holds the settings of the page fetcher. Zero values fall back to DefaultConfig.
+type Config struct {
+	// BaseURL replaces https://ra.co in page URLs, pointing it at a server with saved pages runs offline
+	BaseURL string
+	// FixtureDir reads saved pages from the directory instead of fetching them, see FSFetcher
+	FixtureDir string
+	UserAgent  string
+	Timeout    time.Duration
+	// RequestInterval is the minimum time between two requests, negative disables the wait
+	RequestInterval time.Duration
+	// Transport is used for every request, nil means http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+// DefaultConfig returns the settings for fetching from ra.co.
+func DefaultConfig() Config {
+	return Config{
+		BaseURL:         baseURL,
+		UserAgent:       "blnto-importer/1.0",
+		Timeout:         15 * time.Second,
+		RequestInterval: time.Second,
+	}
+}
+
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.BaseURL == "" {
+		c.BaseURL = defaults.BaseURL
+	}
+	if c.UserAgent == "" {
+		c.UserAgent = defaults.UserAgent
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaults.Timeout
+	}
+	if c.RequestInterval == 0 {
+		c.RequestInterval = defaults.RequestInterval
+	}
+	return c
+}
+
+// Fetcher loads the HTML of an RA page.
+type Fetcher interface {
+	// Fetch returns the page at the URL, provider.ErrNotFound if there is none
+	Fetch(ctx context.Context, pageURL string) ([]byte, error)
+}
+
+// NewFetcher returns the fetcher the config describes: an FSFetcher on FixtureDir when set, an HTTPFetcher otherwise.
+func NewFetcher(config Config) Fetcher {
+	if config.FixtureDir != "" {
+		return NewFSFetcher(os.DirFS(config.FixtureDir))
+	}
+	return NewHTTPFetcher(config)
+}
+
+// HTTPFetcher fetches pages over HTTP, one request at a time at most every RequestInterval.
+type HTTPFetcher struct {
+	config Config
+	client *http.Client
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func NewHTTPFetcher(config Config) *HTTPFetcher {
+	config = config.withDefaults()
+	return &HTTPFetcher{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout, Transport: config.Transport},
+	}
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, pageURL string) ([]byte, error) {
+	path, err := pagePath(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	requestURL := strings.TrimRight(f.config.BaseURL, "/") + path
+
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.config.UserAgent)
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %w", requestURL, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%s: %w", requestURL, provider.ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("error fetching %s: status %d", requestURL, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+}
+
+// wait blocks until RequestInterval passed since the previous request
+func (f *HTTPFetcher) wait(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if delay := time.Until(f.last.Add(f.config.RequestInterval)); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	f.last = time.Now()
+	return nil
+}
+
+// FSFetcher reads saved pages from a file system, the page at https://ra.co/dj/name from dj/name.html.
+type FSFetcher struct {
+	fsys fs.FS
+}
+
+func NewFSFetcher(fsys fs.FS) *FSFetcher {
+	return &FSFetcher{fsys: fsys}
+}
+
+func (f *FSFetcher) Fetch(ctx context.Context, pageURL string) ([]byte, error) {
+	path, err := pagePath(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.Trim(strings.SplitN(path, "?", 2)[0], "/") + ".html"
+	if !fs.ValidPath(name) {
+		return nil, fmt.Errorf("invalid page path %s", path)
+	}
+
+	page, err := fs.ReadFile(f.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", name, provider.ErrNotFound)
+	}
+	return page, err
+}
+
+// pagePath returns the path and query of an RA page URL
+func pagePath(pageURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(pageURL))
+	if err != nil || !isRAHost(u.Hostname()) {
+		return "", fmt.Errorf("%s is not a resident advisor page: %w", pageURL, provider.ErrNotSupported)
+	}
+	path := "/" + strings.Trim(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path, nil
+}
+
+func isRAHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "www."), "m.")
+	return host == "ra.co" || host == "residentadvisor.net"
+}
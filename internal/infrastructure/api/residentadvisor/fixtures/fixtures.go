@@ -0,0 +1,11 @@
+// Package fixtures holds saved Resident Advisor pages for offline imports and tests, laid out the way FSFetcher
+// reads them.
+package fixtures
+
+import "embed"
+
+// Pages holds the page of https://ra.co/dj/<slug> at dj/<slug>.html and of https://ra.co/events/<id> at
+// events/<id>.html.
+//
+//go:embed dj events
+var Pages embed.FS
@@ -0,0 +1,373 @@
+package residentadvisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	xhtml "golang.org/x/net/html"
+)
+
+// ErrNoData is returned for pages that carry no artist or event data, e.g. after RA changed its markup
+var ErrNoData = errors.New("page has no resident advisor data")
+
+var (
+	artistTypes = []string{"Person", "MusicGroup", "PerformingGroup"}
+	eventTypes  = []string{"Event", "MusicEvent", "DanceEvent", "Festival"}
+)
+
+// ArtistPage is what an RA artist page tells about the artist.
+type ArtistPage struct {
+	// URL is the canonical link of the page, https://ra.co/dj/<slug>
+	URL         string
+	Slug        string
+	Name        string
+	ImageURL    string
+	Description string
+	City        string
+	Country     string
+}
+
+// Profile returns the page as a provider profile, identified by the slug.
+func (p *ArtistPage) Profile() *provider.Profile {
+	return &provider.Profile{
+		ExternalID:  p.Slug,
+		URL:         p.URL,
+		Username:    p.Slug,
+		DisplayName: p.Name,
+		AvatarURL:   p.ImageURL,
+		Description: p.Description,
+		City:        p.City,
+		Country:     p.Country,
+	}
+}
+
+// EventPage is what an RA event page tells about the event.
+type EventPage struct {
+	// URL is the canonical link of the page, https://ra.co/events/<id>
+	URL  string
+	ID   string
+	Name string
+	// StartsAt and EndsAt are nil when the page has no dates. RA gives local times without offset, they are
+	// returned as UTC with the same wall clock.
+	StartsAt *time.Time
+	EndsAt   *time.Time
+	Venue    string
+	City     string
+	Country  string
+	// Lineup lists the performers in the order of the page
+	Lineup []Performer
+}
+
+// Performer is an act of an event lineup.
+type Performer struct {
+	Name string
+	// URL is the canonical RA link of the act, empty for acts without an RA artist page
+	URL string
+}
+
+// ArtistURL returns the canonical link of the artist page with the slug.
+func ArtistURL(slug string) string {
+	return baseURL + "/dj/" + slug
+}
+
+// EventURL returns the canonical link of the event page with the ID.
+func EventURL(id string) string {
+	return baseURL + "/events/" + id
+}
+
+// ArtistSlug returns the slug of an RA artist URL such as https://ra.co/dj/name, or false for other URLs.
+func ArtistSlug(rawURL string) (string, bool) {
+	return pathID(rawURL, "dj")
+}
+
+// EventID returns the ID of an RA event URL such as https://ra.co/events/123456, or false for other URLs.
+func EventID(rawURL string) (string, bool) {
+	return pathID(rawURL, "events")
+}
+
+// pathID returns the segment after the section in URLs like https://ra.co/<section>/<id>/...
+func pathID(rawURL, section string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || !isRAHost(u.Hostname()) {
+		return "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || !strings.EqualFold(segments[0], section) {
+		return "", false
+	}
+	id := strings.ToLower(segments[1])
+	if id == "" || strings.Trim(id, "abcdefghijklmnopqrstuvwxyz0123456789-_.") != "" {
+		return "", false
+	}
+	return id, true
+}
+
+// ParseArtistPage parses the HTML of the artist page at pageURL.
+func ParseArtistPage(page []byte, pageURL string) (*ArtistPage, error) {
+	slug, ok := ArtistSlug(pageURL)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a resident advisor artist page: %w", pageURL, provider.ErrNotSupported)
+	}
+	doc := parseDocument(page)
+
+	artistPage := &ArtistPage{URL: ArtistURL(slug), Slug: slug}
+	if node := doc.find(artistTypes); node != nil {
+		artistPage.Name = text(node["name"])
+		artistPage.ImageURL = imageURL(node["image"])
+		artistPage.Description = text(node["description"])
+		artistPage.City, artistPage.Country = locality(node["address"])
+		if artistPage.City == "" && artistPage.Country == "" {
+			if home, ok := node["homeLocation"].(map[string]interface{}); ok {
+				artistPage.City, artistPage.Country = locality(home["address"])
+				if artistPage.City == "" {
+					artistPage.City = text(home["name"])
+				}
+			}
+		}
+	}
+
+	// Pages without JSON-LD still have their Open Graph tags
+	if artistPage.Name == "" {
+		artistPage.Name = pageTitle(doc.meta["og:title"])
+	}
+	if artistPage.ImageURL == "" {
+		artistPage.ImageURL = doc.meta["og:image"]
+	}
+	if artistPage.Description == "" {
+		artistPage.Description = doc.meta["og:description"]
+	}
+
+	if artistPage.Name == "" {
+		return nil, fmt.Errorf("%s: %w", pageURL, ErrNoData)
+	}
+	return artistPage, nil
+}
+
+// ParseEventPage parses the HTML of the event page at pageURL.
+func ParseEventPage(page []byte, pageURL string) (*EventPage, error) {
+	id, ok := EventID(pageURL)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a resident advisor event page: %w", pageURL, provider.ErrNotSupported)
+	}
+	node := parseDocument(page).find(eventTypes)
+	if node == nil {
+		return nil, fmt.Errorf("%s: %w", pageURL, ErrNoData)
+	}
+
+	eventPage := &EventPage{
+		URL:      EventURL(id),
+		ID:       id,
+		Name:     text(node["name"]),
+		StartsAt: parseTime(text(node["startDate"])),
+		EndsAt:   parseTime(text(node["endDate"])),
+	}
+	if location, ok := node["location"].(map[string]interface{}); ok {
+		eventPage.Venue = text(location["name"])
+		eventPage.City, eventPage.Country = locality(location["address"])
+	}
+
+	seen := make(map[string]bool)
+	for _, performer := range list(node["performer"]) {
+		var act Performer
+		switch p := performer.(type) {
+		case string:
+			act.Name = text(p)
+		case map[string]interface{}:
+			act.Name = text(p["name"])
+			act.URL = artistLink(pageURL, text(p["url"]), text(p["@id"]))
+		}
+		if act.Name == "" {
+			continue
+		}
+		key := act.URL
+		if key == "" {
+			key = strings.ToLower(act.Name)
+		}
+		if !seen[key] {
+			seen[key] = true
+			eventPage.Lineup = append(eventPage.Lineup, act)
+		}
+	}
+	return eventPage, nil
+}
+
+// document holds the JSON-LD objects and meta tags of a page
+type document struct {
+	nodes []map[string]interface{}
+	meta  map[string]string
+}
+
+func parseDocument(page []byte) *document {
+	doc := &document{meta: make(map[string]string)}
+	tokenizer := xhtml.NewTokenizer(bytes.NewReader(page))
+
+	var script *strings.Builder
+	for {
+		switch tokenizer.Next() {
+		case xhtml.ErrorToken:
+			return doc
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			attrs := make(map[string]string)
+			for hasAttr {
+				var key, value []byte
+				key, value, hasAttr = tokenizer.TagAttr()
+				attrs[string(key)] = string(value)
+			}
+			switch string(name) {
+			case "script":
+				if strings.EqualFold(strings.TrimSpace(attrs["type"]), "application/ld+json") {
+					script = &strings.Builder{}
+				}
+			case "meta":
+				key := attrs["property"]
+				if key == "" {
+					key = attrs["name"]
+				}
+				if _, ok := doc.meta[key]; key != "" && !ok {
+					doc.meta[key] = strings.TrimSpace(attrs["content"])
+				}
+			}
+		case xhtml.TextToken:
+			if script != nil {
+				script.Write(tokenizer.Text())
+			}
+		case xhtml.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "script" && script != nil {
+				doc.addJSONLD(script.String())
+				script = nil
+			}
+		}
+	}
+}
+
+// addJSONLD adds the objects of a JSON-LD block, flattening arrays and @graph. Malformed blocks are skipped.
+func (d *document) addJSONLD(block string) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(block), &value); err != nil {
+		return
+	}
+	var add func(v interface{})
+	add = func(v interface{}) {
+		switch v := v.(type) {
+		case []interface{}:
+			for _, item := range v {
+				add(item)
+			}
+		case map[string]interface{}:
+			if graph, ok := v["@graph"]; ok {
+				add(graph)
+				return
+			}
+			d.nodes = append(d.nodes, v)
+		}
+	}
+	add(value)
+}
+
+// find returns the first object of one of the types
+func (d *document) find(types []string) map[string]interface{} {
+	for _, node := range d.nodes {
+		for _, t := range list(node["@type"]) {
+			for _, want := range types {
+				if t == want {
+					return node
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// list returns the value as a slice, JSON-LD allows a single value wherever a list is expected
+func list(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
+
+// text returns a string value with HTML entities decoded, RA escapes the text in its JSON-LD
+func text(v interface{}) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(html.UnescapeString(s))
+}
+
+func imageURL(v interface{}) string {
+	for _, image := range list(v) {
+		switch image := image.(type) {
+		case string:
+			return strings.TrimSpace(image)
+		case map[string]interface{}:
+			if u := text(image["url"]); u != "" {
+				return u
+			}
+			if u := text(image["contentUrl"]); u != "" {
+				return u
+			}
+		}
+	}
+	return ""
+}
+
+// locality returns the city and country of a PostalAddress
+func locality(v interface{}) (string, string) {
+	address, ok := v.(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+	country := text(address["addressCountry"])
+	if c, ok := address["addressCountry"].(map[string]interface{}); ok {
+		country = text(c["name"])
+	}
+	return text(address["addressLocality"]), country
+}
+
+// artistLink returns the canonical RA link of the first candidate pointing at an RA artist page, relative
+// candidates are resolved against the page
+func artistLink(pageURL string, candidates ...string) string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	for _, candidate := range candidates {
+		ref, err := url.Parse(candidate)
+		if candidate == "" || err != nil {
+			continue
+		}
+		if slug, ok := ArtistSlug(base.ResolveReference(ref).String()); ok {
+			return ArtistURL(slug)
+		}
+	}
+	return ""
+}
+
+// pageTitle strips what RA appends to titles, as in "Name ⟋ RA"
+func pageTitle(title string) string {
+	for _, separator := range []string{" ⟋ ", " · ", " | "} {
+		title, _, _ = strings.Cut(title, separator)
+	}
+	return strings.TrimSpace(title)
+}
+
+var timeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05.000", "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+func parseTime(value string) *time.Time {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package residentadvisor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blnto/blnto_service/internal/domain/provider"
+)
+
+// DefaultPriority is the default priority of the Resident Advisor provider, below SoundCloud.
+const DefaultPriority = 50
+
+// Client loads and parses RA pages.
+type Client struct {
+	fetcher Fetcher
+}
+
+func NewClient(fetcher Fetcher) *Client {
+	return &Client{fetcher: fetcher}
+}
+
+// ArtistPage fetches and parses the artist page the URL points at. Links to subpages and to residentadvisor.net
+// load the canonical page.
+func (c *Client) ArtistPage(ctx context.Context, rawURL string) (*ArtistPage, error) {
+	slug, ok := ArtistSlug(rawURL)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a resident advisor artist page: %w", rawURL, provider.ErrNotSupported)
+	}
+	pageURL := ArtistURL(slug)
+	page, err := c.fetcher.Fetch(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseArtistPage(page, pageURL)
+}
+
+// EventPage fetches and parses the event page the URL points at, like ArtistPage.
+func (c *Client) EventPage(ctx context.Context, rawURL string) (*EventPage, error) {
+	id, ok := EventID(rawURL)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a resident advisor event page: %w", rawURL, provider.ErrNotSupported)
+	}
+	pageURL := EventURL(id)
+	page, err := c.fetcher.Fetch(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEventPage(page, pageURL)
+}
+
+// Provider adapts Client to the provider.Provider interface. Profiles are identified by the slug of the page.
+type Provider struct {
+	client   *Client
+	priority int
+}
+
+func NewProvider(client *Client, priority int) *Provider {
+	return &Provider{client: client, priority: priority}
+}
+
+func (p *Provider) Name() provider.Name {
+	return provider.ResidentAdvisor
+}
+
+func (p *Provider) Priority() int {
+	return p.priority
+}
+
+// Supports reports whether the URL points at an RA artist page.
+func (p *Provider) Supports(rawURL string) bool {
+	_, ok := ArtistSlug(rawURL)
+	return ok
+}
+
+// ResolveURL returns the slug of the artist page, which needs no request.
+func (p *Provider) ResolveURL(ctx context.Context, rawURL string) (string, error) {
+	slug, ok := ArtistSlug(rawURL)
+	if !ok {
+		return "", provider.ErrNotSupported
+	}
+	return slug, nil
+}
+
+func (p *Provider) FetchProfile(ctx context.Context, externalID string) (*provider.Profile, error) {
+	page, err := p.client.ArtistPage(ctx, ArtistURL(externalID))
+	if err != nil {
+		return nil, err
+	}
+	return page.Profile(), nil
+}
+
+// FetchMedia is not supported, RA lists no media of artists.
+func (p *Provider) FetchMedia(ctx context.Context, externalID string) ([]provider.Media, error) {
+	return nil, provider.ErrNotSupported
+}
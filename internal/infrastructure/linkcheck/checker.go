@@ -0,0 +1,248 @@
+package linkcheck
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// HTTPDoer is the subset of *http.Client used by the checker, so tests can inject their own client.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Store loads the links to check and persists the results.
+type Store interface {
+	FindLinksDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]artist.SocialMediaLink, error)
+	FindByLinkID(ctx context.Context, linkID uuid.UUID) (*artist.LinkHealth, error)
+	Save(ctx context.Context, health *artist.LinkHealth) error
+}
+
+// Config holds the checker settings.
+type Config struct {
+	// Interval between two check rounds
+	Interval time.Duration
+	// RecheckAfter is the minimum age of the last check before a link is checked again
+	RecheckAfter time.Duration
+	// BatchSize limits how many links are checked per round
+	BatchSize int
+	// Concurrency bounds the number of requests in flight
+	Concurrency int
+	// PerHostInterval is the minimum delay between two requests to the same host
+	PerHostInterval time.Duration
+	// FailureThreshold is the number of consecutive failed checks after which a link is flagged as broken
+	FailureThreshold int
+	// RequestTimeout bounds a single request
+	RequestTimeout time.Duration
+	UserAgent      string
+}
+
+// DefaultConfig returns the settings used when nothing else is configured.
+func DefaultConfig() Config {
+	return Config{
+		Interval:         6 * time.Hour,
+		RecheckAfter:     24 * time.Hour,
+		BatchSize:        500,
+		Concurrency:      8,
+		PerHostInterval:  2 * time.Second,
+		FailureThreshold: 3,
+		RequestTimeout:   15 * time.Second,
+		UserAgent:        "blnto-link-checker/1.0",
+	}
+}
+
+// Checker periodically requests every SocialMediaLink and records whether it is still reachable.
+type Checker struct {
+	store   Store
+	client  HTTPDoer
+	config  Config
+	logger  *zap.Logger
+	limiter *hostLimiter
+	now     func() time.Time
+}
+
+func NewChecker(store Store, client HTTPDoer, config Config, logger *zap.Logger) *Checker {
+	if client == nil {
+		client = &http.Client{Timeout: config.RequestTimeout}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultConfig().Interval
+	}
+	if config.Concurrency < 1 {
+		config.Concurrency = 1
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Checker{
+		store:   store,
+		client:  client,
+		config:  config,
+		logger:  logger,
+		limiter: newHostLimiter(config.PerHostInterval),
+		now:     time.Now,
+	}
+}
+
+// Run checks links every Interval until the context is cancelled.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.CheckDue(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Error("link check round failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckDue checks one batch of links that are due and returns how many were checked.
+func (c *Checker) CheckDue(ctx context.Context) (int, error) {
+	links, err := c.store.FindLinksDueForCheck(ctx, c.now().Add(-c.config.RecheckAfter), c.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	jobs := make(chan artist.SocialMediaLink)
+	var wg sync.WaitGroup
+	for i := 0; i < c.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range jobs {
+				c.checkLink(ctx, link)
+			}
+		}()
+	}
+
+	checked := 0
+feed:
+	for _, link := range links {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- link:
+			checked++
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return checked, ctx.Err()
+}
+
+func (c *Checker) checkLink(ctx context.Context, link artist.SocialMediaLink) {
+	result := c.request(ctx, link.Link)
+	if ctx.Err() != nil {
+		// Shutting down, the result says nothing about the link
+		return
+	}
+
+	health, err := c.store.FindByLinkID(ctx, link.ID)
+	if err != nil {
+		c.logger.Error("failed to load link health", zap.String("link", link.Link), zap.Error(err))
+		return
+	}
+	if health == nil {
+		health = &artist.LinkHealth{LinkID: link.ID}
+	}
+
+	health.Record(result, c.config.FailureThreshold)
+
+	if err := c.store.Save(ctx, health); err != nil {
+		c.logger.Error("failed to save link health", zap.String("link", link.Link), zap.Error(err))
+	}
+}
+
+func (c *Checker) request(ctx context.Context, link string) artist.CheckResult {
+	u, err := url.Parse(link)
+	if err != nil {
+		return artist.CheckResult{Err: err, CheckedAt: c.now()}
+	}
+
+	if err := c.limiter.Wait(ctx, u.Host); err != nil {
+		return artist.CheckResult{Err: err, CheckedAt: c.now()}
+	}
+
+	reqCtx := ctx
+	if c.config.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, c.config.RequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, link, nil)
+	if err != nil {
+		return artist.CheckResult{Err: err, CheckedAt: c.now()}
+	}
+	req.Header.Set("User-Agent", c.config.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return artist.CheckResult{Err: err, CheckedAt: c.now()}
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	_, _ = io.CopyN(io.Discard, resp.Body, 64*1024)
+
+	result := artist.CheckResult{StatusCode: resp.StatusCode, CheckedAt: c.now()}
+	if resp.Request != nil && resp.Request.URL != nil {
+		result.FinalURL = resp.Request.URL.String()
+	}
+	return result
+}
+
+// hostLimiter spaces out requests to the same host.
+type hostLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     map[string]time.Time
+}
+
+func newHostLimiter(interval time.Duration) *hostLimiter {
+	return &hostLimiter{interval: interval, next: make(map[string]time.Time)}
+}
+
+// Wait blocks until a request to host is allowed or the context is cancelled.
+func (l *hostLimiter) Wait(ctx context.Context, host string) error {
+	if l.interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[host] = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/blnto/blnto_service/internal/domain/apperror"
 	"github.com/blnto/blnto_service/internal/domain/artist"
 	"github.com/blnto/blnto_service/internal/domain/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ArtistRepository struct {
@@ -32,7 +34,7 @@ func (r *ArtistRepository) FindAllByCursor(ctx context.Context, cursor string, l
 		query = query.Where("id > ?", cursor)
 	}
 
-	err := query.Limit(limit).Preload("SocialMediaLinks").Find(&artists).Error
+	err := query.Limit(limit).Scopes(withDisplayProfile("")).Find(&artists).Error
 	if err != nil {
 		return nil, "", err
 	}
@@ -49,18 +51,38 @@ func (r *ArtistRepository) FindByID(ctx context.Context, id uuid.UUID) (*artist.
 	var (
 		artistModel artist.Artist
 	)
-	if err := r.db.WithContext(ctx).Preload("SocialMediaLinks").Where("id = ?", id).First(&artistModel).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("SocialMediaLinks").Scopes(withDisplayProfile("")).Where("id = ?", id).First(&artistModel).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("artistModel not found")
+			return nil, apperror.NotFound("artist", id)
 		}
 		return nil, err
 	}
 	return &artistModel, nil
 }
 
+// FindByIDs returns the artists with the given IDs in no particular order, skipping IDs that do not exist.
+func (r *ArtistRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]artist.Artist, error) {
+	var artists []artist.Artist
+	if len(ids) == 0 {
+		return artists, nil
+	}
+	err := r.db.WithContext(ctx).
+		Scopes(withDisplayProfile("")).
+		Where("id IN ?", ids).
+		Find(&artists).Error
+	return artists, err
+}
+
 func (r *ArtistRepository) FindAllWithPermalink(ctx context.Context) ([]artist.Artist, error) {
 	var artists []artist.Artist
-	result := r.db.WithContext(ctx).Where("sc_permalink IS NOT NULL AND sc_permalink != ''").Find(&artists)
+	result := r.db.WithContext(ctx).Where("sc_permalink IS NOT NULL AND sc_permalink != ''").Preload("SocialMediaLinks").Find(&artists)
+	return artists, result.Error
+}
+
+// FindAllWithSocialMediaLinks fetches every artist with its social media links.
+func (r *ArtistRepository) FindAllWithSocialMediaLinks(ctx context.Context) ([]artist.Artist, error) {
+	var artists []artist.Artist
+	result := r.db.WithContext(ctx).Preload("SocialMediaLinks").Find(&artists)
 	return artists, result.Error
 }
 
@@ -68,9 +90,9 @@ func (r *ArtistRepository) FindByName(ctx context.Context, name string) (*artist
 	var (
 		artistModel artist.Artist
 	)
-	if err := r.db.WithContext(ctx).Preload("SocialMediaLinks").Where("name = ?", name).First(&artistModel).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(withDisplayProfile("")).Where("name = ?", name).First(&artistModel).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("artistModel not found")
+			return nil, apperror.NotFound("artist", name)
 		}
 		return nil, err
 	}
@@ -116,6 +138,10 @@ func (r *ArtistRepository) Save(ctx context.Context, artist *artist.Artist) (*ar
 }
 
 func (r *ArtistRepository) PermalinkExists(ctx context.Context, permalink string) (bool, error) {
+	permalink, err := artist.NormalizePermalink(permalink)
+	if err != nil {
+		return false, err
+	}
 	var count int64
 	result := r.db.WithContext(ctx).Model(&artist.Artist{}).Where("sc_permalink = ?", permalink).Count(&count)
 	if result.Error != nil {
@@ -132,19 +158,114 @@ func (r *ArtistRepository) Delete(ctx context.Context, id uuid.UUID) (bool, erro
 	}
 
 	if result.RowsAffected == 0 {
-		return false, fmt.Errorf("artist not found")
+		return false, apperror.NotFound("artist", id)
 	}
 
 	return true, nil
 }
 
+// legacySoundCloudColumns are the deprecated profile columns on artists. Saves leave them untouched so the external
+// profile backfill still finds the data it copies until they are dropped.
+var legacySoundCloudColumns = []string{
+	"sc_city", "sc_avatar_url", "sc_first_name", "sc_last_name", "sc_full_name", "sc_username", "sc_description", "sc_country",
+}
+
 func (r *ArtistRepository) Update(ctx context.Context, artist *artist.Artist) (*artist.Artist, error) {
-	if err := r.db.WithContext(ctx).Save(artist).Error; err != nil {
+	if err := r.db.WithContext(ctx).Omit(legacySoundCloudColumns...).Save(artist).Error; err != nil {
 		return nil, err
 	}
 	return artist, nil
 }
 
+// SetSoundCloudID records the SoundCloud user the artist's permalink resolved to.
+func (r *ArtistRepository) SetSoundCloudID(ctx context.Context, id uuid.UUID, scID int) error {
+	return r.db.WithContext(ctx).Model(&artist.Artist{}).Where("id = ?", id).Update("sc_id", scID).Error
+}
+
+// UpdateWithOverrides saves the artist and inserts or replaces the given field overrides in one transaction, so an
+// edit never stores its values without the overrides that keep sync from replacing them.
+func (r *ArtistRepository) UpdateWithOverrides(ctx context.Context, a *artist.Artist, overrides []artist.FieldOverride) (*artist.Artist, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(legacySoundCloudColumns...).Save(a).Error; err != nil {
+			return err
+		}
+		if len(overrides) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "artist_id"}, {Name: "field"}},
+			DoUpdates: clause.AssignmentColumns([]string{"source", "value", "updated_at"}),
+		}).Create(&overrides).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// withDisplayProfile preloads what the displayed values of artists are resolved from, their external profiles and
+// field overrides. association is the path to the artists, e.g. "Artist" for memberships, "" for artists themselves.
+func withDisplayProfile(association string) func(*gorm.DB) *gorm.DB {
+	prefix := ""
+	if association != "" {
+		prefix = association + "."
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Preload(prefix + "ExternalProfiles").Preload(prefix + "FieldOverrides")
+	}
+}
+
+// DeleteFieldOverride removes the override of a field so the provider value is used again.
+func (r *ArtistRepository) DeleteFieldOverride(ctx context.Context, id uuid.UUID, field artist.Field) error {
+	return r.db.WithContext(ctx).Delete(&artist.FieldOverride{}, "artist_id = ? AND field = ?", id, field).Error
+}
+
+// LinkSoundCloudPermalink sets the artist's SoundCloud permalink and clears the last sync time so the next sync
+// pass fetches the profile.
+func (r *ArtistRepository) LinkSoundCloudPermalink(ctx context.Context, id uuid.UUID, permalink string) error {
+	permalink, err := artist.NormalizePermalink(permalink)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Model(&artist.Artist{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"sc_permalink":   permalink,
+		"last_synced_at": nil,
+	}).Error
+}
+
+// LinkSoundCloudAccount sets the SoundCloud ID and permalink an artist verified by connecting their account and
+// clears the last sync time so the next sync pass fetches the profile. It fails when another artist already has
+// the ID or permalink.
+func (r *ArtistRepository) LinkSoundCloudAccount(ctx context.Context, id uuid.UUID, scID int, permalink string) error {
+	permalink, err := artist.NormalizePermalink(permalink)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&artist.Artist{}).
+			Where("sc_id = ? OR sc_permalink = ?", scID, permalink).
+			Where("id <> ?", id).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return apperror.Conflict("soundcloud account %s is already linked to another artist", permalink)
+		}
+
+		result := tx.Model(&artist.Artist{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"sc_id":          scID,
+			"sc_permalink":   permalink,
+			"last_synced_at": nil,
+		})
+		if result.Error == nil && result.RowsAffected == 0 {
+			return apperror.NotFound("artist", id)
+		}
+		return result.Error
+	})
+}
+
 func (r *ArtistRepository) CreateSocialMediaLink(ctx context.Context, link artist.SocialMediaLink) error {
 	db := r.db.WithContext(ctx)
 	return db.Create(&link).Error
@@ -162,12 +283,26 @@ func (r *ArtistRepository) GetSocialMediaLinksByArtistID(ctx context.Context, id
 	return links, result.Error
 }
 
+// FindSocialMediaLinksByArtistIDs returns the social media links of the given artists.
+func (r *ArtistRepository) FindSocialMediaLinksByArtistIDs(ctx context.Context, artistIDs []uuid.UUID) ([]artist.SocialMediaLink, error) {
+	var links []artist.SocialMediaLink
+	if len(artistIDs) == 0 {
+		return links, nil
+	}
+	err := r.db.WithContext(ctx).Where("artist_id IN ?", artistIDs).Order("created_at ASC").Find(&links).Error
+	return links, err
+}
+
 func (r *ArtistRepository) DeleteSocialMediaLink(ctx context.Context, id uuid.UUID) error {
 	db := r.db.WithContext(ctx)
 	return db.Delete(&artist.SocialMediaLink{}, "id = ?", id).Error
 }
 
 func (r *ArtistRepository) PermalinkExistsExcludingArtist(ctx context.Context, permalink string, id uuid.UUID) (bool, error) {
+	permalink, err := artist.NormalizePermalink(permalink)
+	if err != nil {
+		return false, err
+	}
 	var (
 		count int64
 	)
@@ -180,13 +315,17 @@ func (r *ArtistRepository) PermalinkExistsExcludingArtist(ctx context.Context, p
 	return count > 0, result.Error
 }
 
-func (r *ArtistRepository) FindFeatured(ctx context.Context) ([]artist.Artist, error) {
+// FindFeatured returns the artists used to fill the featured list when there are not enough pinned or trending ones.
+func (r *ArtistRepository) FindFeatured(ctx context.Context, requireSoundCloud bool, limit int) ([]artist.Artist, error) {
 	var artists []artist.Artist
 	db := r.db.WithContext(ctx).
-		Model(&artist.Artist{}).
-		Where("sc_id IS NOT NULL").
-		Preload("SocialMediaLinks").
-		Limit(10).Find(&artists)
+		Model(&artist.Artist{})
+	if requireSoundCloud {
+		db = db.Where("sc_id IS NOT NULL")
+	}
+	db = db.
+		Scopes(withDisplayProfile("")).
+		Limit(limit).Find(&artists)
 	if db.Error != nil {
 		_ = fmt.Errorf("error checking for featured Artists: %v", db.Error)
 		return nil, db.Error
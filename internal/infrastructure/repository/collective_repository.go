@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/collective"
+	"github.com/blnto/blnto_service/internal/domain/event"
+	"github.com/blnto/blnto_service/internal/domain/stage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CollectiveRepository struct {
+	db *gorm.DB
+}
+
+func NewCollectiveRepository(db *gorm.DB) *CollectiveRepository {
+	return &CollectiveRepository{db: db}
+}
+
+func (r *CollectiveRepository) FindByID(ctx context.Context, id uuid.UUID) (*collective.Collective, error) {
+	var collectiveModel collective.Collective
+	if err := r.db.WithContext(ctx).Preload("SocialMediaLinks").Where("id = ?", id).First(&collectiveModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("collective", id)
+		}
+		return nil, err
+	}
+	return &collectiveModel, nil
+}
+
+// FindAllByCursor fetches a page of collectives starting after the given cursor with the specified limit
+func (r *CollectiveRepository) FindAllByCursor(ctx context.Context, cursor string, limit int) ([]collective.Collective, string, error) {
+	var collectives []collective.Collective
+	var nextCursor string
+
+	// Assuming ID is used as the cursor
+	query := r.db.WithContext(ctx).Order("id ASC")
+
+	if cursor != "" {
+		query = query.Where("id > ?", cursor)
+	}
+
+	err := query.Limit(limit).Preload("SocialMediaLinks").Find(&collectives).Error
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Set next cursor
+	if len(collectives) > 0 {
+		nextCursor = collectives[len(collectives)-1].ID.String()
+	}
+
+	return collectives, nextCursor, nil
+}
+
+func (r *CollectiveRepository) FindByArtistID(ctx context.Context, artistID uuid.UUID) ([]collective.Collective, error) {
+	var collectives []collective.Collective
+	err := r.db.WithContext(ctx).
+		Joins("JOIN collective_memberships ON collective_memberships.collective_id = collectives.id AND collective_memberships.deleted_at IS NULL").
+		Where("collective_memberships.artist_id = ?", artistID).
+		Preload("SocialMediaLinks").
+		Order("collectives.name ASC").
+		Find(&collectives).Error
+	return collectives, err
+}
+
+func (r *CollectiveRepository) Save(ctx context.Context, collectiveModel *collective.Collective) (*collective.Collective, error) {
+	result := r.db.WithContext(ctx).Save(collectiveModel)
+
+	if isUniqueViolation(result.Error) {
+		return nil, apperror.Conflict("collective name %s is already taken", collectiveModel.Name)
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("error saving collective: %v", result.Error)
+	}
+
+	return collectiveModel, nil
+}
+
+// Delete removes the collective together with its memberships, stage takeovers and social media links, and clears
+// it as host of its events.
+func (r *CollectiveRepository) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&collective.Collective{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("error deleting collective: %v", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("collective", id)
+		}
+
+		for _, owned := range []interface{}{&collective.Membership{}, &collective.Takeover{}, &artist.SocialMediaLink{}} {
+			if err := tx.Where("collective_id = ?", id).Delete(owned).Error; err != nil {
+				return fmt.Errorf("error deleting collective: %v", err)
+			}
+		}
+		err := tx.Model(&event.Event{}).Where("host_collective_id = ?", id).Update("host_collective_id", nil).Error
+		if err != nil {
+			return fmt.Errorf("error clearing event hosts: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *CollectiveRepository) FindMembers(ctx context.Context, collectiveID uuid.UUID) ([]*collective.Membership, error) {
+	var members []*collective.Membership
+	err := r.db.WithContext(ctx).
+		Where("collective_id = ?", collectiveID).
+		Scopes(withDisplayProfile("Artist")).
+		Order("created_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+func (r *CollectiveRepository) AddMember(ctx context.Context, membership *collective.Membership) (*collective.Membership, error) {
+	err := r.db.WithContext(ctx).Create(membership).Error
+	if isUniqueViolation(err) {
+		return nil, apperror.Conflict("artist %s is already a member of the collective", membership.ArtistID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error adding collective member: %v", err)
+	}
+
+	if err := r.db.WithContext(ctx).Scopes(withDisplayProfile("Artist")).First(membership, "id = ?", membership.ID).Error; err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+func (r *CollectiveRepository) RemoveMember(ctx context.Context, collectiveID, artistID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("collective_id = ? AND artist_id = ?", collectiveID, artistID).
+		Delete(&collective.Membership{})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("error removing collective member: %v", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, apperror.NotFound("collective member", artistID)
+	}
+
+	return true, nil
+}
+
+// FindHostedEvents returns the events hosted by a collective, optionally only the upcoming ones.
+func (r *CollectiveRepository) FindHostedEvents(ctx context.Context, collectiveID uuid.UUID, upcomingOnly bool) ([]*event.Event, error) {
+	var events []*event.Event
+	query := r.db.WithContext(ctx).Where("host_collective_id = ?", collectiveID)
+	if upcomingOnly {
+		query = query.Where("end_date >= ?", time.Now())
+	}
+	err := query.Order("start_date ASC").Find(&events).Error
+	return events, err
+}
+
+// FindTakeovers returns the stage takeovers of a collective, optionally only the upcoming ones.
+func (r *CollectiveRepository) FindTakeovers(ctx context.Context, collectiveID uuid.UUID, upcomingOnly bool) ([]*collective.Takeover, error) {
+	var takeovers []*collective.Takeover
+	query := r.db.WithContext(ctx).Where("collective_id = ?", collectiveID)
+	if upcomingOnly {
+		query = query.Where("end_time >= ?", time.Now())
+	}
+	err := query.Order("start_time ASC").
+		Preload("Stage").
+		Find(&takeovers).Error
+	return takeovers, err
+}
+
+// CreateTakeover stores a takeover unless another collective already holds the stage during that time. The stage row
+// stays locked until the takeover is stored, so concurrent requests for the same stage cannot both pass the check.
+func (r *CollectiveRepository) CreateTakeover(ctx context.Context, takeover *collective.Takeover) (*collective.Takeover, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&stage.Stage{}, "id = ?", takeover.StageID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("stage", takeover.StageID)
+		}
+		if err != nil {
+			return err
+		}
+
+		var overlapping int64
+		err = tx.Model(&collective.Takeover{}).
+			Where("stage_id = ? AND start_time < ? AND end_time > ?", takeover.StageID, takeover.EndTime, takeover.StartTime).
+			Count(&overlapping).Error
+		if err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return collective.ErrTakeoverOverlap
+		}
+		if err := tx.Create(takeover).Error; err != nil {
+			return err
+		}
+		return tx.Preload("Stage").First(takeover, "id = ?", takeover.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return takeover, nil
+}
+
+func (r *CollectiveRepository) DeleteTakeover(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&collective.Takeover{}, id)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("error deleting stage takeover: %v", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, apperror.NotFound("stage takeover", id)
+	}
+
+	return true, nil
+}
@@ -1,15 +1,19 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/collective"
 	"github.com/blnto/blnto_service/internal/domain/event"
+	"github.com/blnto/blnto_service/internal/domain/provider"
 	"github.com/blnto/blnto_service/internal/domain/stage"
 	"github.com/blnto/blnto_service/internal/domain/venue"
 	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -32,15 +36,110 @@ func ProvideDatabase() (*gorm.DB, error) {
 
 	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"")
 
-	err = db.AutoMigrate(&artist.Artist{}, &artist.SocialMediaLink{}, &venue.Venue{}, &stage.Stage{}, &event.Event{}, &event.TimetableEntry{}, &artistApi.OAuthToken{})
+	err = db.AutoMigrate(&artist.Artist{}, &artist.SocialMediaLink{}, &artist.LinkHealth{}, &artist.ExternalProfile{}, &artist.FieldOverride{}, &artist.Track{}, &artist.SyncRun{}, &artist.SyncRunError{}, &artist.ProfileSuggestion{}, &artist.PromotedSet{}, &artist.PopularitySnapshot{}, &artist.FeaturedPin{}, &venue.Venue{}, &stage.Stage{}, &event.Event{}, &event.TimetableEntry{}, &venue.Residency{}, &collective.Collective{}, &collective.Membership{}, &collective.Takeover{}, &artistApi.OAuthToken{}, &artistApi.AccountToken{}, &artistApi.AuthorizationState{})
 
 	if err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	if err := dropReplacedIndexes(db); err != nil {
+		log.Fatalf("Failed to drop replaced indexes: %v", err)
+	}
+
+	if err := backfillSoundCloudProfiles(db); err != nil {
+		log.Fatalf("Failed to backfill external profiles: %v", err)
+	}
+
+	if err := normalizeSoundCloudPermalinks(db); err != nil {
+		log.Fatalf("Failed to normalize SoundCloud permalinks: %v", err)
+	}
+
 	return db, nil
 }
 
+// backfillSoundCloudProfiles copies the SoundCloud data stored on artists into artist_external_profiles.
+// Artists that already have a SoundCloud profile are left alone, so running it again is a no-op. Once every deployment
+// has run it, the backfill goes away together with the deprecated sc_* profile columns it reads.
+func backfillSoundCloudProfiles(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO artist_external_profiles
+			(id, artist_id, provider, priority, external_id, url, username, display_name, first_name, last_name,
+			 avatar_url, description, city, country, fetched_at, created_at, updated_at)
+		SELECT gen_random_uuid(), a.id, ?, ?, a.sc_id::text,
+			CASE WHEN COALESCE(a.sc_permalink, '') = '' OR a.sc_permalink LIKE '%://%' THEN COALESCE(a.sc_permalink, '')
+				ELSE 'https://soundcloud.com/' || a.sc_permalink END,
+			COALESCE(a.sc_username, ''),
+			a.sc_full_name, a.sc_first_name, a.sc_last_name, a.sc_avatar_url, a.sc_description, a.sc_city, a.sc_country,
+			a.updated_at, NOW(), NOW()
+		FROM artists a
+		WHERE a.sc_id IS NOT NULL AND a.deleted_at IS NULL
+		ON CONFLICT (artist_id, provider) DO NOTHING`,
+		provider.SoundCloud, artistApi.SoundCloudPriority).Error
+}
+
+// normalizeSoundCloudPermalinks rewrites permalinks stored as profile URLs or with uppercase letters into the
+// normalized form artist.NormalizePermalink gives. Permalinks that are not SoundCloud profiles, or whose normalized
+// form another artist already has, are logged and left for an editor to resolve.
+func normalizeSoundCloudPermalinks(db *gorm.DB) error {
+	var artists []artist.Artist
+	if err := db.Unscoped().Select("id", "sc_permalink").
+		Where("sc_permalink IS NOT NULL AND sc_permalink <> ''").
+		Find(&artists).Error; err != nil {
+		return err
+	}
+	for _, a := range artists {
+		permalink, err := artist.NormalizePermalink(*a.SCPermalink)
+		if err != nil {
+			log.Printf("Artist %s keeps permalink %q: %v", a.ID, *a.SCPermalink, err)
+			continue
+		}
+		if permalink == *a.SCPermalink {
+			continue
+		}
+		err = db.Unscoped().Model(&artist.Artist{}).Where("id = ?", a.ID).Update("sc_permalink", permalink).Error
+		if isUniqueViolation(err) {
+			log.Printf("Artist %s keeps permalink %q: %s belongs to another artist", a.ID, *a.SCPermalink, permalink)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replacedIndexes are unique indexes that also covered soft-deleted rows. AutoMigrate creates their partial
+// replacements but leaves the old indexes in place.
+var replacedIndexes = []struct {
+	model interface{}
+	name  string
+}{
+	{&collective.Collective{}, "idx_collectives_name"},
+	{&collective.Membership{}, "idx_collective_memberships_member"},
+}
+
+// dropReplacedIndexes drops the replaced indexes that still exist.
+func dropReplacedIndexes(db *gorm.DB) error {
+	for _, index := range replacedIndexes {
+		if !db.Migrator().HasIndex(index.model, index.name) {
+			continue
+		}
+		if err := db.Migrator().DropIndex(index.model, index.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uniqueViolation is the code Postgres reports when a write would duplicate a value of a unique index
+const uniqueViolation = "23505"
+
+// isUniqueViolation reports whether err is Postgres rejecting a duplicate value of a unique index.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
 // CloseDatabaseConnection Ensure you call this function from somewhere in your application, typically main.go
 func CloseDatabaseConnection(db *gorm.DB) {
 	sqlDB, err := db.DB()
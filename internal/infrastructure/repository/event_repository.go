@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/blnto/blnto_service/internal/domain/apperror"
 	"github.com/blnto/blnto_service/internal/domain/event"
+	"github.com/blnto/blnto_service/internal/domain/stage"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -21,9 +24,6 @@ func NewEventRepository(db *gorm.DB) *EventRepository {
 func (repo *EventRepository) FindUpcomingByVenueID(ctx context.Context, venueID uuid.UUID) ([]*event.Event, error) {
 	var events []*event.Event
 	err := repo.db.WithContext(ctx).Where("venue_id = ? AND start_date > ?", venueID, time.Now()).
-		Preload("Venue.Stages").
-		Preload("Timetable.Stage").
-		Preload("Timetable.Artist").
 		Find(&events).Error
 	return events, err
 }
@@ -31,18 +31,12 @@ func (repo *EventRepository) FindUpcomingByVenueID(ctx context.Context, venueID
 func (repo *EventRepository) FindPastEventsByVenueID(ctx context.Context, venueID uuid.UUID) ([]*event.Event, error) {
 	var events []*event.Event
 	err := repo.db.WithContext(ctx).Where("venue_id = ? AND end_date < ?", venueID, time.Now()).
-		Preload("Venue.Stages").
-		Preload("Timetable.Stage").
-		Preload("Timetable.Artist").
 		Find(&events).Error
 	return events, err
 }
 func (repo *EventRepository) FindAllByVenueID(ctx context.Context, venueId uuid.UUID) ([]*event.Event, error) {
 	var events []*event.Event
 	err := repo.db.WithContext(ctx).Where("venue_id = ?", venueId).
-		Preload("Venue.Stages").
-		Preload("Timetable.Stage").
-		Preload("Timetable.Artist").
 		Find(&events).Error
 	return events, err
 }
@@ -52,9 +46,6 @@ func (repo *EventRepository) FindAllUpcoming(ctx context.Context, cursort string
 	var nextCursor string
 
 	err := repo.db.WithContext(ctx).Where("start_date > ?", time.Now()).
-		Preload("Venue.Stages").
-		Preload("Timetable.Stage").
-		Preload("Timetable.Artist").
 		Find(&events).Error
 
 	if err != nil {
@@ -73,9 +64,6 @@ func (repo *EventRepository) FindToday(ctx context.Context) ([]*event.Event, err
 	today := time.Now()
 	var events []*event.Event
 	err := repo.db.WithContext(ctx).Where("DATE(start_date) = DATE(?)", today).
-		Preload("Venue.Stages").
-		Preload("Timetable.Stage").
-		Preload("Timetable.Artist").
 		Find(&events).Error
 	return events, err
 }
@@ -83,9 +71,7 @@ func (repo *EventRepository) FindToday(ctx context.Context) ([]*event.Event, err
 func (repo *EventRepository) FindTomorrow(ctx context.Context) ([]*event.Event, error) {
 	tomorrow := time.Now().AddDate(0, 0, 1)
 	var events []*event.Event
-	err := repo.db.WithContext(ctx).Where("DATE(start_date) = DATE(?)", tomorrow).Preload("Venue.Stages").
-		Preload("Timetable.Stage").
-		Preload("Timetable.Artist").
+	err := repo.db.WithContext(ctx).Where("DATE(start_date) = DATE(?)", tomorrow).
 		Find(&events).Error
 	return events, err
 }
@@ -94,9 +80,6 @@ func (repo *EventRepository) FindCurrent(ctx context.Context) ([]*event.Event, e
 	now := time.Now()
 	var events []*event.Event
 	err := repo.db.WithContext(ctx).Where("start_date <= ? AND end_date >= ?", now, now).
-		Preload("Venue.Stages").
-		Preload("Timetable.Stage").
-		Preload("Timetable.Artist").
 		Find(&events).Error
 	return events, err
 }
@@ -123,12 +106,265 @@ func (repo *EventRepository) Delete(ctx context.Context, id uuid.UUID) (bool, er
 	result := repo.db.WithContext(ctx).Delete(&event.Event{}, id)
 
 	if result.Error != nil {
-		return false, fmt.Errorf("error deleting artist: %v", result.Error)
+		return false, fmt.Errorf("error deleting event: %v", result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return false, fmt.Errorf("artist not found")
+		return false, apperror.NotFound("event", id)
 	}
 
 	return true, nil
 }
+
+// FindAppearancesByArtistID returns a page of timetable entries for an artist together with their event,
+// venue and stage. Upcoming-only pages are ordered chronologically, everything else most recent first.
+func (repo *EventRepository) FindAppearancesByArtistID(ctx context.Context, artistID uuid.UUID, filter event.AppearanceFilter, cursor string, limit int) ([]*event.TimetableEntry, string, error) {
+	var entries []*event.TimetableEntry
+	var nextCursor string
+	now := time.Now()
+
+	query := repo.db.WithContext(ctx).Where("artist_id = ?", artistID)
+
+	switch {
+	case filter.OnlyUpcoming():
+		query = query.Where("start_time > ?", now)
+	case filter.OnlyPast():
+		query = query.Where("start_time <= ?", now)
+	}
+
+	order := "DESC"
+	comparator := "<"
+	if filter.OnlyUpcoming() {
+		order = "ASC"
+		comparator = ">"
+	}
+
+	if cursor != "" {
+		after, err := repo.appearanceCursor(ctx, cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		// Keyset pagination on (start_time, id) so the cursor stays stable while entries are added
+		query = query.Where(fmt.Sprintf("(start_time, id) %s (?, ?)", comparator), after.StartTime, after.ID)
+	}
+
+	err := query.Order("start_time " + order).Order("id " + order).
+		Limit(limit).
+		Preload("Event").
+		Find(&entries).Error
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Set next cursor
+	if len(entries) > 0 {
+		nextCursor = entries[len(entries)-1].ID.String()
+	}
+
+	return entries, nextCursor, nil
+}
+
+// appearanceCursor returns the timetable entry an appearances cursor points at. An entry deleted since the previous
+// page still marks its position.
+func (repo *EventRepository) appearanceCursor(ctx context.Context, cursor string) (*event.TimetableEntry, error) {
+	id, err := uuid.Parse(cursor)
+	if err != nil {
+		return nil, apperror.Invalid("after", "after must be the cursor of a previous page")
+	}
+	var entry event.TimetableEntry
+	err = repo.db.WithContext(ctx).Unscoped().Select("id", "start_time").First(&entry, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("timetable entry", id)
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetArtistPerformanceStats aggregates the sets an artist has played so far.
+func (repo *EventRepository) GetArtistPerformanceStats(ctx context.Context, artistID uuid.UUID) (*event.PerformanceStats, error) {
+	var totals struct {
+		TotalSets       int
+		TotalSeconds    float64
+		FirstAppearance *time.Time
+		LastAppearance  *time.Time
+	}
+	now := time.Now()
+
+	err := repo.db.WithContext(ctx).Model(&event.TimetableEntry{}).
+		Select("COUNT(*) AS total_sets, "+
+			"COALESCE(SUM(EXTRACT(EPOCH FROM (end_time - start_time))), 0) AS total_seconds, "+
+			"MIN(start_time) AS first_appearance, "+
+			"MAX(start_time) AS last_appearance").
+		Where("artist_id = ? AND start_time <= ?", artistID, now).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &event.PerformanceStats{
+		TotalSets:        totals.TotalSets,
+		TotalHoursPlayed: totals.TotalSeconds / 3600,
+		FirstAppearance:  totals.FirstAppearance,
+		LastAppearance:   totals.LastAppearance,
+	}
+
+	if totals.TotalSets == 0 {
+		return stats, nil
+	}
+
+	// The stage played most often, ties go to the lowest stage ID so the answer does not change between requests
+	var favourite stage.Stage
+	result := repo.db.WithContext(ctx).Model(&stage.Stage{}).
+		Joins("JOIN timetable_entries ON timetable_entries.stage_id = stages.id AND timetable_entries.deleted_at IS NULL").
+		Where("timetable_entries.artist_id = ? AND timetable_entries.start_time <= ?", artistID, now).
+		Group("stages.id").
+		Order("COUNT(timetable_entries.id) DESC").
+		Order("stages.id ASC").
+		Limit(1).
+		Find(&favourite)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		stats.FavouriteStage = &favourite
+	}
+
+	return stats, nil
+}
+
+// debutCondition matches timetable entries that have no earlier entry for the same artist at the same venue.
+const debutCondition = `NOT EXISTS (
+	SELECT 1 FROM timetable_entries prev
+	JOIN events prev_event ON prev_event.id = prev.event_id AND prev_event.deleted_at IS NULL
+	WHERE prev.deleted_at IS NULL
+	AND prev.artist_id = timetable_entries.artist_id
+	AND prev_event.venue_id = events.venue_id
+	AND (prev.start_time < timetable_entries.start_time OR (prev.start_time = timetable_entries.start_time AND prev.id < timetable_entries.id))
+)`
+
+// FindDebuts returns the timetable entries in the given range that are an artist's first set at the venue,
+// optionally restricted to a single venue.
+func (repo *EventRepository) FindDebuts(ctx context.Context, from, to time.Time, venueID *uuid.UUID) ([]*event.TimetableEntry, error) {
+	var entries []*event.TimetableEntry
+
+	query := repo.db.WithContext(ctx).
+		Joins("JOIN events ON events.id = timetable_entries.event_id AND events.deleted_at IS NULL").
+		Where("timetable_entries.start_time >= ? AND timetable_entries.start_time < ?", from, to).
+		Where(debutCondition)
+
+	if venueID != nil {
+		query = query.Where("events.venue_id = ?", *venueID)
+	}
+
+	err := query.Order("timetable_entries.start_time ASC").
+		Preload("Event").
+		Find(&entries).Error
+	return entries, err
+}
+
+// IsDebut reports whether the timetable entry is the artist's first set at the event's venue.
+func (repo *EventRepository) IsDebut(ctx context.Context, entryID uuid.UUID) (bool, error) {
+	var count int64
+	err := repo.db.WithContext(ctx).Model(&event.TimetableEntry{}).
+		Joins("JOIN events ON events.id = timetable_entries.event_id AND events.deleted_at IS NULL").
+		Where("timetable_entries.id = ?", entryID).
+		Where(debutCondition).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (repo *EventRepository) FindByID(ctx context.Context, id uuid.UUID) (*event.Event, error) {
+	var eventModel event.Event
+	if err := repo.db.WithContext(ctx).Where("id = ?", id).
+		First(&eventModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("event", id)
+		}
+		return nil, err
+	}
+	return &eventModel, nil
+}
+
+// FindUpcoming returns up to limit events that have not ended yet with their venues, ordered by start date. A nil
+// venueID returns the events of all venues.
+func (repo *EventRepository) FindUpcoming(ctx context.Context, venueID *uuid.UUID, limit int) ([]*event.Event, error) {
+	var events []*event.Event
+	query := repo.db.WithContext(ctx).Preload("Venue").Where("end_date >= ?", time.Now())
+	if venueID != nil {
+		query = query.Where("venue_id = ?", *venueID)
+	}
+	err := query.Order("start_date ASC").Order("id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// FindWithTimetable returns the event with its venue and its timetable ordered by start time, the entries with their
+// stages and artists.
+func (repo *EventRepository) FindWithTimetable(ctx context.Context, id uuid.UUID) (*event.Event, error) {
+	var eventModel event.Event
+	err := repo.db.WithContext(ctx).
+		Preload("Venue").
+		Preload("Timetable", func(db *gorm.DB) *gorm.DB { return db.Order("start_time ASC").Order("id ASC") }).
+		Preload("Timetable.Stage").
+		Preload("Timetable.Artist").
+		Where("id = ?", id).
+		First(&eventModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("event", id)
+		}
+		return nil, err
+	}
+	return &eventModel, nil
+}
+
+// FindByIDs returns the events with the given IDs in no particular order, skipping IDs that do not exist.
+func (repo *EventRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*event.Event, error) {
+	var events []*event.Event
+	if len(ids) == 0 {
+		return events, nil
+	}
+	err := repo.db.WithContext(ctx).Where("id IN ?", ids).Find(&events).Error
+	return events, err
+}
+
+// FindTimetableEntriesByIDs returns the timetable entries with the given IDs in no particular order, skipping IDs
+// that do not exist.
+func (repo *EventRepository) FindTimetableEntriesByIDs(ctx context.Context, ids []uuid.UUID) ([]*event.TimetableEntry, error) {
+	var entries []*event.TimetableEntry
+	if len(ids) == 0 {
+		return entries, nil
+	}
+	err := repo.db.WithContext(ctx).Where("id IN ?", ids).Find(&entries).Error
+	return entries, err
+}
+
+// FindTimetablesByEventIDs returns the timetable entries of the given events ordered by start time.
+func (repo *EventRepository) FindTimetablesByEventIDs(ctx context.Context, eventIDs []uuid.UUID) ([]*event.TimetableEntry, error) {
+	var entries []*event.TimetableEntry
+	if len(eventIDs) == 0 {
+		return entries, nil
+	}
+	err := repo.db.WithContext(ctx).Where("event_id IN ?", eventIDs).
+		Order("start_time ASC").Order("id ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// SetHost assigns the hosting collective of an event. A nil collectiveID clears the host.
+func (repo *EventRepository) SetHost(ctx context.Context, eventID uuid.UUID, collectiveID *uuid.UUID) error {
+	result := repo.db.WithContext(ctx).Model(&event.Event{}).
+		Where("id = ?", eventID).
+		Update("host_collective_id", collectiveID)
+	if result.Error != nil {
+		return fmt.Errorf("error setting event host: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("event", eventID)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExternalProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewExternalProfileRepository(db *gorm.DB) *ExternalProfileRepository {
+	return &ExternalProfileRepository{db: db}
+}
+
+func (r *ExternalProfileRepository) FindByArtistID(ctx context.Context, artistID uuid.UUID) ([]artist.ExternalProfile, error) {
+	var profiles []artist.ExternalProfile
+	err := r.db.WithContext(ctx).
+		Where("artist_id = ?", artistID).
+		Order("priority DESC").
+		Find(&profiles).Error
+	return profiles, err
+}
+
+// Upsert stores the profile, replacing the existing profile of the same artist and provider.
+func (r *ExternalProfileRepository) Upsert(ctx context.Context, profile *artist.ExternalProfile) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "artist_id"}, {Name: "provider"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"priority", "external_id", "url", "username", "display_name", "first_name", "last_name",
+				"avatar_url", "description", "city", "country", "followers", "fetched_at", "updated_at",
+			}),
+		}).
+		Create(profile).Error
+}
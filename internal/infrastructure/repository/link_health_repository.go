@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LinkHealthRepository struct {
+	db *gorm.DB
+}
+
+func NewLinkHealthRepository(db *gorm.DB) *LinkHealthRepository {
+	return &LinkHealthRepository{db: db}
+}
+
+// FindLinksDueForCheck returns links that were never checked or not checked since checkedBefore,
+// least recently checked first.
+func (r *LinkHealthRepository) FindLinksDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]artist.SocialMediaLink, error) {
+	var links []artist.SocialMediaLink
+	err := r.db.WithContext(ctx).
+		Joins("LEFT JOIN social_media_link_health ON social_media_link_health.link_id = social_media_links.id").
+		Where("social_media_link_health.last_checked_at IS NULL OR social_media_link_health.last_checked_at < ?", checkedBefore).
+		Order("social_media_link_health.last_checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&links).Error
+	return links, err
+}
+
+// FindByLinkID returns the health record of a link, or nil if the link has not been checked yet.
+func (r *LinkHealthRepository) FindByLinkID(ctx context.Context, linkID uuid.UUID) (*artist.LinkHealth, error) {
+	var health artist.LinkHealth
+	if err := r.db.WithContext(ctx).Where("link_id = ?", linkID).First(&health).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &health, nil
+}
+
+func (r *LinkHealthRepository) Save(ctx context.Context, health *artist.LinkHealth) error {
+	return r.db.WithContext(ctx).
+		Omit("Link").
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(health).Error
+}
+
+// FindBroken fetches a page of broken links starting after the given cursor, together with the link itself.
+func (r *LinkHealthRepository) FindBroken(ctx context.Context, cursor string, limit int) ([]artist.LinkHealth, string, error) {
+	var results []artist.LinkHealth
+	var nextCursor string
+
+	query := r.db.WithContext(ctx).
+		Joins("JOIN social_media_links ON social_media_links.id = social_media_link_health.link_id AND social_media_links.deleted_at IS NULL").
+		Where("social_media_link_health.broken = ?", true).
+		Order("social_media_link_health.link_id ASC")
+
+	if cursor != "" {
+		query = query.Where("social_media_link_health.link_id > ?", cursor)
+	}
+
+	if err := query.Limit(limit).Preload("Link").Find(&results).Error; err != nil {
+		return nil, "", err
+	}
+
+	// Set next cursor
+	if len(results) > 0 {
+		nextCursor = results[len(results)-1].LinkID.String()
+	}
+
+	return results, nextCursor, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/event"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PopularityRepository struct {
+	db *gorm.DB
+}
+
+func NewPopularityRepository(db *gorm.DB) *PopularityRepository {
+	return &PopularityRepository{db: db}
+}
+
+func (r *PopularityRepository) SaveSnapshot(ctx context.Context, snapshot *artist.PopularitySnapshot) error {
+	return r.db.WithContext(ctx).Create(snapshot).Error
+}
+
+// FindSnapshotRanges returns, per artist with a snapshot taken on the provider since the given time, the growth
+// baseline and the last snapshot. The baseline is the latest snapshot taken at or before since, so a window shorter
+// than the snapshot interval still spans two snapshots, and the first snapshot of the window for artists first seen
+// inside it.
+func (r *PopularityRepository) FindSnapshotRanges(ctx context.Context, name provider.Name, since time.Time) ([]artist.SnapshotRange, error) {
+	var before, first, last []artist.PopularitySnapshot
+	db := r.db.WithContext(ctx)
+	if err := db.Raw(`SELECT DISTINCT ON (artist_id) * FROM artist_popularity_snapshots
+		WHERE provider = ? AND taken_at <= ? ORDER BY artist_id, taken_at DESC`, name, since).
+		Scan(&before).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Raw(`SELECT DISTINCT ON (artist_id) * FROM artist_popularity_snapshots
+		WHERE provider = ? AND taken_at >= ? ORDER BY artist_id, taken_at ASC`, name, since).
+		Scan(&first).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Raw(`SELECT DISTINCT ON (artist_id) * FROM artist_popularity_snapshots
+		WHERE provider = ? AND taken_at >= ? ORDER BY artist_id, taken_at DESC`, name, since).
+		Scan(&last).Error; err != nil {
+		return nil, err
+	}
+
+	baseline := make(map[uuid.UUID]artist.PopularitySnapshot, len(before))
+	for _, s := range before {
+		baseline[s.ArtistID] = s
+	}
+	latest := make(map[uuid.UUID]artist.PopularitySnapshot, len(last))
+	for _, s := range last {
+		latest[s.ArtistID] = s
+	}
+	ranges := make([]artist.SnapshotRange, 0, len(first))
+	for _, s := range first {
+		if b, ok := baseline[s.ArtistID]; ok {
+			s = b
+		}
+		ranges = append(ranges, artist.SnapshotRange{ArtistID: s.ArtistID, First: s, Last: latest[s.ArtistID]})
+	}
+	return ranges, nil
+}
+
+// CountUpcomingBookings counts the timetable entries of every artist starting between from and until.
+func (r *PopularityRepository) CountUpcomingBookings(ctx context.Context, from, until time.Time) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		ArtistID uuid.UUID
+		Bookings int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&event.TimetableEntry{}).
+		Select("artist_id, COUNT(*) AS bookings").
+		Where("start_time >= ? AND start_time < ?", from, until).
+		Group("artist_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.ArtistID] = row.Bookings
+	}
+	return counts, nil
+}
+
+// FindActivePins returns the featured pins that have not expired, in position order.
+func (r *PopularityRepository) FindActivePins(ctx context.Context, at time.Time) ([]artist.FeaturedPin, error) {
+	var pins []artist.FeaturedPin
+	err := r.db.WithContext(ctx).
+		Where("expires_at IS NULL OR expires_at > ?", at).
+		Order("position ASC, pinned_at ASC").
+		Find(&pins).Error
+	return pins, err
+}
+
+func (r *PopularityRepository) SavePin(ctx context.Context, pin *artist.FeaturedPin) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(pin).Error
+}
+
+// DeletePin removes the pin of an artist and reports whether there was one.
+func (r *PopularityRepository) DeletePin(ctx context.Context, artistID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&artist.FeaturedPin{}, "artist_id = ?", artistID)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProfileSuggestionRepository struct {
+	db *gorm.DB
+}
+
+func NewProfileSuggestionRepository(db *gorm.DB) *ProfileSuggestionRepository {
+	return &ProfileSuggestionRepository{db: db}
+}
+
+// FindUnlinkedArtists fetches a page of artists without a profile or permalink for the provider whose last
+// discovery attempt is older than checkedBefore, ordered by id and starting after the cursor.
+func (r *ProfileSuggestionRepository) FindUnlinkedArtists(ctx context.Context, name provider.Name, checkedBefore time.Time, cursor string, limit int) ([]artist.Artist, error) {
+	var artists []artist.Artist
+	query := r.db.WithContext(ctx).
+		Where("artists.last_discovery_at IS NULL OR artists.last_discovery_at < ?", checkedBefore).
+		Where("NOT EXISTS (SELECT 1 FROM artist_external_profiles p WHERE p.artist_id = artists.id AND p.provider = ?)", name).
+		Order("artists.id ASC")
+
+	if name == provider.SoundCloud {
+		query = query.Where("artists.sc_permalink IS NULL OR artists.sc_permalink = ''")
+	}
+	if cursor != "" {
+		query = query.Where("artists.id > ?", cursor)
+	}
+
+	err := query.Limit(limit).
+		Scopes(withDisplayProfile("")).
+		Find(&artists).Error
+	return artists, err
+}
+
+// MarkDiscoveryChecked records when discovery last searched for the artist.
+func (r *ProfileSuggestionRepository) MarkDiscoveryChecked(ctx context.Context, artistID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&artist.Artist{}).Where("id = ?", artistID).Update("last_discovery_at", at).Error
+}
+
+// IsLinkedElsewhere reports whether the provider profile already belongs to an artist other than artistID.
+func (r *ProfileSuggestionRepository) IsLinkedElsewhere(ctx context.Context, name provider.Name, externalID string, artistID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&artist.ExternalProfile{}).
+		Where("provider = ? AND external_id = ? AND artist_id <> ?", name, externalID, artistID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SaveSuggestions stores new suggestions. Suggestions that already exist get fresh scores while they are still
+// pending; decided ones are left alone.
+func (r *ProfileSuggestionRepository) SaveSuggestions(ctx context.Context, suggestions []artist.ProfileSuggestion) error {
+	if len(suggestions) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "artist_id"}, {Name: "provider"}, {Name: "external_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"url", "username", "display_name", "avatar_url", "city", "country", "followers",
+				"score", "name_score", "location_score", "follower_score", "linked_elsewhere", "status", "decided_at", "updated_at",
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Eq{Column: clause.Column{Table: "artist_profile_suggestions", Name: "status"}, Value: artist.SuggestionPending},
+			}},
+		}).
+		Create(&suggestions).Error
+}
+
+// FindByID fetches a suggestion.
+func (r *ProfileSuggestionRepository) FindByID(ctx context.Context, id uuid.UUID) (*artist.ProfileSuggestion, error) {
+	var suggestion artist.ProfileSuggestion
+	if err := r.db.WithContext(ctx).First(&suggestion, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("profile suggestion", id)
+		}
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+// FindByStatusAndCursor fetches a page of suggestions with the given status ordered by id, starting after the
+// cursor. An empty status returns every suggestion.
+func (r *ProfileSuggestionRepository) FindByStatusAndCursor(ctx context.Context, status artist.SuggestionStatus, cursor string, limit int) ([]artist.ProfileSuggestion, string, error) {
+	var suggestions []artist.ProfileSuggestion
+	query := r.db.WithContext(ctx).Order("id ASC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if cursor != "" {
+		query = query.Where("id > ?", cursor)
+	}
+	if err := query.Limit(limit).Find(&suggestions).Error; err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(suggestions) > 0 {
+		nextCursor = suggestions[len(suggestions)-1].ID.String()
+	}
+	return suggestions, nextCursor, nil
+}
+
+// Decide stores the status of a suggestion. When a suggestion is linked, the other pending suggestions of the
+// artist for the same provider are rejected.
+func (r *ProfileSuggestionRepository) Decide(ctx context.Context, suggestion *artist.ProfileSuggestion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(suggestion).Updates(map[string]interface{}{
+			"status":     suggestion.Status,
+			"decided_at": suggestion.DecidedAt,
+		}).Error; err != nil {
+			return err
+		}
+		if suggestion.Status != artist.SuggestionAccepted && suggestion.Status != artist.SuggestionAutoLinked {
+			return nil
+		}
+		return tx.Model(&artist.ProfileSuggestion{}).
+			Where("artist_id = ? AND provider = ? AND id <> ? AND status = ?", suggestion.ArtistID, suggestion.Provider, suggestion.ID, artist.SuggestionPending).
+			Updates(map[string]interface{}{
+				"status":     artist.SuggestionRejected,
+				"decided_at": suggestion.DecidedAt,
+			}).Error
+	})
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromotedSetRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotedSetRepository(db *gorm.DB) *PromotedSetRepository {
+	return &PromotedSetRepository{db: db}
+}
+
+// FindDueForCheck returns artists with a promoted set that was never resolved or not checked since checkedBefore,
+// least recently checked first.
+func (r *PromotedSetRepository) FindDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]artist.Artist, error) {
+	var artists []artist.Artist
+	err := r.db.WithContext(ctx).
+		Joins("LEFT JOIN artist_promoted_sets ON artist_promoted_sets.artist_id = artists.id").
+		Where("artists.sc_promoted_set IS NOT NULL AND artists.sc_promoted_set <> ''").
+		Where("artist_promoted_sets.last_checked_at IS NULL OR artist_promoted_sets.last_checked_at < ?", checkedBefore).
+		Order("artist_promoted_sets.last_checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&artists).Error
+	return artists, err
+}
+
+// FindByArtistID returns the promoted set of an artist, or nil if it was never resolved.
+func (r *PromotedSetRepository) FindByArtistID(ctx context.Context, artistID uuid.UUID) (*artist.PromotedSet, error) {
+	var set artist.PromotedSet
+	if err := r.db.WithContext(ctx).Where("artist_id = ?", artistID).First(&set).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &set, nil
+}
+
+// FindByArtistIDs returns the promoted sets of the artists in no particular order, skipping artists without one.
+func (r *PromotedSetRepository) FindByArtistIDs(ctx context.Context, artistIDs []uuid.UUID) ([]artist.PromotedSet, error) {
+	var sets []artist.PromotedSet
+	if len(artistIDs) == 0 {
+		return sets, nil
+	}
+	err := r.db.WithContext(ctx).Where("artist_id IN ?", artistIDs).Find(&sets).Error
+	return sets, err
+}
+
+// Save stores the promoted set, replacing the artist's previous one but keeping when it was first created.
+func (r *PromotedSetRepository) Save(ctx context.Context, set *artist.PromotedSet) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "artist_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"provider", "url", "title", "thumbnail_url", "author_name", "duration_ms", "embed_html", "resolved_at",
+				"last_checked_at", "last_error", "consecutive_failures", "broken", "updated_at",
+			}),
+		}).
+		Create(set).Error
+}
+
+// DeleteByArtistID removes the promoted set of an artist.
+func (r *PromotedSetRepository) DeleteByArtistID(ctx context.Context, artistID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&artist.PromotedSet{}, "artist_id = ?", artistID).Error
+}
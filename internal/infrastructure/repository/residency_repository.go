@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/venue"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ResidencyRepository struct {
+	db *gorm.DB
+}
+
+func NewResidencyRepository(db *gorm.DB) *ResidencyRepository {
+	return &ResidencyRepository{db: db}
+}
+
+func (r *ResidencyRepository) FindByID(ctx context.Context, id uuid.UUID) (*venue.Residency, error) {
+	var residency venue.Residency
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&residency).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("residency", id)
+		}
+		return nil, err
+	}
+	return &residency, nil
+}
+
+func (r *ResidencyRepository) FindByArtistID(ctx context.Context, artistID uuid.UUID) ([]*venue.Residency, error) {
+	var residencies []*venue.Residency
+	err := r.db.WithContext(ctx).
+		Where("artist_id = ?", artistID).
+		Order("start_date DESC").
+		Find(&residencies).Error
+	return residencies, err
+}
+
+// FindByVenueID returns the residencies of a venue. Unless includePast is set only residencies active today are returned.
+func (r *ResidencyRepository) FindByVenueID(ctx context.Context, venueID uuid.UUID, includePast bool) ([]*venue.Residency, error) {
+	var residencies []*venue.Residency
+	query := r.db.WithContext(ctx).Where("venue_id = ?", venueID)
+	if !includePast {
+		now := time.Now()
+		query = query.Where("start_date <= ? AND (end_date IS NULL OR end_date >= ?)", now, now)
+	}
+	err := query.Order("start_date ASC").Find(&residencies).Error
+	return residencies, err
+}
+
+// FindActiveForTimetableEntries returns the residencies that cover the entries' artists at the entries' venues at the
+// time of the sets by entry ID, the earliest one if several do. Entries of artists that were not residents are left
+// out.
+func (r *ResidencyRepository) FindActiveForTimetableEntries(ctx context.Context, entryIDs []uuid.UUID) (map[uuid.UUID]*venue.Residency, error) {
+	result := make(map[uuid.UUID]*venue.Residency, len(entryIDs))
+	if len(entryIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		venue.Residency
+		EntryID uuid.UUID
+	}
+	err := r.db.WithContext(ctx).
+		Table("residencies").
+		Select("DISTINCT ON (timetable_entries.id) residencies.*, timetable_entries.id AS entry_id").
+		Joins("JOIN events ON events.venue_id = residencies.venue_id AND events.deleted_at IS NULL").
+		Joins("JOIN timetable_entries ON timetable_entries.event_id = events.id AND timetable_entries.deleted_at IS NULL").
+		Where("timetable_entries.id IN ?", entryIDs).
+		Where("residencies.deleted_at IS NULL").
+		Where("residencies.artist_id = timetable_entries.artist_id").
+		Where("residencies.start_date <= timetable_entries.start_time").
+		Where("residencies.end_date IS NULL OR residencies.end_date >= timetable_entries.start_time").
+		Order("timetable_entries.id, residencies.start_date ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		result[rows[i].EntryID] = &rows[i].Residency
+	}
+	return result, nil
+}
+
+// Save stores the residency unless its artist or venue does not exist or the artist already is a resident of the
+// venue during that period. The venue row stays locked until the residency is stored, so concurrent requests for the
+// same venue cannot both pass the check.
+func (r *ResidencyRepository) Save(ctx context.Context, residency *venue.Residency) (*venue.Residency, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&venue.Venue{}, "id = ?", residency.VenueID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("venue", residency.VenueID)
+		}
+		if err != nil {
+			return err
+		}
+		err = tx.Select("id").First(&artist.Artist{}, "id = ?", residency.ArtistID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("artist", residency.ArtistID)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Periods include their end date, open-ended residencies overlap every later one
+		query := tx.Model(&venue.Residency{}).
+			Where("artist_id = ? AND venue_id = ? AND id <> ?", residency.ArtistID, residency.VenueID, residency.ID).
+			Where("end_date IS NULL OR end_date >= ?", residency.StartDate)
+		if residency.EndDate != nil {
+			query = query.Where("start_date <= ?", *residency.EndDate)
+		}
+		var overlapping int64
+		if err := query.Count(&overlapping).Error; err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return venue.ErrResidencyOverlap
+		}
+		return tx.Save(residency).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error saving residency: %w", err)
+	}
+
+	return residency, nil
+}
+
+func (r *ResidencyRepository) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&venue.Residency{}, id)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("error deleting residency: %v", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, apperror.NotFound("residency", id)
+	}
+
+	return true, nil
+}
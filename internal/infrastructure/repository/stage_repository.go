@@ -1,6 +1,12 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"context"
+
+	"github.com/blnto/blnto_service/internal/domain/stage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 type StageRepository struct {
 	db *gorm.DB
@@ -10,6 +16,22 @@ func NewStageRepository(db *gorm.DB) *StageRepository {
 	return &StageRepository{db: db}
 }
 
-func getByVenueID() {
+// FindByIDs returns the stages with the given IDs in no particular order, skipping IDs that do not exist.
+func (r *StageRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]stage.Stage, error) {
+	var stages []stage.Stage
+	if len(ids) == 0 {
+		return stages, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&stages).Error
+	return stages, err
+}
 
+// FindByVenueIDs returns the stages of the given venues ordered by name.
+func (r *StageRepository) FindByVenueIDs(ctx context.Context, venueIDs []uuid.UUID) ([]stage.Stage, error) {
+	var stages []stage.Stage
+	if len(venueIDs) == 0 {
+		return stages, nil
+	}
+	err := r.db.WithContext(ctx).Where("venue_id IN ?", venueIDs).Order("stage_name ASC").Find(&stages).Error
+	return stages, err
 }
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SyncRunRepository struct {
+	db *gorm.DB
+}
+
+func NewSyncRunRepository(db *gorm.DB) *SyncRunRepository {
+	return &SyncRunRepository{db: db}
+}
+
+// FindStaleArtists fetches a page of artists last synced before staleBefore, ordered by id and starting after
+// the cursor. Artists that already failed in the given run are skipped so a resumed run does not retry them.
+func (r *SyncRunRepository) FindStaleArtists(ctx context.Context, staleBefore time.Time, runID uuid.UUID, cursor string, limit int) ([]artist.Artist, error) {
+	var artists []artist.Artist
+	query := r.db.WithContext(ctx).
+		Where("artists.last_synced_at IS NULL OR artists.last_synced_at < ?", staleBefore).
+		Where("NOT EXISTS (SELECT 1 FROM artist_sync_run_errors e WHERE e.run_id = ? AND e.artist_id = artists.id)", runID).
+		Order("artists.id ASC")
+
+	if cursor != "" {
+		query = query.Where("artists.id > ?", cursor)
+	}
+
+	err := query.Limit(limit).
+		Preload("SocialMediaLinks").
+		Preload("ExternalProfiles").
+		Find(&artists).Error
+	return artists, err
+}
+
+// MarkArtistSynced records when the artist was last synced.
+func (r *SyncRunRepository) MarkArtistSynced(ctx context.Context, artistID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&artist.Artist{}).Where("id = ?", artistID).Update("last_synced_at", at).Error
+}
+
+// FindResumableRun returns the latest run that did not finish, or nil if there is none.
+func (r *SyncRunRepository) FindResumableRun(ctx context.Context) (*artist.SyncRun, error) {
+	var run artist.SyncRun
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", []artist.SyncRunStatus{artist.SyncRunning, artist.SyncInterrupted}).
+		Order("started_at DESC").
+		First(&run).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *SyncRunRepository) FindRunByID(ctx context.Context, id uuid.UUID) (*artist.SyncRun, error) {
+	var run artist.SyncRun
+	if err := r.db.WithContext(ctx).Preload("Errors").Where("id = ?", id).First(&run).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *SyncRunRepository) CreateRun(ctx context.Context, run *artist.SyncRun) error {
+	return r.db.WithContext(ctx).Omit("Errors").Create(run).Error
+}
+
+// UpdateRunStatus stores the status and finish time of the run, leaving the counters alone.
+func (r *SyncRunRepository) UpdateRunStatus(ctx context.Context, run *artist.SyncRun) error {
+	return r.db.WithContext(ctx).Model(&artist.SyncRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+		"status":      run.Status,
+		"finished_at": run.FinishedAt,
+	}).Error
+}
+
+// RecordSuccess counts a synced artist in the run.
+func (r *SyncRunRepository) RecordSuccess(ctx context.Context, runID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&artist.SyncRun{}).Where("id = ?", runID).Updates(map[string]interface{}{
+		"total":     gorm.Expr("total + 1"),
+		"succeeded": gorm.Expr("succeeded + 1"),
+	}).Error
+}
+
+// RecordFailure counts a failed artist in the run and stores its error.
+func (r *SyncRunRepository) RecordFailure(ctx context.Context, runID, artistID uuid.UUID, syncErr error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&artist.SyncRunError{RunID: runID, ArtistID: artistID, Error: syncErr.Error()}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&artist.SyncRun{}).Where("id = ?", runID).Updates(map[string]interface{}{
+			"total":  gorm.Expr("total + 1"),
+			"failed": gorm.Expr("failed + 1"),
+		}).Error
+	})
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TrackRepository struct {
+	db *gorm.DB
+}
+
+func NewTrackRepository(db *gorm.DB) *TrackRepository {
+	return &TrackRepository{db: db}
+}
+
+// publishedKey sorts tracks without a publish date last
+const publishedKey = "COALESCE(published_at, '-infinity')"
+
+// FindByArtistID fetches a page of the artist's available tracks starting after the given cursor, newest first.
+func (r *TrackRepository) FindByArtistID(ctx context.Context, artistID uuid.UUID, cursor string, limit int) ([]artist.Track, string, error) {
+	var tracks []artist.Track
+	var nextCursor string
+
+	query := r.db.WithContext(ctx).
+		Where("artist_id = ? AND removed_at IS NULL", artistID).
+		Order(publishedKey + " DESC").Order("id DESC")
+
+	if cursor != "" {
+		after, err := r.trackCursor(ctx, cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		// Keyset pagination on (published_at, id) so the cursor stays stable while tracks are added
+		query = query.Where(
+			"("+publishedKey+", id) < (SELECT "+publishedKey+", id FROM artist_tracks WHERE id = ?)",
+			after,
+		)
+	}
+
+	if err := query.Limit(limit).Find(&tracks).Error; err != nil {
+		return nil, "", err
+	}
+
+	// Set next cursor
+	if len(tracks) > 0 {
+		nextCursor = tracks[len(tracks)-1].ID.String()
+	}
+
+	return tracks, nextCursor, nil
+}
+
+// trackCursor returns the ID of the track a tracks cursor points at. Tracks removed since the previous page still
+// mark their position.
+func (r *TrackRepository) trackCursor(ctx context.Context, cursor string) (uuid.UUID, error) {
+	id, err := uuid.Parse(cursor)
+	if err != nil {
+		return uuid.Nil, apperror.Invalid("after", "after must be the cursor of a previous page")
+	}
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&artist.Track{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return uuid.Nil, err
+	}
+	if count == 0 {
+		return uuid.Nil, apperror.NotFound("track", id)
+	}
+	return id, nil
+}
+
+// Upsert stores the tracks keyed by provider and external ID. Tracks that were marked removed are restored.
+func (r *TrackRepository) Upsert(ctx context.Context, tracks []artist.Track) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "provider"}, {Name: "external_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"artist_id", "title", "duration_ms", "artwork_url", "genre", "permalink_url", "playback_count",
+				"likes_count", "comment_count", "reposts_count", "published_at", "last_seen_at", "removed_at", "updated_at",
+			}),
+		}).
+		CreateInBatches(tracks, 100).Error
+}
+
+// MarkMissing flags the artist's tracks of a provider that were not seen since seenSince as removed.
+func (r *TrackRepository) MarkMissing(ctx context.Context, artistID uuid.UUID, name provider.Name, seenSince time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&artist.Track{}).
+		Where("artist_id = ? AND provider = ? AND last_seen_at < ? AND removed_at IS NULL", artistID, name, seenSince).
+		Update("removed_at", time.Now())
+	return result.RowsAffected, result.Error
+}
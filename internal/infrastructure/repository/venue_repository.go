@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/blnto/blnto_service/internal/domain/apperror"
 	"github.com/blnto/blnto_service/internal/domain/venue"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -28,7 +30,7 @@ func (r *VenueRepository) FindAllByCursor(ctx context.Context, cursor string, li
 		query = query.Where("id > ?", cursor)
 	}
 
-	err := query.Limit(limit).Preload("Stages").Find(&venues).Error
+	err := query.Limit(limit).Find(&venues).Error
 	if err != nil {
 		return nil, "", err
 	}
@@ -41,6 +43,43 @@ func (r *VenueRepository) FindAllByCursor(ctx context.Context, cursor string, li
 	return venues, nextCursor, nil
 }
 
+func (r *VenueRepository) FindByID(ctx context.Context, id uuid.UUID) (*venue.Venue, error) {
+	var venueModel venue.Venue
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&venueModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("venue", id)
+		}
+		return nil, err
+	}
+	return &venueModel, nil
+}
+
+// FindByIDWithStages returns the venue with its stages ordered by name.
+func (r *VenueRepository) FindByIDWithStages(ctx context.Context, id uuid.UUID) (*venue.Venue, error) {
+	var venueModel venue.Venue
+	err := r.db.WithContext(ctx).
+		Preload("Stages", func(db *gorm.DB) *gorm.DB { return db.Order("stage_name ASC").Order("id ASC") }).
+		Where("id = ?", id).
+		First(&venueModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("venue", id)
+		}
+		return nil, err
+	}
+	return &venueModel, nil
+}
+
+// FindByIDs returns the venues with the given IDs in no particular order, skipping IDs that do not exist.
+func (r *VenueRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]venue.Venue, error) {
+	var venues []venue.Venue
+	if len(ids) == 0 {
+		return venues, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&venues).Error
+	return venues, err
+}
+
 func (r *VenueRepository) Save(ctx context.Context, venue *venue.Venue) (*venue.Venue, error) {
 	// Save the venue to the database
 	result := r.db.WithContext(ctx).Save(venue)
@@ -61,7 +100,7 @@ func (r *VenueRepository) Delete(ctx context.Context, id uuid.UUID) (bool, error
 	}
 
 	if result.RowsAffected == 0 {
-		return false, fmt.Errorf("venue not found")
+		return false, apperror.NotFound("venue", id)
 	}
 
 	return true, nil
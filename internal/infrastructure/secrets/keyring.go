@@ -0,0 +1,156 @@
+// Package secrets encrypts values stored at rest with AES-256-GCM. Keys are identified by an ID that is
+// stored next to every ciphertext, so keys can be rotated: new values use the current key, older values keep
+// decrypting with the key they were written with until they are re-encrypted.
+package secrets
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// KeySize is the length of an AES-256 key in bytes
+const KeySize = 32
+
+// ErrUnknownKey is returned when a ciphertext names a key that is not in the keyring
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Keyring holds the encryption keys by ID and the ID of the key used for new ciphertexts
+type Keyring struct {
+	current string
+	aeads   map[string]cipher.AEAD
+}
+
+// NewKeyring builds a keyring from raw keys. currentID must be one of the keys.
+func NewKeyring(currentID string, keys map[string][]byte) (*Keyring, error) {
+	if currentID == "" {
+		return nil, errors.New("current key id is empty")
+	}
+	if _, ok := keys[currentID]; !ok {
+		return nil, fmt.Errorf("current key %q is not in the keyring", currentID)
+	}
+
+	keyring := &Keyring{current: currentID, aeads: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("key %q must be %d bytes, got %d", id, KeySize, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		keyring.aeads[id] = aead
+	}
+	return keyring, nil
+}
+
+// LoadKeyring reads the keys from the TOKEN_ENCRYPTION_KEYS variable, or from the file named by
+// TOKEN_ENCRYPTION_KEY_FILE when set. Both hold "id:base64key" entries, separated by commas in the variable and
+// by lines in the file. TOKEN_ENCRYPTION_KEY_ID selects the key used for new ciphertexts.
+func LoadKeyring() (*Keyring, error) {
+	var entries []string
+	if path := os.Getenv("TOKEN_ENCRYPTION_KEY_FILE"); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening key file: %w", err)
+		}
+		defer file.Close()
+		entries, err = readLines(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading key file: %w", err)
+		}
+	} else {
+		entries = strings.Split(os.Getenv("TOKEN_ENCRYPTION_KEYS"), ",")
+	}
+
+	keys, err := ParseKeys(entries)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyring(os.Getenv("TOKEN_ENCRYPTION_KEY_ID"), keys)
+}
+
+// ParseKeys decodes "id:base64key" entries, skipping blank entries and # comments
+func ParseKeys(entries []string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("key entry must look like id:base64key")
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not valid base64: %w", id, err)
+		}
+		if _, exists := keys[id]; exists {
+			return nil, fmt.Errorf("key %q is defined twice", id)
+		}
+		keys[id] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no encryption keys configured")
+	}
+	return keys, nil
+}
+
+// CurrentKeyID returns the ID of the key used by Encrypt
+func (k *Keyring) CurrentKeyID() string {
+	return k.current
+}
+
+// Encrypt seals the plaintext with the current key and returns the key ID and the base64 encoded
+// nonce and ciphertext
+func (k *Keyring) Encrypt(plaintext string) (keyID string, ciphertext string, err error) {
+	aead := k.aeads[k.current]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.current))
+	return k.current, base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a ciphertext produced by Encrypt with the key it names
+func (k *Keyring) Decrypt(keyID string, ciphertext string) (string, error) {
+	aead, ok := k.aeads[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("ciphertext is not valid base64: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("ciphertext is too short")
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("error decrypting with key %q: %w", keyID, err)
+	}
+	return string(plaintext), nil
+}
+
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
@@ -2,28 +2,61 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"go.uber.org/zap"
 	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/blnto/blnto_service/internal"
+	"github.com/blnto/blnto_service/internal/api/connect"
+	"github.com/blnto/blnto_service/internal/api/graphql/constraint"
+	"github.com/blnto/blnto_service/internal/api/graphql/presenter"
 	"github.com/blnto/blnto_service/internal/infrastructure/graphql"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take to finish once the process is interrupted
+const shutdownTimeout = 15 * time.Second
+
 // Defining the Graphql handler
 func graphqlHandler(app *internal.App) gin.HandlerFunc {
-	// Resolver is in the resolver.go file
-	h := handler.NewDefaultServer(graphql.NewExecutableSchema(graphql.Config{Resolvers: app.Resolver}))
+	// Resolver is in the resolver.go file, the dataloaders of each request are set up before it runs
+	srv := handler.New(graphql.NewExecutableSchema(graphql.Config{Resolvers: app.Resolver}))
+	srv.AddTransport(transport.Websocket{KeepAlivePingInterval: 10 * time.Second})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.SetQueryCache(lru.New(1000))
+	srv.SetErrorPresenter(presenter.ErrorPresenter(app.Logger))
+	srv.SetRecoverFunc(presenter.Recover(app.Logger))
+	srv.Use(extension.Introspection{})
+
+	// Persisted queries resolve from the registry first; in strict mode only registered operations run
+	srv.Use(extension.AutomaticPersistedQuery{Cache: app.PersistedQueries.Cache()})
+	srv.Use(app.PersistedQueries)
+	srv.Use(app.QueryLimits)
+	// Inputs violating @constraint fail with all rejected fields before their resolvers run
+	srv.Use(constraint.NewValidator())
+	h := app.Loaders.Middleware(srv)
 
 	return func(c *gin.Context) {
 		h.ServeHTTP(c.Writer, c.Request)
@@ -46,6 +79,14 @@ func setupRoutes(router *gin.Engine, app *internal.App) {
 		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 	})
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	startLinks := connect.NewStartLinks(os.Getenv("SOUNDCLOUD_CONNECT_SECRET"))
+	soundCloud := connect.NewSoundCloudHandler(app.SoundCloudAccounts, startLinks, os.Getenv("SOUNDCLOUD_CONNECT_RETURN_URL"), app.Logger)
+	router.GET("/connect/soundcloud/start", soundCloud.Start)
+	router.GET("/connect/soundcloud/callback", soundCloud.Callback)
+
+	// Read-only JSON feed for partners, cacheable and revalidated with conditional requests
+	app.Feed.Register(router)
 }
 
 func main() {
@@ -59,7 +100,39 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize app: %v", err)
 	}
-	// artistApi.StartTokenRefreshScheduler(app.DB)
+	// Background jobs and the server stop when the process receives an interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var workers sync.WaitGroup
+	runWorker := func(run func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(ctx)
+		}()
+	}
+
+	if os.Getenv("LINK_CHECK_ENABLED") == "true" {
+		runWorker(app.LinkChecker.Run)
+	}
+
+	if os.Getenv("ARTIST_SYNC_ENABLED") == "true" {
+		runWorker(app.ArtistSync.Run)
+	}
+
+	if os.Getenv("ARTIST_DISCOVERY_ENABLED") == "true" {
+		runWorker(app.Discoverer.Run)
+	}
+
+	if os.Getenv("PROMOTED_SET_REFRESH_ENABLED") == "true" {
+		runWorker(app.PromotedSetRefresher.Run)
+	}
+
+	if os.Getenv("SOUNDCLOUD_TOKEN_REFRESH_ENABLED") == "true" {
+		runWorker(func(ctx context.Context) { app.SoundCloudClient.Tokens().Run(ctx, time.Minute) })
+	}
+
 	router := gin.Default()
 
 	router.Use(cors.New(cors.Config{
@@ -77,10 +150,29 @@ func main() {
 	defer logFile.Close()
 	defer logger.Sync()
 	// Start the application
-	if err := router.Run(":8080"); err != nil {
-		// handle error, perhaps log it and/or gracefully shut down
-		log.Fatalf("Failed to run server: %v", err)
+	srv := &http.Server{Addr: ":8080", Handler: router}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to run server: %v", err)
+		}
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Let in-flight requests finish before the workers and the database go away
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("failed to shut down server", zap.Error(err))
 	}
+	workers.Wait()
+	repository.CloseDatabaseConnection(app.DB)
 }
 
 func GraphQLLogger(logger *zap.Logger) gin.HandlerFunc {
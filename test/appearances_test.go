@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/event"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+func TestAppearancesRejectMalformedCursor(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewEventRepository(db)
+
+	_, _, err := repo.FindAppearancesByArtistID(context.Background(), uuid.New(), event.AppearanceFilter{}, "not-a-cursor", 10)
+	if apperror.KindOf(err) != apperror.KindValidation {
+		t.Fatalf("err = %v, want a validation error", err)
+	}
+	if statements := fake.executed(); len(statements) != 0 {
+		t.Errorf("malformed cursor reached the database: %v", statements)
+	}
+}
+
+func TestAppearancesReportUnknownCursor(t *testing.T) {
+	db, _ := newFakeDB(t)
+	repo := repository.NewEventRepository(db)
+
+	_, _, err := repo.FindAppearancesByArtistID(context.Background(), uuid.New(), event.AppearanceFilter{}, uuid.NewString(), 10)
+	if apperror.KindOf(err) != apperror.KindNotFound {
+		t.Fatalf("err = %v, want not found instead of an empty page", err)
+	}
+}
+
+func TestAppearancesPageAfterCursorPosition(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewEventRepository(db)
+	cursor := uuid.New()
+	start := time.Date(2026, 5, 1, 22, 0, 0, 0, time.UTC)
+	next := uuid.New()
+	fake.returns(`FROM "timetable_entries" WHERE id = $1`, []string{"id", "start_time"}, []driver.Value{cursor.String(), start})
+	fake.returns(`FROM "timetable_entries" WHERE artist_id`, []string{"id", "start_time"}, []driver.Value{next.String(), start})
+
+	filter := event.AppearanceFilter{Upcoming: true}
+	entries, nextCursor, err := repo.FindAppearancesByArtistID(context.Background(), uuid.New(), filter, cursor.String(), 10)
+	if err != nil {
+		t.Fatalf("FindAppearancesByArtistID: %v", err)
+	}
+	if len(entries) != 1 || nextCursor != next.String() {
+		t.Errorf("page = %d entries and cursor %s, want the entry %s", len(entries), nextCursor, next)
+	}
+
+	page, args, ok := fake.find(`FROM "timetable_entries" WHERE artist_id`)
+	if !ok {
+		t.Fatalf("no page query in %v", fake.executed())
+	}
+	// Upcoming pages run forward from the cursor's position, the entry itself is excluded
+	if !strings.Contains(page, "(start_time, id) > ($3, $4)") || !strings.Contains(page, "ORDER BY start_time ASC,id ASC") {
+		t.Errorf("page query = %s", page)
+	}
+	if len(args) < 4 || args[2] != start || args[3] != cursor {
+		t.Errorf("keyset arguments = %v, want %v and %s", args, start, cursor)
+	}
+}
+
+func TestPerformanceStatsBreakFavouriteStageTies(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewEventRepository(db)
+	stageID := uuid.New()
+	first := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	last := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	fake.returns("COUNT(*) AS total_sets", []string{"total_sets", "total_seconds", "first_appearance", "last_appearance"},
+		[]driver.Value{int64(3), float64(5400), first, last})
+	fake.returns(`FROM "stages"`, []string{"id", "stage_name"}, []driver.Value{stageID.String(), "Main"})
+
+	stats, err := repo.GetArtistPerformanceStats(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetArtistPerformanceStats: %v", err)
+	}
+	if stats.TotalSets != 3 || stats.TotalHoursPlayed != 1.5 || !stats.FirstAppearance.Equal(first) || !stats.LastAppearance.Equal(last) {
+		t.Errorf("stats = %+v", stats)
+	}
+	if stats.FavouriteStage == nil || stats.FavouriteStage.ID != stageID {
+		t.Errorf("favourite stage = %+v, want %s", stats.FavouriteStage, stageID)
+	}
+	favourite, _, _ := fake.find(`FROM "stages"`)
+	if !strings.Contains(favourite, "ORDER BY COUNT(timetable_entries.id) DESC,stages.id ASC") {
+		t.Errorf("favourite stage query = %s, want ties broken by stage ID", favourite)
+	}
+}
+
+func TestPerformanceStatsWithoutSets(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewEventRepository(db)
+	fake.returns("COUNT(*) AS total_sets", []string{"total_sets", "total_seconds", "first_appearance", "last_appearance"},
+		[]driver.Value{int64(0), float64(0), nil, nil})
+
+	stats, err := repo.GetArtistPerformanceStats(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetArtistPerformanceStats: %v", err)
+	}
+	if stats.TotalSets != 0 || stats.FavouriteStage != nil || stats.FirstAppearance != nil {
+		t.Errorf("stats = %+v, want none", stats)
+	}
+	if _, _, ok := fake.find(`FROM "stages"`); ok {
+		t.Error("looked up a favourite stage without any sets")
+	}
+}
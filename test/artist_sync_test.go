@@ -0,0 +1,242 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/application/artistsync"
+	"github.com/blnto/blnto_service/internal/application/discovery"
+	"github.com/blnto/blnto_service/internal/application/promotedset"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/blnto/blnto_service/internal/infrastructure/linkcheck"
+	"github.com/google/uuid"
+)
+
+type memorySyncStore struct {
+	mu      sync.Mutex
+	artists []artist.Artist
+	runs    []*artist.SyncRun
+	errors  map[uuid.UUID]map[uuid.UUID]bool
+}
+
+func (s *memorySyncStore) FindStaleArtists(ctx context.Context, staleBefore time.Time, runID uuid.UUID, cursor string, limit int) ([]artist.Artist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var stale []artist.Artist
+	for _, a := range s.artists {
+		if a.LastSyncedAt != nil && !a.LastSyncedAt.Before(staleBefore) {
+			continue
+		}
+		if s.errors[runID][a.ID] || (cursor != "" && a.ID.String() <= cursor) {
+			continue
+		}
+		stale = append(stale, a)
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].ID.String() < stale[j].ID.String() })
+	if len(stale) > limit {
+		stale = stale[:limit]
+	}
+	return stale, nil
+}
+
+func (s *memorySyncStore) MarkArtistSynced(ctx context.Context, artistID uuid.UUID, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.artists {
+		if s.artists[i].ID == artistID {
+			s.artists[i].LastSyncedAt = &at
+		}
+	}
+	return nil
+}
+
+func (s *memorySyncStore) FindResumableRun(ctx context.Context) (*artist.SyncRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, run := range s.runs {
+		if run.IsResumable() {
+			copied := *run
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memorySyncStore) CreateRun(ctx context.Context, run *artist.SyncRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run.ID = uuid.New()
+	copied := *run
+	s.runs = append(s.runs, &copied)
+	return nil
+}
+
+func (s *memorySyncStore) UpdateRunStatus(ctx context.Context, run *artist.SyncRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, stored := range s.runs {
+		if stored.ID == run.ID {
+			stored.Status, stored.FinishedAt = run.Status, run.FinishedAt
+		}
+	}
+	return nil
+}
+
+func (s *memorySyncStore) RecordSuccess(ctx context.Context, runID uuid.UUID) error {
+	return nil
+}
+
+func (s *memorySyncStore) RecordFailure(ctx context.Context, runID, artistID uuid.UUID, syncErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.errors == nil {
+		s.errors = make(map[uuid.UUID]map[uuid.UUID]bool)
+	}
+	if s.errors[runID] == nil {
+		s.errors[runID] = make(map[uuid.UUID]bool)
+	}
+	s.errors[runID][artistID] = true
+	return nil
+}
+
+type stubEnricher struct {
+	mu      sync.Mutex
+	synced  map[string]int
+	failFor string
+	onSync  func(a *artist.Artist)
+}
+
+func (e *stubEnricher) EnrichArtist(ctx context.Context, a *artist.Artist) error {
+	if e.onSync != nil {
+		e.onSync(a)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.synced[a.Name]++
+	if a.Name == e.failFor {
+		return errors.New("profile fetch failed")
+	}
+	return nil
+}
+
+func (e *stubEnricher) PreviewArtist(ctx context.Context, a *artist.Artist) ([]artist.ProfileChange, error) {
+	return []artist.ProfileChange{{Provider: "soundcloud", Field: "city", Old: "", New: "Berlin"}}, nil
+}
+
+func newSyncFixture(names ...string) *memorySyncStore {
+	store := &memorySyncStore{}
+	for _, name := range names {
+		store.artists = append(store.artists, artist.Artist{ID: uuid.New(), Name: name})
+	}
+	return store
+}
+
+func TestArtistSyncSkipsFreshArtistsAndRecordsFailures(t *testing.T) {
+	store := newSyncFixture("Stale", "Broken", "Fresh")
+	recent := time.Now()
+	store.artists[2].LastSyncedAt = &recent
+	enricher := &stubEnricher{synced: map[string]int{}, failFor: "Broken"}
+
+	engine := artistsync.NewEngine(store, enricher, artistsync.Config{StaleAfter: time.Hour, Workers: 2, BatchSize: 1}, nil)
+	run, err := engine.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+
+	if run.Status != artist.SyncCompleted || run.Succeeded != 1 || run.Failed != 1 {
+		t.Errorf("run = %s with %d succeeded, %d failed; want completed, 1, 1", run.Status, run.Succeeded, run.Failed)
+	}
+	if enricher.synced["Fresh"] != 0 {
+		t.Error("an artist synced within StaleAfter was synced again")
+	}
+}
+
+func TestArtistSyncResumesInterruptedRun(t *testing.T) {
+	store := newSyncFixture("One", "Two", "Three", "Four")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var once sync.Once
+	enricher := &stubEnricher{synced: map[string]int{}}
+	engine := artistsync.NewEngine(store, enricher, artistsync.Config{StaleAfter: time.Hour, Workers: 1, BatchSize: 10}, nil)
+
+	// Interrupt once the second artist starts syncing, artists are ordered by their random IDs
+	calls := 0
+	enricher.onSync = func(a *artist.Artist) {
+		calls++
+		if calls == 2 {
+			once.Do(cancel)
+		}
+	}
+	run, _ := engine.Sync(ctx)
+	if run == nil || run.Status != artist.SyncInterrupted {
+		t.Fatalf("first run = %+v, want interrupted", run)
+	}
+
+	enricher.onSync = nil
+	resumed, err := engine.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	if resumed.ID != run.ID || resumed.Status != artist.SyncCompleted {
+		t.Errorf("resumed run = %s %s, want %s completed", resumed.ID, resumed.Status, run.ID)
+	}
+	// The resumed run picks up where the interrupted one stopped, no artist is synced twice or skipped
+	for _, a := range store.artists {
+		if count := enricher.synced[a.Name]; count != 1 {
+			t.Errorf("artist %s synced %d times, want once", a.Name, count)
+		}
+	}
+	if len(store.runs) != 1 {
+		t.Errorf("got %d runs, want the interrupted run to be reused", len(store.runs))
+	}
+}
+
+func TestArtistSyncDryRunPrintsChanges(t *testing.T) {
+	store := newSyncFixture("Preview")
+	engine := artistsync.NewEngine(store, &stubEnricher{synced: map[string]int{}}, artistsync.Config{StaleAfter: time.Hour}, nil)
+
+	var out bytes.Buffer
+	if err := engine.DryRun(context.Background(), &out); err != nil {
+		t.Fatalf("DryRun returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), `soundcloud.city: "" -> "Berlin"`) {
+		t.Errorf("dry run output %q does not show the change", out.String())
+	}
+	if store.artists[0].LastSyncedAt != nil || len(store.runs) != 0 {
+		t.Error("dry run stored changes")
+	}
+}
+
+func TestWorkersRunWithoutInterval(t *testing.T) {
+	_, embedder := newSoundCloudEmbedder(t)
+	_, client := newFakeSoundCloud(t)
+	searcher := artistApi.NewSoundCloudProvider(client, artistApi.SoundCloudPriority)
+
+	// A zero interval from the environment must not reach time.NewTicker, the workers fall back to the default
+	workers := map[string]func(ctx context.Context){
+		"artist sync": artistsync.NewEngine(newSyncFixture(), &stubEnricher{synced: map[string]int{}}, artistsync.Config{}, nil).Run,
+		"discovery": discovery.NewDiscoverer(&memoryDiscoveryStore{linked: map[string]uuid.UUID{}, checked: map[uuid.UUID]time.Time{}},
+			searcher, &recordingLinker{links: map[uuid.UUID]string{}}, discovery.Config{}, nil).Run,
+		"promoted sets": promotedset.NewRefresher(&memoryPromotedSetStore{sets: map[uuid.UUID]*artist.PromotedSet{}}, embedder, nil, promotedset.Config{}, nil).Run,
+		"link check":    linkcheck.NewChecker(&memoryLinkStore{health: map[uuid.UUID]*artist.LinkHealth{}}, nil, linkcheck.Config{}, nil).Run,
+	}
+	for name, run := range workers {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s panicked without an interval: %v", name, r)
+				}
+			}()
+			run(ctx)
+		}()
+	}
+}
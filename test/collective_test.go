@@ -0,0 +1,205 @@
+package test
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/application/service"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/collective"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm/schema"
+)
+
+func TestCollectiveUniqueIndexesIgnoreDeletedRows(t *testing.T) {
+	for model, name := range map[interface{}]string{
+		&collective.Collective{}: "idx_collectives_active_name",
+		&collective.Membership{}: "idx_collective_memberships_active_member",
+	} {
+		parsed, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatalf("parsing %T: %v", model, err)
+		}
+		index, ok := parsed.ParseIndexes()[name]
+		if !ok {
+			t.Errorf("%s has no index %s", parsed.Table, name)
+			continue
+		}
+		// Soft-deleted rows must not block a collective name or a membership from being used again
+		if index.Class != "UNIQUE" || index.Where != "deleted_at IS NULL" {
+			t.Errorf("%s = %+v, want a unique index over rows that are not deleted", name, index)
+		}
+	}
+}
+
+func TestCollectiveDuplicatesAreConflicts(t *testing.T) {
+	duplicate := &pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint"}
+
+	db, fake := newFakeDB(t)
+	repo := repository.NewCollectiveRepository(db)
+	fake.fails(`INSERT INTO "collectives"`, duplicate)
+	if _, err := repo.Save(context.Background(), &collective.Collective{Name: "Kollektiv", Kind: collective.Crew}); apperror.KindOf(err) != apperror.KindConflict {
+		t.Errorf("taken name: err = %v, want a conflict", err)
+	}
+
+	fake.fails(`INSERT INTO "collective_memberships"`, duplicate)
+	membership := &collective.Membership{CollectiveID: uuid.New(), ArtistID: uuid.New()}
+	if _, err := repo.AddMember(context.Background(), membership); apperror.KindOf(err) != apperror.KindConflict {
+		t.Errorf("existing member: err = %v, want a conflict", err)
+	}
+}
+
+func TestCollectiveDeleteReleasesWhatItOwned(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewCollectiveRepository(db)
+	id := uuid.New()
+
+	if _, err := repo.Delete(context.Background(), id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	for _, table := range []string{"collective_memberships", "stage_takeovers", "social_media_links"} {
+		statement, args, ok := fake.find(`UPDATE "` + table + `" SET "deleted_at"`)
+		if !ok || !strings.Contains(statement, "collective_id = $2") || args[1] != id {
+			t.Errorf("%s of the collective not deleted: %v", table, fake.executed())
+		}
+	}
+	statement, args, ok := fake.find(`UPDATE "events" SET "host_collective_id"=$1`)
+	if !ok || args[0] != nil || !strings.Contains(statement, "host_collective_id = $3") {
+		t.Errorf("hosted events keep the collective: %v", fake.executed())
+	}
+	if statements := fake.executed(); statements[len(statements)-1] != "COMMIT" {
+		t.Errorf("statements = %v, want one transaction", statements)
+	}
+}
+
+func TestCollectiveDeleteReportsUnknownCollective(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewCollectiveRepository(db)
+	fake.affects(`UPDATE "collectives" SET "deleted_at"`, 0)
+
+	if _, err := repo.Delete(context.Background(), uuid.New()); apperror.KindOf(err) != apperror.KindNotFound {
+		t.Fatalf("err = %v, want not found", err)
+	}
+	if _, _, ok := fake.find(`UPDATE "events"`); ok {
+		t.Error("cleared event hosts of an unknown collective")
+	}
+	if statements := fake.executed(); statements[len(statements)-1] != "ROLLBACK" {
+		t.Errorf("statements = %v, want the transaction rolled back", statements)
+	}
+}
+
+func TestRemoveMemberReportsUnknownMember(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewCollectiveRepository(db)
+	fake.affects(`UPDATE "collective_memberships" SET "deleted_at"`, 0)
+
+	if _, err := repo.RemoveMember(context.Background(), uuid.New(), uuid.New()); apperror.KindOf(err) != apperror.KindNotFound {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
+
+func TestSetEventHostRequiresCollective(t *testing.T) {
+	db, fake := newFakeDB(t)
+	collectives := service.NewCollectiveService(repository.NewCollectiveRepository(db), repository.NewEventRepository(db))
+	collectiveID := uuid.New()
+
+	if _, err := collectives.SetEventHost(context.Background(), uuid.New(), &collectiveID); apperror.KindOf(err) != apperror.KindNotFound {
+		t.Fatalf("err = %v, want not found", err)
+	}
+	if _, _, ok := fake.find(`UPDATE "events"`); ok {
+		t.Error("assigned an unknown collective as event host")
+	}
+}
+
+func newTakeover() *collective.Takeover {
+	start := time.Date(2026, 7, 4, 22, 0, 0, 0, time.UTC)
+	return &collective.Takeover{CollectiveID: uuid.New(), StageID: uuid.New(), StartTime: start, EndTime: start.Add(6 * time.Hour)}
+}
+
+func TestTakeoverOverlaps(t *testing.T) {
+	takeover := newTakeover()
+	later := *takeover
+	later.StartTime, later.EndTime = takeover.EndTime, takeover.EndTime.Add(time.Hour)
+	overlapping := *takeover
+	overlapping.StartTime = takeover.EndTime.Add(-time.Minute)
+	otherStage := *takeover
+	otherStage.StageID = uuid.New()
+
+	if takeover.Overlaps(&later) || !takeover.Overlaps(&overlapping) || takeover.Overlaps(&otherStage) {
+		t.Error("takeovers overlap when they hold the same stage for a shared moment only")
+	}
+}
+
+func TestCreateTakeoverChecksOverlapUnderStageLock(t *testing.T) {
+	for _, overlapping := range []int64{0, 1} {
+		db, fake := newFakeDB(t)
+		repo := repository.NewCollectiveRepository(db)
+		takeover := newTakeover()
+		fake.returns(`FROM "stages"`, []string{"id"}, []driver.Value{takeover.StageID.String()})
+		fake.returns(`SELECT count(*) FROM "stage_takeovers"`, []string{"count"}, []driver.Value{overlapping})
+		fake.returns(`FROM "stage_takeovers" WHERE id = $1`, []string{"id", "collective_id", "stage_id", "start_time", "end_time"},
+			[]driver.Value{uuid.NewString(), takeover.CollectiveID.String(), takeover.StageID.String(), takeover.StartTime, takeover.EndTime})
+
+		_, err := repo.CreateTakeover(context.Background(), takeover)
+		if overlapping > 0 && apperror.KindOf(err) != apperror.KindConflict {
+			t.Fatalf("overlapping takeover: err = %v, want a conflict", err)
+		}
+		if overlapping == 0 && err != nil {
+			t.Fatalf("CreateTakeover: %v", err)
+		}
+
+		// The stage is locked before the check and stays locked until the transaction ends
+		statements := fake.executed()
+		if len(statements) < 3 || statements[0] != "BEGIN" ||
+			!strings.Contains(statements[1], `FROM "stages"`) || !strings.HasSuffix(statements[1], "FOR UPDATE") ||
+			!strings.Contains(statements[2], `SELECT count(*) FROM "stage_takeovers"`) {
+			t.Fatalf("statements = %v, want the stage locked before the overlap check", statements)
+		}
+		_, _, inserted := fake.find(`INSERT INTO "stage_takeovers"`)
+		if inserted != (overlapping == 0) {
+			t.Errorf("with %d overlapping takeovers: inserted = %v", overlapping, inserted)
+		}
+		want := "COMMIT"
+		if overlapping > 0 {
+			want = "ROLLBACK"
+		}
+		if last := statements[len(statements)-1]; last != want {
+			t.Errorf("with %d overlapping takeovers the transaction ended with %s, want %s", overlapping, last, want)
+		}
+	}
+}
+
+func TestCreateTakeoverReportsUnknownStage(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewCollectiveRepository(db)
+
+	if _, err := repo.CreateTakeover(context.Background(), newTakeover()); apperror.KindOf(err) != apperror.KindNotFound {
+		t.Fatalf("err = %v, want not found", err)
+	}
+	if _, _, ok := fake.find(`stage_takeovers`); ok {
+		t.Errorf("checked takeovers of an unknown stage: %v", fake.executed())
+	}
+}
+
+func TestCreateTakeoverRejectsInvertedTimes(t *testing.T) {
+	db, fake := newFakeDB(t)
+	collectives := service.NewCollectiveService(repository.NewCollectiveRepository(db), repository.NewEventRepository(db))
+	start := time.Date(2026, 7, 4, 22, 0, 0, 0, time.UTC)
+
+	_, err := collectives.CreateTakeover(context.Background(), models.CreateStageTakeoverInput{
+		CollectiveID: uuid.New(), StageID: uuid.New(), StartTime: start, EndTime: start.Add(-time.Hour),
+	})
+	if apperror.KindOf(err) != apperror.KindValidation {
+		t.Fatalf("err = %v, want a validation error", err)
+	}
+	if statements := fake.executed(); len(statements) != 0 {
+		t.Errorf("invalid takeover reached the database: %v", statements)
+	}
+}
@@ -0,0 +1,167 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/blnto/blnto_service/internal/api/graphql/constraint"
+	"github.com/blnto/blnto_service/internal/api/graphql/loaders"
+	"github.com/blnto/blnto_service/internal/api/graphql/presenter"
+	"github.com/blnto/blnto_service/internal/api/graphql/resolvers"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/validation"
+	"github.com/blnto/blnto_service/internal/infrastructure/graphql"
+	"go.uber.org/zap"
+)
+
+type fieldErrorsResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string `json:"message"`
+		Extensions struct {
+			Code   string                `json:"code"`
+			Fields []apperror.FieldError `json:"fields"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// newConstraintServer serves the schema without services, a resolver that runs panics so rejected inputs never
+// reach one
+func newConstraintServer() *handler.Server {
+	resolver := resolvers.NewResolver(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		loaders.NewFactory(nil, nil, nil, nil, nil, nil, loaders.DefaultConfig()))
+	srv := handler.New(graphql.NewExecutableSchema(graphql.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.POST{})
+	srv.SetErrorPresenter(presenter.ErrorPresenter(zap.NewNop()))
+	srv.Use(constraint.NewValidator())
+	return srv
+}
+
+func TestConstraintsReportAllFields(t *testing.T) {
+	srv := newConstraintServer()
+
+	body := `{"query":"mutation($input: CreateArtistInput!) { createArtist(input: $input) { id } }","variables":{"input":{
+		"name": "",
+		"soundcloudPermalink": "not a permalink",
+		"soundcloudPromotedSet": "ftp://example.com/set",
+		"socialMedia": [{"link": "https://instagram.com/artist"}, {"link": ""}]
+	}}}`
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var response fieldErrorsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("response %q: %v", rec.Body.String(), err)
+	}
+	if len(response.Errors) != 1 || response.Errors[0].Extensions.Code != "VALIDATION_FAILED" {
+		t.Fatalf("response = %s, want one validation error", rec.Body.String())
+	}
+
+	got := map[string]bool{}
+	for _, f := range response.Errors[0].Extensions.Fields {
+		got[f.Field] = true
+	}
+	want := []string{"input.name", "input.soundcloudPermalink", "input.soundcloudPromotedSet", "input.socialMedia.1.link"}
+	for _, field := range want {
+		if !got[field] {
+			t.Errorf("fields = %v, missing %s", response.Errors[0].Extensions.Fields, field)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("fields = %v, want %v", response.Errors[0].Extensions.Fields, want)
+	}
+
+	// Literal arguments are checked like variables
+	body = `{"query":"mutation { createTimetableEntry(input: {eventID: \"` + zeroID + `\", stageID: \"` + zeroID + `\", artistID: \"` + zeroID + `\", weekNumber: 54, year: 1999}) { id } }"}`
+	req = httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	response = fieldErrorsResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("response %q: %v", rec.Body.String(), err)
+	}
+	if len(response.Errors) != 1 || len(response.Errors[0].Extensions.Fields) != 2 {
+		t.Fatalf("response = %s, want weekNumber and year rejected", rec.Body.String())
+	}
+}
+
+func TestConstraintsAcceptSoundCloudProfileURLs(t *testing.T) {
+	srv := newConstraintServer()
+
+	for permalink, accepted := range map[string]bool{
+		"some-artist":                                    true,
+		"https://soundcloud.com/Some-Artist/":            true,
+		"soundcloud.com/some-artist?utm_source=x":        true,
+		"https://soundcloud.com/some-artist/night-drive": false,
+		"https://example.com/some-artist":                false,
+	} {
+		// The empty name keeps the resolver from running
+		body := `{"query":"mutation($input: CreateArtistInput!) { createArtist(input: $input) { id } }","variables":{"input":{
+			"name": "", "soundcloudPermalink": "` + permalink + `"}}}`
+		req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+
+		var response fieldErrorsResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil || len(response.Errors) != 1 {
+			t.Fatalf("response %q: %v", rec.Body.String(), err)
+		}
+		rejected := false
+		for _, f := range response.Errors[0].Extensions.Fields {
+			rejected = rejected || f.Field == "input.soundcloudPermalink"
+		}
+		if rejected == accepted {
+			t.Errorf("permalink %q rejected = %v, want %v", permalink, rejected, !accepted)
+		}
+	}
+}
+
+const zeroID = "00000000-0000-0000-0000-000000000000"
+
+func TestNormalizeLinksReportsEveryLink(t *testing.T) {
+	links := []artist.SocialMediaLink{
+		{Link: "not a url"},
+		{Link: "https://instagram.com/artist"},
+		{Link: "https://www.instagram.com/artist/"},
+		{Link: "https://soundcloud.com/artist", Platform: artist.Instagram},
+	}
+
+	err := validation.Prefix("input.socialMedia", artist.NormalizeLinks(links))
+	if !errors.Is(err, apperror.ErrValidation) {
+		t.Fatalf("err = %v, want a validation error", err)
+	}
+
+	var appErr *apperror.Error
+	errors.As(err, &appErr)
+	want := []string{"input.socialMedia.0.link", "input.socialMedia.2.link", "input.socialMedia.3.platform"}
+	if len(appErr.Fields) != len(want) {
+		t.Fatalf("fields = %v, want %v", appErr.Fields, want)
+	}
+	for i, field := range want {
+		if appErr.Fields[i].Field != field {
+			t.Errorf("field %d = %s, want %s", i, appErr.Fields[i].Field, field)
+		}
+	}
+}
+
+func TestPrefixKeepsOtherErrors(t *testing.T) {
+	if err := validation.Prefix("input", nil); err != nil {
+		t.Errorf("Prefix(nil) = %v", err)
+	}
+	cause := errors.New("connection refused")
+	if err := validation.Prefix("input", cause); err != cause {
+		t.Errorf("Prefix kept %v, want the error as is", err)
+	}
+}
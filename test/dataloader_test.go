@@ -0,0 +1,117 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/api/graphql/loaders"
+)
+
+type recordingFetch struct {
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (f *recordingFetch) fetch(ctx context.Context, keys []int) (map[int]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	batch := append([]int(nil), keys...)
+	sort.Ints(batch)
+	f.batches = append(f.batches, batch)
+
+	values := make(map[int]string)
+	for _, key := range keys {
+		if key >= 0 {
+			values[key] = strings.Repeat("x", key)
+		}
+	}
+	return values, nil
+}
+
+func (f *recordingFetch) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.batches)
+}
+
+func TestLoaderBatchesConcurrentLoads(t *testing.T) {
+	f := &recordingFetch{}
+	loader := loaders.NewLoader(f.fetch, 10*time.Millisecond, 0)
+
+	values, err := loader.LoadAll(context.Background(), []int{3, 1, 2, 1, -1})
+	if err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if strings.Join(values, ",") != "xxx,x,xx,x," {
+		t.Errorf("values = %q", values)
+	}
+	if len(f.batches) != 1 || len(f.batches[0]) != 4 {
+		t.Fatalf("batches = %v, want one batch of the 4 distinct keys", f.batches)
+	}
+
+	// Cached keys are not fetched again
+	if value, err := loader.Load(context.Background(), 2); err != nil || value != "xx" {
+		t.Errorf("Load(2) = %q, %v", value, err)
+	}
+	if f.count() != 1 {
+		t.Errorf("batches = %v, want the cached value", f.batches)
+	}
+}
+
+func TestLoaderSplitsLargeBatches(t *testing.T) {
+	f := &recordingFetch{}
+	loader := loaders.NewLoader(f.fetch, time.Hour, 2)
+
+	// A full batch is fetched right away, the rest waits for the next one to fill up
+	if _, err := loader.LoadAll(context.Background(), []int{1, 2, 3, 4}); err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if f.count() != 2 {
+		t.Errorf("batches = %v, want two batches of two keys", f.batches)
+	}
+}
+
+func TestLoaderReturnsFetchErrors(t *testing.T) {
+	failing := loaders.NewLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		return nil, errors.New("database down")
+	}, time.Millisecond, 0)
+	if _, err := failing.LoadAll(context.Background(), []int{1, 2}); err == nil || !strings.Contains(err.Error(), "database down") {
+		t.Errorf("error = %v, want the fetch error", err)
+	}
+
+	panicking := loaders.NewLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		panic("boom")
+	}, time.Millisecond, 0)
+	if _, err := panicking.Load(context.Background(), 1); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("error = %v, want the panic as an error", err)
+	}
+}
+
+func TestLoaderFactoryUsesRequestLoaders(t *testing.T) {
+	factory := loaders.NewFactory(nil, nil, nil, nil, nil, nil, loaders.DefaultConfig())
+	requestLoaders := factory.New()
+
+	var seen []*loaders.Loaders
+	handler := factory.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, factory.For(r.Context()), factory.For(r.Context()))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/query", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/query", nil))
+
+	if len(seen) != 4 || seen[0] != seen[1] || seen[2] != seen[3] {
+		t.Fatal("the resolvers of a request must share its loaders")
+	}
+	if seen[0] == seen[2] || seen[0] == requestLoaders {
+		t.Error("every request must get fresh loaders")
+	}
+	if factory.For(context.Background()) == factory.For(context.Background()) {
+		t.Error("loaders outside of a request must not be shared")
+	}
+}
@@ -0,0 +1,148 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/blnto/blnto_service/internal/api/graphql/loaders"
+	"github.com/blnto/blnto_service/internal/api/graphql/presenter"
+	"github.com/blnto/blnto_service/internal/api/graphql/resolvers"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/infrastructure/graphql"
+	"github.com/google/uuid"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestErrorPresenterReportsKinds(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	present := presenter.ErrorPresenter(zap.New(core))
+	ctx := context.Background()
+	id := uuid.New()
+
+	notFound := present(ctx, fmt.Errorf("error fetching venue: %w", apperror.NotFound("venue", id)))
+	if notFound.Extensions["code"] != "NOT_FOUND" || notFound.Message != "error fetching venue: venue "+id.String()+" not found" {
+		t.Errorf("not found = %q %v", notFound.Message, notFound.Extensions)
+	}
+	if !errors.Is(notFound, apperror.ErrNotFound) {
+		t.Error("the presented error must keep its cause")
+	}
+
+	invalid := present(ctx, apperror.Validation(
+		apperror.FieldError{Field: "input.name", Message: "name is required"},
+		apperror.FieldError{Field: "input.socialMedia.1.link", Message: "invalid link format"},
+	))
+	fields, _ := invalid.Extensions["fields"].([]apperror.FieldError)
+	if invalid.Extensions["code"] != "VALIDATION_FAILED" || len(fields) != 2 || fields[1].Field != "input.socialMedia.1.link" {
+		t.Errorf("validation = %q %v", invalid.Message, invalid.Extensions)
+	}
+
+	// Causes are logged, not shown
+	upstream := present(ctx, apperror.Upstream(errors.New("soundcloud transient (status 503)"), "promoted set could not be resolved"))
+	if upstream.Extensions["code"] != "UPSTREAM_ERROR" || upstream.Message != "promoted set could not be resolved" {
+		t.Errorf("upstream = %q %v", upstream.Message, upstream.Extensions)
+	}
+
+	plain := present(ctx, fmt.Errorf("error fetching venue: %w", errors.New("dial tcp 10.0.0.5:5432: connection refused")))
+	if plain.Extensions["code"] != "INTERNAL_SERVER_ERROR" || plain.Message != "internal server error" {
+		t.Errorf("untyped error = %q %v, want an internal error without its cause", plain.Message, plain.Extensions)
+	}
+	if logs.Len() != 2 {
+		t.Errorf("logged %d entries, want the upstream and the untyped error", logs.Len())
+	}
+
+	// Errors of the parser, the validator and extensions keep their own code
+	coded := gqlerror.Errorf("operation has depth 12")
+	coded.Extensions = map[string]interface{}{"code": "DEPTH_LIMIT_EXCEEDED"}
+	if presented := present(ctx, coded); presented.Extensions["code"] != "DEPTH_LIMIT_EXCEEDED" {
+		t.Errorf("gqlerror = %v", presented.Extensions)
+	}
+}
+
+func TestPanicsAreRecoveredAndLogged(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+
+	// Without services every resolver that reaches one panics
+	resolver := resolvers.NewResolver(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		loaders.NewFactory(nil, nil, nil, nil, nil, nil, loaders.DefaultConfig()))
+	srv := handler.New(graphql.NewExecutableSchema(graphql.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.POST{})
+	srv.SetErrorPresenter(presenter.ErrorPresenter(logger))
+	srv.SetRecoverFunc(presenter.Recover(logger))
+
+	body := `{"query":"query ArtistPage { getArtist(id: \"` + uuid.NewString() + `\") { name } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var response struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []struct {
+			Message    string                 `json:"message"`
+			Path       []interface{}          `json:"path"`
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("response %q: %v", rec.Body.String(), err)
+	}
+	if len(response.Errors) != 1 || response.Data["getArtist"] != nil {
+		t.Fatalf("response = %s, want null and one error", rec.Body.String())
+	}
+	if e := response.Errors[0]; e.Message != "internal server error" || e.Extensions["code"] != "INTERNAL_SERVER_ERROR" {
+		t.Errorf("error = %+v", e)
+	}
+
+	panics := logs.FilterMessage("GraphQL resolver panic").All()
+	if len(panics) != 1 {
+		t.Fatalf("logged %v, want the panic", logs.All())
+	}
+	if fields := panics[0].ContextMap(); fields["operation"] != "ArtistPage" || fields["path"] != "getArtist" {
+		t.Errorf("panic log fields = %v", fields)
+	}
+}
+
+func TestUnimplementedOperationsReportNotImplemented(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+
+	resolver := resolvers.NewResolver(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		loaders.NewFactory(nil, nil, nil, nil, nil, nil, loaders.DefaultConfig()))
+	srv := handler.New(graphql.NewExecutableSchema(graphql.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.POST{})
+	srv.SetErrorPresenter(presenter.ErrorPresenter(logger))
+	srv.SetRecoverFunc(presenter.Recover(logger))
+
+	body := `{"query":"{ stagesByVenue(venueID: \"` + uuid.NewString() + `\") { name } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var response struct {
+		Errors []struct {
+			Message    string                 `json:"message"`
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("response %q: %v", rec.Body.String(), err)
+	}
+	if len(response.Errors) != 1 || response.Errors[0].Extensions["code"] != "NOT_IMPLEMENTED" {
+		t.Fatalf("response = %s, want a NOT_IMPLEMENTED error", rec.Body.String())
+	}
+	if panics := logs.FilterMessage("GraphQL resolver panic").Len(); panics != 0 {
+		t.Errorf("logged %d panics, unimplemented operations must not panic", panics)
+	}
+}
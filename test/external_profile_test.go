@@ -0,0 +1,28 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+)
+
+func TestPreferredProfileUsesHighestPriorityWithData(t *testing.T) {
+	a := artist.Artist{
+		ExternalProfiles: []artist.ExternalProfile{
+			{Provider: "bandcamp", Priority: 10, AvatarURL: "https://bandcamp.example/avatar.jpg", City: "Berlin", Description: "From Bandcamp"},
+			{Provider: provider.SoundCloud, Priority: 100, AvatarURL: "https://soundcloud.example/avatar.jpg", Description: ""},
+		},
+	}
+
+	profile := a.PreferredProfile()
+	if profile.AvatarURL != "https://soundcloud.example/avatar.jpg" {
+		t.Errorf("AvatarURL = %q, want the SoundCloud avatar", profile.AvatarURL)
+	}
+	if profile.Description != "From Bandcamp" {
+		t.Errorf("Description = %q, want the lower-priority value when the higher one is empty", profile.Description)
+	}
+	if profile.City != "Berlin" {
+		t.Errorf("City = %q, want %q", profile.City, "Berlin")
+	}
+}
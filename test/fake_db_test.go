@@ -0,0 +1,166 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeDB is a database/sql driver that answers statements from a script, so repositories run their real gorm
+// queries without Postgres. It records every statement, transactions included, and answers a statement with the
+// first unused reply whose match it contains. Statements without a reply return no rows and affect one row.
+type fakeDB struct {
+	mu         sync.Mutex
+	statements []string
+	args       [][]driver.Value
+	replies    []*fakeReply
+}
+
+type fakeReply struct {
+	match    string
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+	used     bool
+}
+
+// newFakeDB opens gorm on a fakeDB through the postgres dialect.
+func newFakeDB(t *testing.T) (*gorm.DB, *fakeDB) {
+	t.Helper()
+	fake := &fakeDB{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(fake)}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("opening fake database: %v", err)
+	}
+	return db, fake
+}
+
+// returns answers the next statement containing match with the rows.
+func (f *fakeDB) returns(match string, columns []string, rows ...[]driver.Value) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.replies = append(f.replies, &fakeReply{match: match, columns: columns, rows: rows})
+}
+
+// affects answers the next statement containing match with the number of affected rows.
+func (f *fakeDB) affects(match string, affected int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.replies = append(f.replies, &fakeReply{match: match, affected: affected})
+}
+
+// fails answers the next statement containing match with err.
+func (f *fakeDB) fails(match string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.replies = append(f.replies, &fakeReply{match: match, err: err})
+}
+
+// executed returns the statements run so far.
+func (f *fakeDB) executed() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.statements...)
+}
+
+// find returns the first statement containing match and its arguments.
+func (f *fakeDB) find(match string) (string, []driver.Value, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, statement := range f.statements {
+		if strings.Contains(statement, match) {
+			return statement, f.args[i], true
+		}
+	}
+	return "", nil, false
+}
+
+func (f *fakeDB) answer(statement string, args []driver.NamedValue) *fakeReply {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	f.statements = append(f.statements, statement)
+	f.args = append(f.args, values)
+	for _, reply := range f.replies {
+		if !reply.used && strings.Contains(statement, reply.match) {
+			reply.used = true
+			return reply
+		}
+	}
+	return &fakeReply{affected: 1}
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return fakeDriver{db: f} }
+
+type fakeDriver struct{ db *fakeDB }
+
+func (d fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{db: d.db}, nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fake database does not prepare statements")
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.answer("BEGIN", nil)
+	return fakeTx{db: c.db}, nil
+}
+func (c *fakeConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	reply := c.db.answer(query, args)
+	if reply.err != nil {
+		return nil, reply.err
+	}
+	return &fakeRows{columns: reply.columns, rows: reply.rows}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	reply := c.db.answer(query, args)
+	if reply.err != nil {
+		return nil, reply.err
+	}
+	return driver.RowsAffected(reply.affected), nil
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (t fakeTx) Commit() error {
+	t.db.answer("COMMIT", nil)
+	return nil
+}
+func (t fakeTx) Rollback() error {
+	t.db.answer("ROLLBACK", nil)
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
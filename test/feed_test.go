@@ -0,0 +1,190 @@
+package test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/api/feed"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func TestFeedValidatorsFollowUpdates(t *testing.T) {
+	id, other := uuid.New(), uuid.New()
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+
+	base := feed.NewValidators("/feed/v1/venues", feed.Version{ID: id, UpdatedAt: updated})
+	if base != feed.NewValidators("/feed/v1/venues", feed.Version{ID: id, UpdatedAt: updated}) {
+		t.Error("validators of the same objects differ")
+	}
+	if !base.LastModified.Equal(updated.Truncate(time.Second)) {
+		t.Errorf("LastModified = %v, want %v", base.LastModified, updated.Truncate(time.Second))
+	}
+
+	changes := map[string]feed.Validators{
+		"update":   feed.NewValidators("/feed/v1/venues", feed.Version{ID: id, UpdatedAt: updated.Add(time.Nanosecond)}),
+		"addition": feed.NewValidators("/feed/v1/venues", feed.Version{ID: id, UpdatedAt: updated}, feed.Version{ID: other, UpdatedAt: updated}),
+		"removal":  feed.NewValidators("/feed/v1/venues"),
+		"resource": feed.NewValidators("/feed/v1/venues?limit=1", feed.Version{ID: id, UpdatedAt: updated}),
+	}
+	for change, validators := range changes {
+		if validators.ETag == base.ETag {
+			t.Errorf("ETag did not change on %s", change)
+		}
+	}
+}
+
+func TestFeedConditionalRequestsAndGzip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	validators := feed.NewValidators("/venue", feed.Version{ID: uuid.New(), UpdatedAt: updated})
+	cache := feed.Cache{MaxAge: time.Minute, StaleWhileRevalidate: 5 * time.Minute}
+
+	router := gin.New()
+	router.Use(feed.Gzip())
+	router.GET("/venue", func(c *gin.Context) {
+		cache.Respond(c, validators, gin.H{"name": "Berghain"})
+	})
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/venue", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get(map[string]string{"Accept-Encoding": "gzip"})
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("status %d, encoding %q, want a gzipped 200", rec.Code, rec.Header().Get("Content-Encoding"))
+	}
+	if rec.Header().Get("ETag") != validators.ETag || rec.Header().Get("Last-Modified") != "Wed, 01 May 2024 12:00:00 GMT" {
+		t.Errorf("validators = %q %q", rec.Header().Get("ETag"), rec.Header().Get("Last-Modified"))
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "public, max-age=60, stale-while-revalidate=300" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	body, _ := io.ReadAll(reader)
+	var venue map[string]string
+	if err := json.Unmarshal(body, &venue); err != nil || venue["name"] != "Berghain" {
+		t.Errorf("body = %q, %v", body, err)
+	}
+
+	if rec := get(map[string]string{"Accept-Encoding": "gzip;q=0"}); rec.Header().Get("Content-Encoding") != "" {
+		t.Error("compressed for a client refusing gzip")
+	}
+
+	notModified := []map[string]string{
+		{"If-None-Match": validators.ETag},
+		{"If-None-Match": `"other", ` + validators.ETag[2:]},
+		{"If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT"},
+	}
+	for _, headers := range notModified {
+		headers["Accept-Encoding"] = "gzip"
+		if rec := get(headers); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 || rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("%v: status %d, body %q, want an empty 304", headers, rec.Code, rec.Body.String())
+		}
+	}
+
+	// If-None-Match decides alone when present
+	modified := []map[string]string{
+		{"If-None-Match": `W/"other"`, "If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT"},
+		{"If-Modified-Since": "Wed, 01 May 2024 11:59:59 GMT"},
+	}
+	for _, headers := range modified {
+		if rec := get(headers); rec.Code != http.StatusOK {
+			t.Errorf("%v: status %d, want 200", headers, rec.Code)
+		}
+	}
+}
+
+func TestFeedListsRevalidateOnRemoval(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	venues := []feed.Version{{ID: uuid.New(), UpdatedAt: updated}, {ID: uuid.New(), UpdatedAt: updated.Add(-time.Hour)}}
+	cache := feed.Cache{MaxAge: time.Minute}
+
+	router := gin.New()
+	router.GET("/venues", func(c *gin.Context) {
+		cache.Respond(c, feed.NewListValidators(c.Request.URL.RequestURI(), venues...), gin.H{"items": len(venues)})
+	})
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/venues", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get(nil)
+	if first.Header().Get("Last-Modified") != "" {
+		t.Errorf("list sent Last-Modified %q", first.Header().Get("Last-Modified"))
+	}
+
+	// Removing the older venue leaves the latest update untouched, the list must still be served again
+	venues = venues[:1]
+	for _, headers := range []map[string]string{
+		{"If-None-Match": first.Header().Get("ETag")},
+		{"If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT"},
+	} {
+		if rec := get(headers); rec.Code != http.StatusOK || rec.Body.String() != `{"items":1}` {
+			t.Errorf("%v: status %d, body %q, want the shorter list", headers, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestFeedGzipFlushesCompressedData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	var flushed []byte
+
+	router := gin.New()
+	router.Use(feed.Gzip())
+	router.GET("/stream", func(c *gin.Context) {
+		_, _ = c.Writer.WriteString("first chunk")
+		c.Writer.Flush()
+		flushed = append(flushed, rec.Body.Bytes()...)
+		_, _ = c.Writer.WriteString(", second chunk")
+	})
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	router.ServeHTTP(rec, req)
+
+	reader, err := gzip.NewReader(bytes.NewReader(flushed))
+	if err != nil {
+		t.Fatalf("flushed %d bytes, want the gzip stream: %v", len(flushed), err)
+	}
+	chunk := make([]byte, len("first chunk"))
+	if _, err := io.ReadFull(reader, chunk); err != nil || string(chunk) != "first chunk" {
+		t.Errorf("flushed %q, %v, want the first chunk", chunk, err)
+	}
+}
+
+func TestFeedRejectsInvalidParameters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// Invalid requests are answered before a service is called
+	feed.NewHandler(nil, nil, nil, feed.DefaultConfig(), zap.NewNop()).Register(router)
+
+	for _, path := range []string{"/feed/v1/venues/not-a-uuid", "/feed/v1/artists?limit=0", "/feed/v1/artists?limit=201", "/feed/v1/venues?cursor=abc", "/feed/v1/events/upcoming?limit=x"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusBadRequest || rec.Header().Get("Cache-Control") != "no-store" {
+			t.Errorf("%s: status %d, Cache-Control %q, want an uncached 400", path, rec.Code, rec.Header().Get("Cache-Control"))
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+)
+
+func TestManualFieldWinsOverProviderValue(t *testing.T) {
+	fetchedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := artist.Artist{
+		ExternalProfiles: []artist.ExternalProfile{
+			{Provider: provider.SoundCloud, Priority: 100, City: "Berlin", Description: "From SoundCloud", FetchedAt: fetchedAt},
+		},
+	}
+
+	editedAt := fetchedAt.Add(time.Hour)
+	a.SetField(artist.FieldCity, "Leipzig", artist.SourceManual, editedAt)
+
+	city := a.ResolveField(artist.FieldCity)
+	if city.Value != "Leipzig" || city.Source != artist.SourceManual {
+		t.Errorf("city = %q from %q, want %q from manual", city.Value, city.Source, "Leipzig")
+	}
+	if city.UpdatedAt == nil || !city.UpdatedAt.Equal(editedAt) {
+		t.Errorf("city UpdatedAt = %v, want %v", city.UpdatedAt, editedAt)
+	}
+
+	description := a.ResolveField(artist.FieldDescription)
+	if description.Value != "From SoundCloud" || description.Source != artist.Source(provider.SoundCloud) {
+		t.Errorf("description = %q from %q, want the SoundCloud value", description.Value, description.Source)
+	}
+	if description.UpdatedAt == nil || !description.UpdatedAt.Equal(fetchedAt) {
+		t.Errorf("description UpdatedAt = %v, want the profile fetch time", description.UpdatedAt)
+	}
+
+	if got := a.DisplayProfile().City; got != "Leipzig" {
+		t.Errorf("DisplayProfile().City = %q, want the manual value", got)
+	}
+	if got := a.PreferredProfile().City; got != "Berlin" {
+		t.Errorf("PreferredProfile().City = %q, want the provider value", got)
+	}
+}
+
+func TestSyncedProfileDoesNotReplaceManualField(t *testing.T) {
+	a := artist.Artist{
+		ExternalProfiles: []artist.ExternalProfile{{Provider: provider.SoundCloud, Priority: 100, City: "Berlin"}},
+	}
+	a.SetField(artist.FieldCity, "", artist.SourceManual, time.Now())
+
+	a.ExternalProfiles[0].ApplyProfile(&provider.Profile{City: "Hamburg"}, time.Now())
+
+	if got := a.ResolveField(artist.FieldCity); got.Value != "" || got.Source != artist.SourceManual {
+		t.Errorf("city = %q from %q, want the cleared manual value", got.Value, got.Source)
+	}
+}
+
+func TestClearFieldFallsBackToProvider(t *testing.T) {
+	a := artist.Artist{
+		ExternalProfiles: []artist.ExternalProfile{{Provider: provider.SoundCloud, Priority: 100, Country: "Germany"}},
+	}
+	a.SetField(artist.FieldCountry, "Austria", artist.SourceImport, time.Now())
+	a.SetField(artist.FieldCountry, "Switzerland", artist.SourceManual, time.Now())
+	if len(a.FieldOverrides) != 1 {
+		t.Fatalf("len(FieldOverrides) = %d, want setting a field twice to keep one override", len(a.FieldOverrides))
+	}
+
+	a.ClearField(artist.FieldCountry)
+
+	country := a.ResolveField(artist.FieldCountry)
+	if country.Value != "Germany" || country.Source != artist.Source(provider.SoundCloud) {
+		t.Errorf("country = %q from %q, want the SoundCloud value", country.Value, country.Source)
+	}
+
+	if got := a.ResolveField(artist.FieldLastName); got.Source != "" || got.UpdatedAt != nil {
+		t.Errorf("last name source = %q, want no source when nothing has a value", got.Source)
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/blnto/blnto_service/internal/infrastructure/secrets"
+)
+
+func TestKeyringRotation(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, secrets.KeySize)
+	newKey := bytes.Repeat([]byte{2}, secrets.KeySize)
+
+	oldRing, err := secrets.NewKeyring("old", map[string][]byte{"old": oldKey})
+	if err != nil {
+		t.Fatalf("NewKeyring returned error: %v", err)
+	}
+	keyID, ciphertext, err := oldRing.Encrypt("refresh-token")
+	if err != nil {
+		t.Fatalf("Encrypt returned error: %v", err)
+	}
+	if ciphertext == "refresh-token" {
+		t.Fatal("ciphertext equals the plaintext")
+	}
+
+	// After rotation the old ciphertext still opens, new ones use the new key
+	rotated, err := secrets.NewKeyring("new", map[string][]byte{"old": oldKey, "new": newKey})
+	if err != nil {
+		t.Fatalf("NewKeyring returned error: %v", err)
+	}
+	if plaintext, err := rotated.Decrypt(keyID, ciphertext); err != nil || plaintext != "refresh-token" {
+		t.Fatalf("Decrypt() = %q, %v", plaintext, err)
+	}
+	if newID, _, _ := rotated.Encrypt("refresh-token"); newID != "new" {
+		t.Errorf("Encrypt used key %q, want %q", newID, "new")
+	}
+
+	// A ciphertext cannot be opened under another key ID
+	if _, err := rotated.Decrypt("new", ciphertext); err == nil {
+		t.Error("expected decrypting with the wrong key to fail")
+	}
+	withoutOld, _ := secrets.NewKeyring("new", map[string][]byte{"new": newKey})
+	if _, err := withoutOld.Decrypt(keyID, ciphertext); !errors.Is(err, secrets.ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestParseKeysRejectsShortKeys(t *testing.T) {
+	keys, err := secrets.ParseKeys([]string{"short:c2hvcnQ="})
+	if err != nil {
+		t.Fatalf("ParseKeys returned error: %v", err)
+	}
+	if _, err := secrets.NewKeyring("short", keys); err == nil {
+		t.Fatal("expected a key of the wrong size to be rejected")
+	}
+}
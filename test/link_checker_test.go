@@ -0,0 +1,88 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/infrastructure/linkcheck"
+	"github.com/google/uuid"
+)
+
+// memoryLinkStore keeps link health in memory and always reports every link as due.
+type memoryLinkStore struct {
+	mu     sync.Mutex
+	links  []artist.SocialMediaLink
+	health map[uuid.UUID]*artist.LinkHealth
+}
+
+func (s *memoryLinkStore) FindLinksDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]artist.SocialMediaLink, error) {
+	return s.links, nil
+}
+
+func (s *memoryLinkStore) FindByLinkID(ctx context.Context, linkID uuid.UUID) (*artist.LinkHealth, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if health, ok := s.health[linkID]; ok {
+		copied := *health
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (s *memoryLinkStore) Save(ctx context.Context, health *artist.LinkHealth) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *health
+	s.health[health.LinkID] = &copied
+	return nil
+}
+
+func TestLinkCheckerFlagsBrokenLinks(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/alive", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/alive-moved", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/alive-moved", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/dead", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	alive := artist.SocialMediaLink{ID: uuid.New(), Link: server.URL + "/alive"}
+	dead := artist.SocialMediaLink{ID: uuid.New(), Link: server.URL + "/dead"}
+	store := &memoryLinkStore{
+		links:  []artist.SocialMediaLink{alive, dead},
+		health: make(map[uuid.UUID]*artist.LinkHealth),
+	}
+
+	config := linkcheck.DefaultConfig()
+	config.PerHostInterval = 0
+	config.FailureThreshold = 2
+	checker := linkcheck.NewChecker(store, server.Client(), config, nil)
+
+	for round := 1; round <= 2; round++ {
+		if _, err := checker.CheckDue(context.Background()); err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+		deadHealth, _ := store.FindByLinkID(context.Background(), dead.ID)
+		if deadHealth.Broken != (round == 2) {
+			t.Fatalf("round %d: broken = %v, want %v", round, deadHealth.Broken, round == 2)
+		}
+	}
+
+	aliveHealth, _ := store.FindByLinkID(context.Background(), alive.ID)
+	if aliveHealth.Broken || *aliveHealth.StatusCode != http.StatusOK {
+		t.Fatalf("alive link reported as %+v", aliveHealth)
+	}
+	if *aliveHealth.FinalURL != server.URL+"/alive-moved" {
+		t.Fatalf("final URL = %s, want the redirect target", *aliveHealth.FinalURL)
+	}
+}
@@ -19,7 +19,7 @@ func CreateMockArtist() *artist.Artist {
 
 	sampleSCID := 123 // Sample SoundCloud ID
 	username := gofakeit.Username()
-	scPermalink := "http://example.com/permalink"
+	scPermalink := "sample-artist"
 	return &artist.Artist{
 		ID:               uuid.New(),
 		Name:             "Sample Artist",
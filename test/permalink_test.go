@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/blnto/blnto_service/internal/application/service"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/models"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+func TestNormalizePermalink(t *testing.T) {
+	for raw, want := range map[string]string{
+		"some-artist":                             "some-artist",
+		" Some_Artist ":                           "some_artist",
+		"https://soundcloud.com/Some-Artist/":     "some-artist",
+		"soundcloud.com/some-artist?utm_source=x": "some-artist",
+		"http://m.soundcloud.com/some-artist":     "some-artist",
+		"https://www.soundcloud.com/some-artist":  "some-artist",
+	} {
+		if got, err := artist.NormalizePermalink(raw); err != nil || got != want {
+			t.Errorf("NormalizePermalink(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{
+		"",
+		"some artist",
+		"https://soundcloud.com/some-artist/night-drive",
+		"https://on.soundcloud.com/AbC12dEf",
+		"https://example.com/some-artist",
+		"https://soundcloud.com/",
+	} {
+		if _, err := artist.NormalizePermalink(raw); apperror.KindOf(err) != apperror.KindValidation {
+			t.Errorf("NormalizePermalink(%q) err = %v, want a validation error", raw, err)
+		}
+	}
+}
+
+func TestLinkSoundCloudAccountComparesNormalizedPermalinks(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewArtistRepository(db)
+
+	if err := repo.LinkSoundCloudAccount(context.Background(), uuid.New(), 1001, "https://soundcloud.com/Fixture-Artist"); err != nil {
+		t.Fatalf("LinkSoundCloudAccount: %v", err)
+	}
+	// The uniqueness check and the stored value use the same form
+	_, check, _ := fake.find(`SELECT count(*) FROM "artists"`)
+	if len(check) < 2 || check[1] != "fixture-artist" {
+		t.Errorf("uniqueness check arguments = %v, want the permalink fixture-artist", check)
+	}
+	_, update, ok := fake.find(`UPDATE "artists" SET`)
+	if !ok || !containsValue(update, "fixture-artist") {
+		t.Errorf("stored arguments = %v, want the permalink fixture-artist", update)
+	}
+}
+
+func TestPermalinkExistsComparesNormalizedPermalinks(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewArtistRepository(db)
+	fake.returns(`SELECT count(*) FROM "artists"`, []string{"count"}, []driver.Value{int64(1)})
+
+	exists, err := repo.PermalinkExistsExcludingArtist(context.Background(), "soundcloud.com/Fixture-Artist/", uuid.New())
+	if err != nil || !exists {
+		t.Fatalf("PermalinkExistsExcludingArtist = %v, %v", exists, err)
+	}
+	if _, args, _ := fake.find(`SELECT count(*) FROM "artists"`); len(args) < 1 || args[0] != "fixture-artist" {
+		t.Errorf("arguments = %v, want the permalink fixture-artist", args)
+	}
+}
+
+func TestSaveArtistStoresNormalizedPermalink(t *testing.T) {
+	db, fake := newFakeDB(t)
+	artists := service.NewArtistService(repository.NewArtistRepository(db), nil)
+	permalink := "https://soundcloud.com/Some-Artist"
+
+	saved, err := artists.Save(context.Background(), &models.Artist{Name: "Some Artist", SoundcloudPermalink: &permalink})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if saved.SoundcloudPermalink == nil || *saved.SoundcloudPermalink != "some-artist" {
+		t.Errorf("saved permalink = %v, want some-artist", saved.SoundcloudPermalink)
+	}
+	if _, args, _ := fake.find(`INSERT INTO "artists"`); !containsValue(args, "some-artist") {
+		t.Errorf("insert arguments = %v, want the permalink some-artist", args)
+	}
+
+	track := "https://soundcloud.com/some-artist/night-drive"
+	_, err = artists.Save(context.Background(), &models.Artist{Name: "Some Artist", SoundcloudPermalink: &track})
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || len(appErr.Fields) != 1 || appErr.Fields[0].Field != "input.soundcloudPermalink" {
+		t.Errorf("track URL as permalink: err = %v, want input.soundcloudPermalink rejected", err)
+	}
+}
+
+// containsValue reports whether one of the statement arguments is want, nullable columns are passed as pointers
+func containsValue(values []driver.Value, want string) bool {
+	for _, v := range values {
+		if s, ok := v.(*string); ok && s != nil {
+			v = *s
+		}
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/blnto/blnto_service/internal/api/graphql/persisted"
+	"github.com/blnto/blnto_service/internal/infrastructure/graphql"
+	"github.com/vektah/gqlparser/v2"
+)
+
+const registeredTypename = "query Typename {\n\t__typename\n}"
+
+func TestRegistryFilesValidate(t *testing.T) {
+	registry, err := persisted.LoadRegistry("../operations", graphql.NewExecutableSchema(graphql.Config{}).Schema())
+	if err != nil {
+		t.Fatalf("LoadRegistry: %v", err)
+	}
+	if registry.Len() == 0 {
+		t.Error("the registry holds no operations")
+	}
+}
+
+func TestRegistryMatchesReformattedOperations(t *testing.T) {
+	schema := graphql.NewExecutableSchema(graphql.Config{}).Schema()
+	registry := persisted.NewRegistry()
+	_, err := registry.Register(`
+		query GetArtist($id: ID!) { getArtist(id: $id) { ...Card } }
+		fragment Card on Artist { id name }
+	`, schema)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	// Other whitespace, comments, definition order and other operations of the document do not matter
+	doc, errs := gqlparser.LoadQuery(schema, `
+		query Location($id: ID!) { getArtist(id: $id) { location } }
+		# the artist card
+		fragment Card on Artist {
+			id
+			name
+		}
+		query GetArtist($id: ID!) {
+			getArtist(id: $id) { ...Card }
+		}
+	`)
+	if errs != nil {
+		t.Fatalf("LoadQuery: %v", errs)
+	}
+	if op, ok := registry.Match(doc.Operations.ForName("GetArtist"), doc.Fragments); !ok || op.Name != "GetArtist" {
+		t.Errorf("Match = %v, %v, want GetArtist", op, ok)
+	}
+
+	doc, _ = gqlparser.LoadQuery(schema, `query GetArtist($id: ID!) { getArtist(id: $id) { ...Card } } fragment Card on Artist { id name location }`)
+	if _, ok := registry.Match(doc.Operations[0], doc.Fragments); ok {
+		t.Error("an operation selecting more fields must not match")
+	}
+
+	if _, err := registry.Register(`{ __typename }`, schema); err == nil {
+		t.Error("anonymous operations must not register")
+	}
+}
+
+func TestExtractOperations(t *testing.T) {
+	source := []byte("const CARD = gql`fragment Card on Artist { id name }`;\n" +
+		"export const GET_ARTIST = gql`\n  query GetArtist($id: ID!) { getArtist(id: $id) { ...Card } }\n  ${CARD}\n`;\n" +
+		"const BROKEN = graphql(`query Broken { noSuchField }`);")
+	docs := persisted.ExtractDocuments("src/artist.ts", source)
+	if len(docs) != 3 {
+		t.Fatalf("documents = %q, want 3", docs)
+	}
+
+	log := strings.Join([]string{
+		`{"level":"info","msg":"GraphQL Request","operation":"ListArtists","query":"query ListArtists { listArtists { edges { cursor } } }"}`,
+		`{"level":"info","msg":"GraphQL Request","formattedQuery":"{\"query\":\"query GetArtist($id: ID!) { getArtist(id: $id) { ...Card } }\"}"}`,
+		`{"level":"info","msg":"GraphQL Response","status":200}`,
+		`{"level":"info","msg":"GraphQL Request","query":"{ listArtists { edges { cursor } } }"}`,
+		`not json`,
+	}, "\n")
+	queries, err := persisted.ExtractLogQueries(strings.NewReader(log))
+	if err != nil || len(queries) != 3 {
+		t.Fatalf("ExtractLogQueries = %q, %v", queries, err)
+	}
+
+	extractions, errs := persisted.SplitOperations(append(docs, queries...), graphql.NewExecutableSchema(graphql.Config{}).Schema())
+	var names []string
+	for _, e := range extractions {
+		names = append(names, e.Name)
+	}
+	if strings.Join(names, ",") != "GetArtist,ListArtists" {
+		t.Errorf("operations = %v, want GetArtist and ListArtists once each", names)
+	}
+	if len(errs) != 2 {
+		t.Errorf("errors = %v, want the invalid and the anonymous operation", errs)
+	}
+	if !strings.Contains(extractions[0].Query, "fragment Card on Artist") {
+		t.Errorf("GetArtist = %q, want the fragment of the other document", extractions[0].Query)
+	}
+}
+
+func postPersistedQuery(t *testing.T, allowList *persisted.AllowList, body map[string]interface{}, header http.Header) (int, map[string]interface{}) {
+	t.Helper()
+	srv := handler.New(graphql.NewExecutableSchema(graphql.Config{}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.AutomaticPersistedQuery{Cache: allowList.Cache()})
+	srv.Use(allowList)
+
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(payload)))
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, response
+}
+
+func errorCode(response map[string]interface{}) string {
+	errs, _ := response["errors"].([]interface{})
+	if len(errs) == 0 {
+		return ""
+	}
+	extensions, _ := errs[0].(map[string]interface{})["extensions"].(map[string]interface{})
+	code, _ := extensions["code"].(string)
+	return code
+}
+
+func TestStrictModeRunsRegisteredOperationsOnly(t *testing.T) {
+	registry := persisted.NewRegistry()
+	op, err := registry.Register(registeredTypename, graphql.NewExecutableSchema(graphql.Config{}).Schema())
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	config := persisted.DefaultConfig()
+	config.Strict = true
+	config.BypassToken = "internal-secret"
+	allowList := persisted.NewAllowList(registry, config)
+
+	if status, response := postPersistedQuery(t, allowList, map[string]interface{}{"query": "query Typename { __typename }"}, nil); status != http.StatusOK || errorCode(response) != "" {
+		t.Errorf("registered operation: status %d, response %v", status, response)
+	}
+
+	apq := map[string]interface{}{"extensions": map[string]interface{}{"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": op.Hash}}}
+	if status, response := postPersistedQuery(t, allowList, apq, nil); status != http.StatusOK || errorCode(response) != "" {
+		t.Errorf("registered hash: status %d, response %v", status, response)
+	}
+
+	other := map[string]interface{}{"query": "query Other { __typename }"}
+	status, response := postPersistedQuery(t, allowList, other, nil)
+	if status != http.StatusUnprocessableEntity || errorCode(response) != persisted.CodeNotAllowed {
+		t.Errorf("unregistered operation: status %d, response %v", status, response)
+	}
+	if _, response := postPersistedQuery(t, allowList, other, http.Header{"X-Persisted-Query-Bypass": {"wrong"}}); errorCode(response) != persisted.CodeNotAllowed {
+		t.Errorf("wrong bypass token: response %v", response)
+	}
+	if status, response := postPersistedQuery(t, allowList, other, http.Header{"X-Persisted-Query-Bypass": {"internal-secret"}}); status != http.StatusOK || errorCode(response) != "" {
+		t.Errorf("bypass token: status %d, response %v", status, response)
+	}
+
+	// Outside of strict mode every operation runs and unknown hashes are remembered once sent with their query
+	open := persisted.NewAllowList(registry, persisted.DefaultConfig())
+	if _, response := postPersistedQuery(t, open, other, nil); errorCode(response) != "" {
+		t.Errorf("open mode: response %v", response)
+	}
+
+	cache := open.Cache()
+	hash := persisted.Hash("query Other { __typename }")
+	if _, ok := cache.Get(context.Background(), hash); ok {
+		t.Fatal("unknown hash found")
+	}
+	cache.Add(context.Background(), hash, "query Other { __typename }")
+	if _, ok := cache.Get(context.Background(), hash); !ok {
+		t.Error("open mode must remember sent queries")
+	}
+	strictCache := allowList.Cache()
+	strictCache.Add(context.Background(), hash, "query Other { __typename }")
+	if _, ok := strictCache.Get(context.Background(), hash); ok {
+		t.Error("strict mode must not remember unregistered queries")
+	}
+}
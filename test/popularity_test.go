@@ -0,0 +1,105 @@
+package test
+
+import (
+	"context"
+	"database/sql/driver"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+func TestNewPopularitySnapshotCountsTracksAndPlays(t *testing.T) {
+	media := []provider.Media{
+		{Kind: provider.Track, ExternalID: "1", Stats: provider.MediaStats{Plays: 120}},
+		{Kind: provider.Track, ExternalID: "2", Stats: provider.MediaStats{Plays: 30}},
+		{Kind: provider.Playlist, ExternalID: "3", Stats: provider.MediaStats{Plays: 1000}},
+	}
+
+	snapshot := artist.NewPopularitySnapshot(uuid.New(), provider.SoundCloud, 500, media, time.Now())
+	if snapshot.Tracks != 2 || snapshot.Plays != 150 || snapshot.Followers != 500 {
+		t.Errorf("snapshot = %+v, want 2 tracks, 150 plays and 500 followers", snapshot)
+	}
+}
+
+func snapshotRange(followersFrom, followersTo int, playsFrom, playsTo int64) artist.SnapshotRange {
+	id := uuid.New()
+	return artist.SnapshotRange{
+		ArtistID: id,
+		First:    artist.PopularitySnapshot{ArtistID: id, Followers: followersFrom, Plays: playsFrom},
+		Last:     artist.PopularitySnapshot{ArtistID: id, Followers: followersTo, Plays: playsTo},
+	}
+}
+
+func TestComputeTrendRanksRelativeGrowth(t *testing.T) {
+	weights := artist.DefaultTrendingWeights()
+
+	small := artist.ComputeTrend(snapshotRange(99, 199, 999, 1999), 0, weights)
+	large := artist.ComputeTrend(snapshotRange(99999, 100999, 999999, 1009999), 0, weights)
+	if small.Score <= large.Score {
+		t.Errorf("small artist doubling scored %v, large artist growing 1%% scored %v", small.Score, large.Score)
+	}
+	if math.Abs(small.FollowerGrowth-math.Log(2)) > 1e-9 {
+		t.Errorf("FollowerGrowth = %v, want ln 2", small.FollowerGrowth)
+	}
+	if small.Followers != 199 || small.Plays != 1999 {
+		t.Errorf("trend = %+v, want the latest counts", small)
+	}
+}
+
+func TestComputeTrendWeightsUpcomingBookings(t *testing.T) {
+	weights := artist.DefaultTrendingWeights()
+	r := snapshotRange(100, 150, 1000, 1500)
+
+	unbooked := artist.ComputeTrend(r, 0, weights)
+	booked := artist.ComputeTrend(r, 2, weights)
+	capped := artist.ComputeTrend(r, 40, weights)
+	if booked.Score <= unbooked.Score {
+		t.Errorf("booked artist scored %v, unbooked %v", booked.Score, unbooked.Score)
+	}
+	want := unbooked.Score * (1 + weights.Booking*float64(weights.MaxBookings))
+	if math.Abs(capped.Score-want) > 1e-9 {
+		t.Errorf("capped score = %v, want %v", capped.Score, want)
+	}
+
+	shrinking := snapshotRange(150, 100, 1500, 1500)
+	if a, b := artist.ComputeTrend(shrinking, 0, weights), artist.ComputeTrend(shrinking, 3, weights); a.Score != b.Score || a.Score >= 0 {
+		t.Errorf("shrinking artist scored %v unbooked and %v booked, want the same negative score", a.Score, b.Score)
+	}
+}
+
+func TestSnapshotRangesMeasureFromBeforeWindow(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewPopularityRepository(db)
+	since := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	known, added := uuid.New(), uuid.New()
+	columns := []string{"artist_id", "followers", "taken_at"}
+	// Snapshots are taken daily, so a day window holds a single snapshot of the known artist
+	fake.returns("taken_at <= $2", columns, []driver.Value{known.String(), int64(100), since.Add(-time.Hour)})
+	fake.returns("taken_at >= $2 ORDER BY artist_id, taken_at ASC", columns,
+		[]driver.Value{known.String(), int64(150), since.Add(23 * time.Hour)}, []driver.Value{added.String(), int64(10), since.Add(time.Hour)})
+	fake.returns("taken_at >= $2 ORDER BY artist_id, taken_at DESC", columns,
+		[]driver.Value{known.String(), int64(150), since.Add(23 * time.Hour)}, []driver.Value{added.String(), int64(20), since.Add(22 * time.Hour)})
+
+	ranges, err := repo.FindSnapshotRanges(context.Background(), provider.SoundCloud, since)
+	if err != nil {
+		t.Fatalf("FindSnapshotRanges: %v", err)
+	}
+	growth := map[uuid.UUID][2]int{}
+	for _, r := range ranges {
+		growth[r.ArtistID] = [2]int{r.First.Followers, r.Last.Followers}
+	}
+	if growth[known] != [2]int{100, 150} {
+		t.Errorf("known artist grew %v, want from the last snapshot before the window", growth[known])
+	}
+	if growth[added] != [2]int{10, 20} {
+		t.Errorf("new artist grew %v, want from its first snapshot in the window", growth[added])
+	}
+	if _, args, _ := fake.find("taken_at <= $2"); len(args) < 2 || args[0] != provider.SoundCloud || args[1] != since {
+		t.Errorf("baseline arguments = %v", args)
+	}
+}
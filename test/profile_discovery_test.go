@@ -0,0 +1,176 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/application/discovery"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/google/uuid"
+)
+
+type memoryDiscoveryStore struct {
+	mu          sync.Mutex
+	artists     []artist.Artist
+	linked      map[string]uuid.UUID
+	suggestions []artist.ProfileSuggestion
+	checked     map[uuid.UUID]time.Time
+}
+
+func (s *memoryDiscoveryStore) FindUnlinkedArtists(ctx context.Context, name provider.Name, checkedBefore time.Time, cursor string, limit int) ([]artist.Artist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var unlinked []artist.Artist
+	for _, a := range s.artists {
+		if at, ok := s.checked[a.ID]; ok && !at.Before(checkedBefore) {
+			continue
+		}
+		if cursor != "" && a.ID.String() <= cursor {
+			continue
+		}
+		unlinked = append(unlinked, a)
+	}
+	if len(unlinked) > limit {
+		unlinked = unlinked[:limit]
+	}
+	return unlinked, nil
+}
+
+func (s *memoryDiscoveryStore) MarkDiscoveryChecked(ctx context.Context, artistID uuid.UUID, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checked[artistID] = at
+	return nil
+}
+
+func (s *memoryDiscoveryStore) IsLinkedElsewhere(ctx context.Context, name provider.Name, externalID string, artistID uuid.UUID) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	owner, ok := s.linked[externalID]
+	return ok && owner != artistID, nil
+}
+
+func (s *memoryDiscoveryStore) SaveSuggestions(ctx context.Context, suggestions []artist.ProfileSuggestion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.suggestions = append(s.suggestions, suggestions...)
+	return nil
+}
+
+type recordingLinker struct {
+	links map[uuid.UUID]string
+}
+
+func (l *recordingLinker) Link(ctx context.Context, a *artist.Artist, suggestion *artist.ProfileSuggestion) error {
+	l.links[a.ID] = suggestion.Permalink()
+	return nil
+}
+
+func newDiscoveryFixture(t *testing.T, artists ...artist.Artist) (*discovery.Discoverer, *memoryDiscoveryStore, *recordingLinker) {
+	t.Helper()
+	_, client := newFakeSoundCloud(t)
+	for i := range artists {
+		artists[i].ID = uuid.New()
+	}
+	store := &memoryDiscoveryStore{artists: artists, linked: map[string]uuid.UUID{}, checked: map[uuid.UUID]time.Time{}}
+	linker := &recordingLinker{links: map[uuid.UUID]string{}}
+	searcher := artistApi.NewSoundCloudProvider(client, artistApi.SoundCloudPriority)
+	return discovery.NewDiscoverer(store, searcher, linker, discovery.DefaultConfig(), nil), store, linker
+}
+
+func TestNameSimilarityIgnoresCaseAccentsAndPunctuation(t *testing.T) {
+	if got := artist.NameSimilarity("DJ Émile", "dj-emile"); got != 1 {
+		t.Errorf("NameSimilarity = %v, want 1", got)
+	}
+	if got := artist.NameSimilarity("Fixture Artist", "Quiet Artist"); got >= 0.8 {
+		t.Errorf("NameSimilarity of different names = %v, want well below 1", got)
+	}
+	if got := artist.NameSimilarity("", "anything"); got != 0 {
+		t.Errorf("NameSimilarity with an empty name = %v, want 0", got)
+	}
+}
+
+func TestScoreCandidatePenalisesProfilesLinkedElsewhere(t *testing.T) {
+	a := &artist.Artist{Name: "Fixture Artist", Location: "Berlin"}
+	candidate := &provider.Profile{DisplayName: "Fixture Artist", City: "Berlin", Country: "Germany", Followers: 12500}
+
+	free := artist.ScoreCandidate(a, candidate, false)
+	if free.Name != 1 || free.Location != 1 {
+		t.Errorf("name and location scores = %v and %v, want 1 and 1", free.Name, free.Location)
+	}
+	if free.Total < 0.9 {
+		t.Errorf("Total = %v, want an exact match in the same city to score at least 0.9", free.Total)
+	}
+
+	linked := artist.ScoreCandidate(a, candidate, true)
+	if linked.Total >= free.Total-0.4 {
+		t.Errorf("Total for a profile linked elsewhere = %v, want it well below %v", linked.Total, free.Total)
+	}
+}
+
+func TestDiscoverAutoLinksClearMatch(t *testing.T) {
+	d, store, linker := newDiscoveryFixture(t, artist.Artist{Name: "Fixture Artist", Location: "Berlin"})
+
+	result, err := d.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if result.AutoLinked != 1 || result.Checked != 1 {
+		t.Errorf("result = %+v, want one artist checked and linked", result)
+	}
+
+	a := store.artists[0]
+	if linker.links[a.ID] != "fixture-artist" {
+		t.Errorf("linked permalink = %q, want %q", linker.links[a.ID], "fixture-artist")
+	}
+	if len(store.suggestions) != 1 || store.suggestions[0].Status != artist.SuggestionAutoLinked {
+		t.Fatalf("suggestions = %+v, want the linked candidate recorded as auto_linked", store.suggestions)
+	}
+	if _, ok := store.checked[a.ID]; !ok {
+		t.Error("artist was not marked as checked")
+	}
+}
+
+func TestDiscoverQueuesUncertainMatchForReview(t *testing.T) {
+	d, store, linker := newDiscoveryFixture(t, artist.Artist{Name: "Quiet Artist"})
+
+	result, err := d.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if result.AutoLinked != 0 || result.Suggested != 1 {
+		t.Errorf("result = %+v, want one suggestion and no link", result)
+	}
+	if len(linker.links) != 0 {
+		t.Errorf("links = %v, want none", linker.links)
+	}
+	if len(store.suggestions) != 1 || store.suggestions[0].Status != artist.SuggestionPending || store.suggestions[0].ExternalID != "1002" {
+		t.Errorf("suggestions = %+v, want user 1002 pending", store.suggestions)
+	}
+
+	// The artist is not searched again until RecheckAfter has passed
+	if result, err := d.Discover(context.Background()); err != nil || result.Checked != 0 {
+		t.Errorf("second Discover = %+v, %v, want nothing checked", result, err)
+	}
+}
+
+func TestDiscoverDoesNotLinkProfileOwnedByAnotherArtist(t *testing.T) {
+	d, store, linker := newDiscoveryFixture(t, artist.Artist{Name: "Fixture Artist", Location: "Berlin"})
+	store.linked["1001"] = uuid.New()
+
+	if _, err := d.Discover(context.Background()); err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if len(linker.links) != 0 {
+		t.Errorf("links = %v, want none for a profile that belongs to another artist", linker.links)
+	}
+	for _, s := range store.suggestions {
+		if s.ExternalID == "1001" && s.Status != artist.SuggestionPending {
+			t.Errorf("suggestion for 1001 has status %s, want it left for review", s.Status)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/application/promotedset"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi/fakesoundcloud"
+	"github.com/google/uuid"
+)
+
+const fixtureSetURL = "https://soundcloud.com/fixture-artist/closing-set"
+
+type memoryPromotedSetStore struct {
+	mu      sync.Mutex
+	artists []artist.Artist
+	sets    map[uuid.UUID]*artist.PromotedSet
+}
+
+func (s *memoryPromotedSetStore) FindDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]artist.Artist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []artist.Artist
+	for _, a := range s.artists {
+		if set, ok := s.sets[a.ID]; ok && set.LastCheckedAt != nil && !set.LastCheckedAt.Before(checkedBefore) {
+			continue
+		}
+		due = append(due, a)
+	}
+	return due, nil
+}
+
+func (s *memoryPromotedSetStore) FindByArtistID(ctx context.Context, artistID uuid.UUID) (*artist.PromotedSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if set, ok := s.sets[artistID]; ok {
+		copied := *set
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (s *memoryPromotedSetStore) Save(ctx context.Context, set *artist.PromotedSet) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *set
+	s.sets[set.ArtistID] = &copied
+	return nil
+}
+
+func newSoundCloudEmbedder(t *testing.T) (*fakesoundcloud.Server, *artistApi.SoundCloudProvider) {
+	t.Helper()
+	fake, client := newFakeSoundCloud(t)
+	return fake, artistApi.NewSoundCloudProvider(client, artistApi.SoundCloudPriority)
+}
+
+func TestSoundCloudResolveEmbed(t *testing.T) {
+	_, embedder := newSoundCloudEmbedder(t)
+
+	embed, err := embedder.ResolveEmbed(context.Background(), fixtureSetURL)
+	if err != nil {
+		t.Fatalf("ResolveEmbed: %v", err)
+	}
+	if embed.Title != "Closing Set by Fixture Artist" {
+		t.Errorf("Title = %q", embed.Title)
+	}
+	if embed.Duration != 90*time.Minute {
+		t.Errorf("Duration = %v, want 1h30m", embed.Duration)
+	}
+	if !strings.Contains(embed.HTML, "<iframe") || embed.ThumbnailURL == "" {
+		t.Errorf("embed = %+v, want player html and a thumbnail", embed)
+	}
+	if embed.URL != fixtureSetURL {
+		t.Errorf("URL = %q, want the canonical permalink", embed.URL)
+	}
+}
+
+func TestSoundCloudResolveEmbedRejectsUnusableURLs(t *testing.T) {
+	_, embedder := newSoundCloudEmbedder(t)
+
+	if _, err := embedder.ResolveEmbed(context.Background(), "https://soundcloud.com/fixture-artist"); !errors.Is(err, provider.ErrNotSupported) {
+		t.Errorf("profile URL error = %v, want ErrNotSupported", err)
+	}
+	if _, err := embedder.ResolveEmbed(context.Background(), "https://example.com/fixture-artist/closing-set"); !errors.Is(err, provider.ErrNotSupported) {
+		t.Errorf("foreign URL error = %v, want ErrNotSupported", err)
+	}
+	if _, err := embedder.ResolveEmbed(context.Background(), "https://soundcloud.com/fixture-artist/deleted-set"); !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("missing set error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPromotedSetRefresherFlagsAndRecovers(t *testing.T) {
+	fake, embedder := newSoundCloudEmbedder(t)
+	a := artist.Artist{ID: uuid.New(), SCPromotedSet: fixtureSetURL}
+	store := &memoryPromotedSetStore{artists: []artist.Artist{a}, sets: map[uuid.UUID]*artist.PromotedSet{}}
+	refresher := promotedset.NewRefresher(store, embedder, nil, promotedset.Config{FailureThreshold: 2}, nil)
+
+	// The set is gone for two rounds
+	fake.SetMode(fakesoundcloud.ModeNotFound)
+	for round := 1; round <= 2; round++ {
+		if _, err := refresher.RefreshDue(context.Background()); err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+	}
+	set := store.sets[a.ID]
+	if set == nil || !set.Broken || set.ConsecutiveFailures != 2 || set.LastError == nil {
+		t.Fatalf("set = %+v, want broken after two failures", set)
+	}
+
+	fake.SetMode(fakesoundcloud.ModeOK)
+	if _, err := refresher.RefreshDue(context.Background()); err != nil {
+		t.Fatalf("recovery round: %v", err)
+	}
+	set = store.sets[a.ID]
+	if set.Broken || set.ConsecutiveFailures != 0 || set.ResolvedAt == nil || set.Title == "" {
+		t.Errorf("set = %+v, want resolved and no longer broken", set)
+	}
+}
+
+func TestPromotedSetRefresherIgnoresTransientFailures(t *testing.T) {
+	fake, embedder := newSoundCloudEmbedder(t)
+	a := artist.Artist{ID: uuid.New(), SCPromotedSet: fixtureSetURL}
+	store := &memoryPromotedSetStore{artists: []artist.Artist{a}, sets: map[uuid.UUID]*artist.PromotedSet{}}
+	refresher := promotedset.NewRefresher(store, embedder, nil, promotedset.Config{FailureThreshold: 1}, nil)
+
+	fake.SetMode(fakesoundcloud.ModeServerError)
+	if _, err := refresher.RefreshDue(context.Background()); err != nil {
+		t.Fatalf("RefreshDue: %v", err)
+	}
+	if set := store.sets[a.ID]; set == nil || set.Broken || set.ConsecutiveFailures != 0 || set.LastError == nil {
+		t.Errorf("set = %+v, want the error recorded without counting as a failure", set)
+	}
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/blnto/blnto_service/internal/api/graphql/limits"
+	"github.com/blnto/blnto_service/internal/infrastructure/graphql"
+	"github.com/vektah/gqlparser/v2"
+)
+
+func queryCost(t *testing.T, query string, vars map[string]interface{}, config limits.Config) limits.Cost {
+	t.Helper()
+	schema := graphql.NewExecutableSchema(graphql.Config{}).Schema()
+	doc, errs := gqlparser.LoadQuery(schema, query)
+	if errs != nil {
+		t.Fatalf("LoadQuery: %v", errs)
+	}
+	return limits.Calculate(doc.Operations[0], vars, config)
+}
+
+func TestQueryCostWeighsConnectionsByFirst(t *testing.T) {
+	config := limits.DefaultConfig()
+
+	// listArtists costs 1 plus 50 times edges, node and name
+	cost := queryCost(t, `{ listArtists(first: 50) { edges { node { name } } } }`, nil, config)
+	if cost.Complexity != 151 || cost.Depth != 4 {
+		t.Errorf("cost = %+v, want complexity 151 and depth 4", cost)
+	}
+
+	// Variables count like literals, connections without first assume the default page size
+	cost = queryCost(t, `query($n: Int) { listArtists(first: $n) { edges { node { name } } } }`, map[string]interface{}{"n": int64(5)}, config)
+	if cost.Complexity != 16 {
+		t.Errorf("complexity with first from a variable = %d, want 16", cost.Complexity)
+	}
+	cost = queryCost(t, `{ listArtists { edges { node { name } } } }`, nil, config)
+	if cost.Complexity != 31 {
+		t.Errorf("complexity without first = %d, want 31", cost.Complexity)
+	}
+
+	// Fragments add their fields without adding depth, introspection is free
+	config.FieldCosts = map[string]int{"Artist.name": 4}
+	cost = queryCost(t, `{ __typename listArtists(first: 2) { edges { node { ...name } } } } fragment name on Artist { name }`, nil, config)
+	if cost.Complexity != 13 || cost.Depth != 4 {
+		t.Errorf("cost = %+v, want complexity 13 and depth 4", cost)
+	}
+}
+
+func postQuery(t *testing.T, config limits.Config, query string) (int, map[string]interface{}) {
+	t.Helper()
+	srv := handler.New(graphql.NewExecutableSchema(graphql.Config{}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(limits.NewLimiter(config))
+
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, response
+}
+
+func TestLimiterRejectsOverBudgetQueries(t *testing.T) {
+	config := limits.DefaultConfig()
+	config.MaxComplexity = 100
+	config.MaxDepth = 3
+
+	tests := []struct {
+		query string
+		code  string
+	}{
+		{`{ listArtists(first: 50) { edges { cursor } } }`, limits.CodeComplexityLimit},
+		{`{ listArtists(first: 1) { edges { node { name } } } }`, limits.CodeDepthLimit},
+	}
+	for _, tt := range tests {
+		status, response := postQuery(t, config, tt.query)
+		if status != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status = %d, want 422", tt.query, status)
+		}
+		errs, _ := response["errors"].([]interface{})
+		if len(errs) != 1 {
+			t.Fatalf("%s: errors = %v", tt.query, response["errors"])
+		}
+		extensions, _ := errs[0].(map[string]interface{})["extensions"].(map[string]interface{})
+		if extensions["code"] != tt.code || extensions["cost"] == nil {
+			t.Errorf("%s: extensions = %v, want code %s and the cost", tt.query, extensions, tt.code)
+		}
+	}
+}
+
+func TestLimiterReportsCost(t *testing.T) {
+	status, response := postQuery(t, limits.DefaultConfig(), `{ __typename }`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, response %v", status, response)
+	}
+	extensions, _ := response["extensions"].(map[string]interface{})
+	cost, _ := extensions["cost"].(map[string]interface{})
+	if cost == nil || cost["complexity"] != float64(0) || cost["maxComplexity"] != float64(2500) {
+		t.Errorf("extensions = %v, want the cost next to the budget", extensions)
+	}
+}
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gqlgen "github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/blnto/blnto_service/internal/api/graphql/loaders"
+	"github.com/blnto/blnto_service/internal/api/graphql/relay"
+	"github.com/blnto/blnto_service/internal/api/graphql/resolvers"
+	"github.com/blnto/blnto_service/internal/infrastructure/graphql"
+	"github.com/google/uuid"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestGlobalIDRoundTrip(t *testing.T) {
+	id := uuid.New()
+	global := relay.GlobalID{Type: "TimetableEntry", ID: id}
+
+	parsed, err := relay.ParseGlobalID(global.String())
+	if err != nil || parsed != global {
+		t.Fatalf("ParseGlobalID = %v, %v, want %v", parsed, err, global)
+	}
+	for _, invalid := range []string{id.String(), "not base64!", "QXJ0aXN0", "QXJ0aXN0Om5vLXV1aWQ="} {
+		if _, err := relay.ParseGlobalID(invalid); err == nil {
+			t.Errorf("ParseGlobalID(%q) succeeded", invalid)
+		}
+	}
+
+	// Arguments take global IDs and the plain UUIDs clients stored before
+	for _, input := range []string{global.String(), id.String()} {
+		if got, err := relay.UnmarshalID(context.Background(), input); err != nil || got != id {
+			t.Errorf("UnmarshalID(%q) = %v, %v, want %v", input, got, err, id)
+		}
+	}
+}
+
+func marshalIDField(object string, field string, interfaces []string, id uuid.UUID) string {
+	ctx := gqlgen.WithFieldContext(context.Background(), &gqlgen.FieldContext{
+		Object: object,
+		Field: gqlgen.CollectedField{Field: &ast.Field{
+			Name:             field,
+			ObjectDefinition: &ast.Definition{Name: object, Interfaces: interfaces},
+		}},
+	})
+	var buf bytes.Buffer
+	gqlgen.WrapContextMarshaler(ctx, relay.MarshalID(id)).MarshalGQL(&buf)
+	return buf.String()
+}
+
+func TestNodeIDsAreGlobal(t *testing.T) {
+	id := uuid.New()
+	want := `"` + relay.GlobalID{Type: "Venue", ID: id}.String() + `"`
+	if got := marshalIDField("Venue", "id", []string{"Node"}, id); got != want {
+		t.Errorf("Venue.id = %s, want %s", got, want)
+	}
+	if got := marshalIDField("Event", "venueID", []string{"Node"}, id); got != `"`+id.String()+`"` {
+		t.Errorf("Event.venueID = %s, want the plain UUID", got)
+	}
+	if got := marshalIDField("Collective", "id", nil, id); got != `"`+id.String()+`"` {
+		t.Errorf("Collective.id = %s, want the plain UUID", got)
+	}
+}
+
+func TestZeroIDIsAnError(t *testing.T) {
+	var buf bytes.Buffer
+	if err := relay.MarshalID(uuid.Nil).MarshalGQLContext(context.Background(), &buf); err == nil {
+		t.Errorf("MarshalID(uuid.Nil) wrote %s, want an error", buf.String())
+	}
+}
+
+func TestNodeQueryDispatchesOnType(t *testing.T) {
+	resolver := resolvers.NewResolver(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		loaders.NewFactory(nil, nil, nil, nil, nil, nil, loaders.DefaultConfig()))
+	srv := handler.New(graphql.NewExecutableSchema(graphql.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.POST{})
+
+	query := func(body string) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		var response map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("response %q: %v", rec.Body.String(), err)
+		}
+		return response
+	}
+
+	// Types without a node lookup resolve to null without touching a service
+	unknown := relay.GlobalID{Type: "Collective", ID: uuid.New()}.String()
+	response := query(`{"query":"query($ids: [ID!]!) { nodes(ids: $ids) { id } }","variables":{"ids":["` + unknown + `","` + unknown + `"]}}`)
+	if response["errors"] != nil {
+		t.Fatalf("nodes: %v", response["errors"])
+	}
+	nodes := response["data"].(map[string]interface{})["nodes"].([]interface{})
+	if len(nodes) != 2 || nodes[0] != nil || nodes[1] != nil {
+		t.Errorf("nodes = %v, want two nulls", nodes)
+	}
+
+	// node needs the type, a plain UUID cannot be dispatched
+	response = query(`{"query":"{ node(id: \"` + uuid.NewString() + `\") { id } }"}`)
+	errs, _ := response["errors"].([]interface{})
+	if len(errs) == 0 || !strings.Contains(errs[0].(map[string]interface{})["message"].(string), "plain UUID") {
+		t.Errorf("node with a plain UUID: %v, want an error naming the plain UUID", response)
+	}
+}
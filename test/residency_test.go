@@ -0,0 +1,166 @@
+package test
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/venue"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestResidencyOverlaps(t *testing.T) {
+	artistID, venueID := uuid.New(), uuid.New()
+	residency := func(start time.Time, end *time.Time) *venue.Residency {
+		return &venue.Residency{ArtistID: artistID, VenueID: venueID, StartDate: start, EndDate: end}
+	}
+	march, june, september := day(3, 31), day(6, 30), day(9, 30)
+
+	tests := []struct {
+		name string
+		a, b *venue.Residency
+		want bool
+	}{
+		{"disjoint", residency(day(1, 1), &march), residency(day(4, 1), &june), false},
+		{"shared end date", residency(day(1, 1), &march), residency(march, &june), true},
+		{"nested", residency(day(1, 1), &september), residency(day(4, 1), &june), true},
+		{"open-ended before", residency(day(1, 1), nil), residency(day(4, 1), &june), true},
+		{"open-ended after", residency(day(7, 1), nil), residency(day(4, 1), &june), false},
+		{"both open-ended", residency(day(7, 1), nil), residency(day(1, 1), nil), true},
+		{"other venue", residency(day(1, 1), nil), &venue.Residency{ArtistID: artistID, VenueID: uuid.New(), StartDate: day(1, 1)}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Overlaps(tt.b); got != tt.want {
+			t.Errorf("%s: Overlaps = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := tt.b.Overlaps(tt.a); got != tt.want {
+			t.Errorf("%s: reversed Overlaps = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func newResidencyFixture(t *testing.T) (*repository.ResidencyRepository, *fakeDB, *venue.Residency) {
+	db, fake := newFakeDB(t)
+	end := day(6, 30)
+	return repository.NewResidencyRepository(db), fake, &venue.Residency{
+		ArtistID: uuid.New(), VenueID: uuid.New(), StartDate: day(4, 1), EndDate: &end,
+	}
+}
+
+func TestResidencySaveRejectsOverlap(t *testing.T) {
+	repo, fake, residency := newResidencyFixture(t)
+	fake.returns(`FROM "venues"`, []string{"id"}, []driver.Value{residency.VenueID.String()})
+	fake.returns(`FROM "artists"`, []string{"id"}, []driver.Value{residency.ArtistID.String()})
+	fake.returns(`SELECT count(*) FROM "residencies"`, []string{"count"}, []driver.Value{int64(1)})
+
+	_, err := repo.Save(context.Background(), residency)
+	if apperror.KindOf(err) != apperror.KindConflict {
+		t.Fatalf("err = %v, want a conflict", err)
+	}
+
+	lock, _, _ := fake.find(`FROM "venues"`)
+	if !strings.HasSuffix(lock, "FOR UPDATE") {
+		t.Errorf("venue lookup = %s, want the venue locked", lock)
+	}
+	overlap, args, _ := fake.find(`SELECT count(*) FROM "residencies"`)
+	if !strings.Contains(overlap, "(end_date IS NULL OR end_date >= $4) AND start_date <= $5") {
+		t.Errorf("overlap query = %s", overlap)
+	}
+	if len(args) < 5 || args[3] != residency.StartDate || args[4] != *residency.EndDate {
+		t.Errorf("overlap arguments = %v", args)
+	}
+	if _, _, ok := fake.find(`INSERT INTO "residencies"`); ok {
+		t.Error("stored an overlapping residency")
+	}
+	if statements := fake.executed(); statements[len(statements)-1] != "ROLLBACK" {
+		t.Errorf("statements = %v, want the transaction rolled back", statements)
+	}
+}
+
+func TestResidencySaveStoresOpenEndedResidency(t *testing.T) {
+	repo, fake, residency := newResidencyFixture(t)
+	residency.EndDate = nil
+	fake.returns(`FROM "venues"`, []string{"id"}, []driver.Value{residency.VenueID.String()})
+	fake.returns(`FROM "artists"`, []string{"id"}, []driver.Value{residency.ArtistID.String()})
+	fake.returns(`SELECT count(*) FROM "residencies"`, []string{"count"}, []driver.Value{int64(0)})
+
+	if _, err := repo.Save(context.Background(), residency); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	overlap, _, _ := fake.find(`SELECT count(*) FROM "residencies"`)
+	if strings.Contains(overlap, "start_date <=") {
+		t.Errorf("overlap query = %s, an open-ended residency overlaps every later one", overlap)
+	}
+	if _, _, ok := fake.find(`INSERT INTO "residencies"`); !ok {
+		t.Errorf("residency not stored: %v", fake.executed())
+	}
+}
+
+func TestResidencySaveRequiresVenueAndArtist(t *testing.T) {
+	repo, _, residency := newResidencyFixture(t)
+	_, err := repo.Save(context.Background(), residency)
+	if apperror.KindOf(err) != apperror.KindNotFound || !strings.Contains(err.Error(), "venue") {
+		t.Errorf("without a venue: err = %v, want venue not found", err)
+	}
+
+	repo, fake, residency := newResidencyFixture(t)
+	fake.returns(`FROM "venues"`, []string{"id"}, []driver.Value{residency.VenueID.String()})
+	_, err = repo.Save(context.Background(), residency)
+	if apperror.KindOf(err) != apperror.KindNotFound || !strings.Contains(err.Error(), "artist") {
+		t.Errorf("without an artist: err = %v, want artist not found", err)
+	}
+}
+
+func TestResidencyDeleteReportsUnknownResidency(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewResidencyRepository(db)
+	fake.affects(`UPDATE "residencies" SET "deleted_at"`, 0)
+
+	if _, err := repo.Delete(context.Background(), uuid.New()); apperror.KindOf(err) != apperror.KindNotFound {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
+
+func TestFindDebutsRestrictsToVenue(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewEventRepository(db)
+	venueID := uuid.New()
+
+	if _, err := repo.FindDebuts(context.Background(), day(1, 1), day(2, 1), &venueID); err != nil {
+		t.Fatalf("FindDebuts: %v", err)
+	}
+	debuts, args, ok := fake.find(`FROM "timetable_entries"`)
+	if !ok {
+		t.Fatalf("no debut query in %v", fake.executed())
+	}
+	// An entry is a debut when no earlier set of the artist at the same venue exists
+	for _, want := range []string{"NOT EXISTS", "prev_event.venue_id = events.venue_id", "events.venue_id = $3", "ORDER BY timetable_entries.start_time ASC"} {
+		if !strings.Contains(debuts, want) {
+			t.Errorf("debut query lacks %q: %s", want, debuts)
+		}
+	}
+	if len(args) < 3 || args[2] != venueID {
+		t.Errorf("debut arguments = %v, want the venue %s", args, venueID)
+	}
+}
+
+func TestIsDebut(t *testing.T) {
+	for _, count := range []int64{0, 1} {
+		db, fake := newFakeDB(t)
+		repo := repository.NewEventRepository(db)
+		fake.returns(`SELECT count(*) FROM "timetable_entries"`, []string{"count"}, []driver.Value{count})
+
+		debut, err := repo.IsDebut(context.Background(), uuid.New())
+		if err != nil || debut != (count == 1) {
+			t.Errorf("with %d matching entries: IsDebut = %v, %v", count, debut, err)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/application/raimport"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/residentadvisor"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/residentadvisor/fixtures"
+	"github.com/google/uuid"
+)
+
+type memoryImportStore struct {
+	mu       sync.Mutex
+	artists  []artist.Artist
+	links    []artist.SocialMediaLink
+	profiles []artist.ExternalProfile
+}
+
+func (s *memoryImportStore) FindAllWithSocialMediaLinks(ctx context.Context) ([]artist.Artist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]artist.Artist(nil), s.artists...), nil
+}
+
+func (s *memoryImportStore) CreateSocialMediaLink(ctx context.Context, link artist.SocialMediaLink) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.links = append(s.links, link)
+	return nil
+}
+
+func (s *memoryImportStore) Upsert(ctx context.Context, profile *artist.ExternalProfile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles = append(s.profiles, *profile)
+	return nil
+}
+
+func newImportArtist(name string, links ...string) artist.Artist {
+	a := artist.Artist{ID: uuid.New(), Name: name}
+	for _, link := range links {
+		a.SocialMediaLinks = append(a.SocialMediaLinks, artist.SocialMediaLink{ArtistID: &a.ID, Platform: artist.ResidentAdvisor, Link: link})
+	}
+	return a
+}
+
+func newFixtureImporter(store *memoryImportStore, dryRun bool) *raimport.Importer {
+	config := raimport.DefaultConfig()
+	config.DryRun = dryRun
+	client := residentadvisor.NewClient(residentadvisor.NewFSFetcher(fixtures.Pages))
+	return raimport.NewImporter(store, store, client, config, nil)
+}
+
+func TestParseArtistPageReadsJSONLD(t *testing.T) {
+	// Subpages and old residentadvisor.net links load the canonical page
+	page, err := residentadvisor.NewClient(residentadvisor.NewFSFetcher(fixtures.Pages)).
+		ArtistPage(context.Background(), "https://www.residentadvisor.net/dj/Fixture-Artist/biography")
+	if err != nil {
+		t.Fatalf("ArtistPage: %v", err)
+	}
+	want := residentadvisor.ArtistPage{
+		URL:         "https://ra.co/dj/fixture-artist",
+		Slug:        "fixture-artist",
+		Name:        "Fixture Artist",
+		ImageURL:    "https://static.ra.co/images/profiles/fixture-artist.jpg",
+		Description: "Berlin based selector playing house & techno.",
+		City:        "Berlin",
+		Country:     "Germany",
+	}
+	if *page != want {
+		t.Errorf("page = %+v, want %+v", *page, want)
+	}
+}
+
+func TestParseArtistPageFallsBackToOpenGraph(t *testing.T) {
+	page, err := residentadvisor.NewClient(residentadvisor.NewFSFetcher(fixtures.Pages)).
+		ArtistPage(context.Background(), "https://ra.co/dj/open-graph-only")
+	if err != nil {
+		t.Fatalf("ArtistPage: %v", err)
+	}
+	if page.Name != "Open Graph Only" || page.ImageURL == "" || page.Description == "" {
+		t.Errorf("page = %+v, want the Open Graph title, image and description", page)
+	}
+
+	if _, err := residentadvisor.ParseArtistPage([]byte("<html></html>"), "https://ra.co/dj/empty"); !errors.Is(err, residentadvisor.ErrNoData) {
+		t.Errorf("empty page error = %v, want ErrNoData", err)
+	}
+}
+
+func TestParseEventPageReadsLineup(t *testing.T) {
+	page, err := residentadvisor.NewClient(residentadvisor.NewFSFetcher(fixtures.Pages)).
+		EventPage(context.Background(), "https://ra.co/events/1000001")
+	if err != nil {
+		t.Fatalf("EventPage: %v", err)
+	}
+	if page.Name != "Fixture Night & Friends" || page.Venue != "Fixture Club" || page.City != "Berlin" || page.Country != "Germany" {
+		t.Errorf("event = %+v", page)
+	}
+	if page.StartsAt == nil || !page.StartsAt.Equal(time.Date(2026, 11, 14, 23, 0, 0, 0, time.UTC)) {
+		t.Errorf("StartsAt = %v, want 2026-11-14 23:00", page.StartsAt)
+	}
+
+	want := []residentadvisor.Performer{
+		{Name: "Fixture Artist", URL: "https://ra.co/dj/fixture-artist"},
+		{Name: "Second Act", URL: "https://ra.co/dj/secondact"},
+		{Name: "Local Hero"},
+		{Name: "Unknown Guest", URL: "https://ra.co/dj/unknownguest"},
+	}
+	if len(page.Lineup) != len(want) {
+		t.Fatalf("lineup = %+v, want %+v", page.Lineup, want)
+	}
+	for i := range want {
+		if page.Lineup[i] != want[i] {
+			t.Errorf("lineup[%d] = %+v, want %+v", i, page.Lineup[i], want[i])
+		}
+	}
+}
+
+func TestHTTPFetcherUsesBaseURL(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path != "/dj/fixture-artist" {
+			http.NotFound(w, r)
+			return
+		}
+		page, _ := fixtures.Pages.ReadFile("dj/fixture-artist.html")
+		w.Write(page)
+	}))
+	defer server.Close()
+
+	p := residentadvisor.NewProvider(
+		residentadvisor.NewClient(residentadvisor.NewHTTPFetcher(residentadvisor.Config{BaseURL: server.URL, RequestInterval: -1})),
+		residentadvisor.DefaultPriority,
+	)
+	slug, err := p.ResolveURL(context.Background(), "https://ra.co/dj/Fixture-Artist")
+	if err != nil || slug != "fixture-artist" {
+		t.Fatalf("ResolveURL = %q, %v", slug, err)
+	}
+	profile, err := p.FetchProfile(context.Background(), slug)
+	if err != nil {
+		t.Fatalf("FetchProfile: %v", err)
+	}
+	if profile.ExternalID != "fixture-artist" || profile.DisplayName != "Fixture Artist" || profile.City != "Berlin" {
+		t.Errorf("profile = %+v", profile)
+	}
+
+	if _, err := p.FetchProfile(context.Background(), "missing"); !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("missing page error = %v, want ErrNotFound", err)
+	}
+	if strings.Join(paths, ",") != "/dj/fixture-artist,/dj/missing" {
+		t.Errorf("requested %v", paths)
+	}
+	if p.Supports("https://ra.co/events/1000001") || !p.Supports("https://residentadvisor.net/dj/secondact") {
+		t.Error("Supports must accept artist pages only")
+	}
+}
+
+func TestImporterMatchesEventLineup(t *testing.T) {
+	fixtureArtist := newImportArtist("Fixture Artist")
+	secondAct := newImportArtist("2nd Act", "https://www.residentadvisor.net/dj/SecondAct")
+	store := &memoryImportStore{artists: []artist.Artist{
+		fixtureArtist,
+		secondAct,
+		newImportArtist("Local Hero"),
+		newImportArtist("local-hero"),
+	}}
+
+	result, err := newFixtureImporter(store, false).ImportEvent(context.Background(), "https://ra.co/events/1000001")
+	if err != nil {
+		t.Fatalf("ImportEvent: %v", err)
+	}
+
+	byName := make(map[string]raimport.Match)
+	for _, match := range result.Lineup {
+		byName[match.Name] = match
+	}
+	if m := byName["Fixture Artist"]; m.ArtistID != fixtureArtist.ID || m.By != raimport.MatchedByName || !m.LinkAdded {
+		t.Errorf("Fixture Artist = %+v, want matched by name with the link added", m)
+	}
+	if m := byName["Second Act"]; m.ArtistID != secondAct.ID || m.By != raimport.MatchedByLink || m.LinkAdded {
+		t.Errorf("Second Act = %+v, want matched by the stored link", m)
+	}
+	if m := byName["Local Hero"]; m.Matched() || !strings.Contains(m.Problem, "2 artists") {
+		t.Errorf("Local Hero = %+v, want ambiguous", m)
+	}
+	if m := byName["Unknown Guest"]; m.Matched() || m.Problem == "" {
+		t.Errorf("Unknown Guest = %+v, want unmatched", m)
+	}
+
+	if len(store.links) != 1 {
+		t.Fatalf("links = %+v, want the link of Fixture Artist", store.links)
+	}
+	link := store.links[0]
+	if *link.ArtistID != fixtureArtist.ID || link.Platform != artist.ResidentAdvisor || link.Link != "https://ra.co/dj/fixture-artist" {
+		t.Errorf("link = %+v", link)
+	}
+}
+
+func TestImporterStoresArtistProfile(t *testing.T) {
+	fixtureArtist := newImportArtist("Fixture Artist", "https://ra.co/dj/fixture-artist")
+	store := &memoryImportStore{artists: []artist.Artist{fixtureArtist}}
+
+	result, err := newFixtureImporter(store, false).ImportArtist(context.Background(), "https://ra.co/dj/fixture-artist")
+	if err != nil {
+		t.Fatalf("ImportArtist: %v", err)
+	}
+	if result.Match.ArtistID != fixtureArtist.ID || result.Match.LinkAdded {
+		t.Errorf("match = %+v, want the linked artist", result.Match)
+	}
+	if len(store.profiles) != 1 {
+		t.Fatalf("profiles = %+v, want one", store.profiles)
+	}
+	profile := store.profiles[0]
+	if profile.Provider != provider.ResidentAdvisor || profile.Priority >= 100 || profile.ExternalID != "fixture-artist" || profile.City != "Berlin" {
+		t.Errorf("profile = %+v, want the RA profile below SoundCloud", profile)
+	}
+}
+
+func TestImporterDryRunStoresNothing(t *testing.T) {
+	store := &memoryImportStore{artists: []artist.Artist{newImportArtist("Fixture Artist")}}
+	importer := newFixtureImporter(store, true)
+
+	result, err := importer.ImportArtist(context.Background(), "https://ra.co/dj/fixture-artist")
+	if err != nil {
+		t.Fatalf("ImportArtist: %v", err)
+	}
+	if !result.Match.LinkAdded {
+		t.Errorf("match = %+v, want the link reported", result.Match)
+	}
+	event, err := importer.ImportEvent(context.Background(), "https://ra.co/events/1000001")
+	if err != nil {
+		t.Fatalf("ImportEvent: %v", err)
+	}
+	if m := event.Lineup[0]; m.By != raimport.MatchedByLink || m.LinkAdded {
+		t.Errorf("second page match = %+v, want matched by the link of the first page", m)
+	}
+	if len(store.links) != 0 || len(store.profiles) != 0 {
+		t.Errorf("dry run stored %d links and %d profiles", len(store.links), len(store.profiles))
+	}
+}
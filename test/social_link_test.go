@@ -0,0 +1,57 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/blnto/blnto_service/internal/domain/artist"
+)
+
+func TestCanonicalizeLink(t *testing.T) {
+	cases := []struct {
+		raw      string
+		want     string
+		platform artist.SocialMediaPlatform
+	}{
+		{"http://www.Instagram.com/Some.Artist/?igshid=abc123", "https://instagram.com/some.artist", artist.Instagram},
+		{"soundcloud.com/SomeArtist?utm_source=clipboard&utm_medium=text", "https://soundcloud.com/someartist", artist.Soundcloud},
+		{"https://x.com/SomeArtist", "https://x.com/someartist", artist.Twitter},
+		{"https://ra.co/dj/SomeArtist#bio", "https://ra.co/dj/someartist", artist.ResidentAdvisor},
+		{"https://someartist.bandcamp.com/album/Night-Drive", "https://someartist.bandcamp.com/album/Night-Drive", artist.Bandcamp},
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ&si=tracking", "https://youtube.com/watch?v=dQw4w9WgXcQ", artist.YouTube},
+		{"https://Some-Artist.com/Press/", "https://some-artist.com/Press", artist.Website},
+		// Only the handle is case-insensitive, short links and secret share links carry case-sensitive tokens
+		{"https://soundcloud.com/SomeArtist/Night-Drive", "https://soundcloud.com/someartist/Night-Drive", artist.Soundcloud},
+		{"https://www.tiktok.com/@SomeArtist/video/7301", "https://tiktok.com/@someartist/video/7301", artist.TikTok},
+		{"https://on.soundcloud.com/AbC12dEf", "https://on.soundcloud.com/AbC12dEf", artist.Soundcloud},
+		{"https://vm.tiktok.com/ZMeAbCdE/", "https://vm.tiktok.com/ZMeAbCdE", artist.TikTok},
+		{"https://soundcloud.com/SomeArtist/night-drive/s-AbCdE12345", "https://soundcloud.com/SomeArtist/night-drive/s-AbCdE12345", artist.Soundcloud},
+		{"https://soundcloud.com/someartist/sets/Live/s-XyZ98", "https://soundcloud.com/someartist/sets/Live/s-XyZ98", artist.Soundcloud},
+	}
+
+	for _, c := range cases {
+		got, platform, err := artist.CanonicalizeLink(c.raw)
+		if err != nil {
+			t.Fatalf("CanonicalizeLink(%q) returned error: %v", c.raw, err)
+		}
+		if got != c.want || platform != c.platform {
+			t.Errorf("CanonicalizeLink(%q) = %q, %s; want %q, %s", c.raw, got, platform, c.want, c.platform)
+		}
+	}
+}
+
+func TestNormalizeLinksRejectsDuplicates(t *testing.T) {
+	links := []artist.SocialMediaLink{
+		{Link: "https://soundcloud.com/someartist"},
+		{Link: "http://m.soundcloud.com/SomeArtist/?utm_campaign=share"},
+	}
+	if err := artist.NormalizeLinks(links); err == nil {
+		t.Fatal("expected duplicate links to be rejected")
+	}
+}
+
+func TestNormalizeRejectsMismatchedPlatform(t *testing.T) {
+	link := artist.SocialMediaLink{Platform: artist.Instagram, Link: "https://soundcloud.com/someartist"}
+	if err := link.Normalize(); err == nil {
+		t.Fatal("expected a platform mismatch to be rejected")
+	}
+}
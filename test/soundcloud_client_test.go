@@ -0,0 +1,203 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi/fakesoundcloud"
+)
+
+func newFakeSoundCloud(t *testing.T) (*fakesoundcloud.Server, *artistApi.SoundCloudClient) {
+	return newFakeSoundCloudWithBreaker(t, 10, time.Minute)
+}
+
+func newFakeSoundCloudWithBreaker(t *testing.T, threshold int, cooldown time.Duration) (*fakesoundcloud.Server, *artistApi.SoundCloudClient) {
+	t.Helper()
+	fake := fakesoundcloud.New(fakesoundcloud.DefaultFixtures(), "test-client", "test-secret")
+	server := httptest.NewServer(fake.Handler())
+	t.Cleanup(server.Close)
+
+	client := artistApi.NewClient(artistApi.NewMemoryTokenStore(), artistApi.Config{
+		BaseURL:      server.URL,
+		TokenURL:     server.URL + "/oauth2/token",
+		OEmbedURL:    server.URL + "/oembed",
+		AuthorizeURL: server.URL + "/authorize",
+		RedirectURL:  testRedirectURL,
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		Transport:    server.Client().Transport,
+
+		RequestsPerSecond: 1000,
+		Burst:             100,
+		BaseBackoff:       time.Millisecond,
+		MaxBackoff:        50 * time.Millisecond,
+		BreakerThreshold:  threshold,
+		BreakerCooldown:   cooldown,
+	})
+	return fake, client
+}
+
+func TestSoundCloudClientResolvesAndPaginatesTracks(t *testing.T) {
+	fake, client := newFakeSoundCloud(t)
+	fake.PageSize = 2
+
+	scArtist, err := client.FetchArtistByPermalink(context.Background(), "https://soundcloud.com/fixture-artist")
+	if err != nil {
+		t.Fatalf("FetchArtistByPermalink returned error: %v", err)
+	}
+	if scArtist.ID != 1001 || scArtist.City != "Berlin" {
+		t.Fatalf("resolved artist = %+v, want the fixture artist", scArtist)
+	}
+
+	tracks, err := client.FetchTracksByArtistId(context.Background(), "1001")
+	if err != nil {
+		t.Fatalf("FetchTracksByArtistId returned error: %v", err)
+	}
+	if len(tracks) != 5 {
+		t.Fatalf("got %d tracks, want 5", len(tracks))
+	}
+	if got := fake.Requests(fakesoundcloud.EndpointTracks); got != 3 {
+		t.Errorf("tracks endpoint called %d times, want 3 pages", got)
+	}
+	if got := fake.Requests(fakesoundcloud.EndpointToken); got != 1 {
+		t.Errorf("token endpoint called %d times, want 1", got)
+	}
+}
+
+func TestSoundCloudClientSurfacesServerErrors(t *testing.T) {
+	fake, client := newFakeSoundCloud(t)
+	fake.SetMode(fakesoundcloud.ModeServerError)
+
+	_, err := client.FetchArtistById(context.Background(), "1001")
+	if !errors.Is(err, artistApi.ErrTransient) {
+		t.Fatalf("expected a transient error while the server fails, got %v", err)
+	}
+}
+
+func TestSoundCloudClientRetriesTransientFailures(t *testing.T) {
+	fake, client := newFakeSoundCloud(t)
+	if _, err := client.FetchArtistById(context.Background(), "1001"); err != nil {
+		t.Fatalf("FetchArtistById returned error: %v", err)
+	}
+
+	fake.FailNext(2, fakesoundcloud.ModeServerError)
+	if _, err := client.FetchArtistById(context.Background(), "1001"); err != nil {
+		t.Fatalf("expected the call to succeed after retries, got %v", err)
+	}
+	if got := fake.Requests(fakesoundcloud.EndpointUsers); got != 4 {
+		t.Errorf("users endpoint called %d times, want 4", got)
+	}
+}
+
+func TestSoundCloudClientHonoursRetryAfter(t *testing.T) {
+	fake, client := newFakeSoundCloud(t)
+	if _, err := client.FetchArtistById(context.Background(), "1001"); err != nil {
+		t.Fatalf("FetchArtistById returned error: %v", err)
+	}
+
+	// A Retry-After longer than MaxBackoff is handed back instead of slept on
+	fake.RetryAfter = time.Minute
+	fake.FailNext(1, fakesoundcloud.ModeRateLimited)
+	_, err := client.FetchArtistById(context.Background(), "1001")
+
+	var apiErr *artistApi.APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != artistApi.KindRateLimited || apiErr.RetryAfter != time.Minute {
+		t.Fatalf("expected a rate limited error with Retry-After, got %v", err)
+	}
+}
+
+func TestSoundCloudClientClassifiesNotFound(t *testing.T) {
+	_, client := newFakeSoundCloud(t)
+
+	_, err := client.FetchArtistByPermalink(context.Background(), "https://soundcloud.com/nobody-here")
+	if !errors.Is(err, artistApi.ErrNotFound) || !errors.Is(err, provider.ErrNotFound) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestSoundCloudClientRenewsTokenOnlyWhenRejected(t *testing.T) {
+	fake, client := newFakeSoundCloud(t)
+	if _, err := client.FetchArtistById(context.Background(), "1001"); err != nil {
+		t.Fatalf("FetchArtistById returned error: %v", err)
+	}
+
+	// Access denied to a valid token is not fixed by a new token
+	fake.FailNext(1, fakesoundcloud.ModeForbidden)
+	_, err := client.FetchArtistById(context.Background(), "1001")
+	var apiErr *artistApi.APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != artistApi.KindPermanent || errors.Is(err, artistApi.ErrAuth) {
+		t.Fatalf("expected a permanent error for a forbidden resource, got %v", err)
+	}
+	if got := fake.Requests(fakesoundcloud.EndpointToken); got != 1 {
+		t.Errorf("token endpoint called %d times after a 403, want the token kept", got)
+	}
+
+	fake.FailNext(1, fakesoundcloud.ModeUnauthorized)
+	if _, err := client.FetchArtistById(context.Background(), "1001"); err != nil {
+		t.Fatalf("expected the call to succeed with a new token after a 401, got %v", err)
+	}
+	if got := fake.Requests(fakesoundcloud.EndpointToken); got != 2 {
+		t.Errorf("token endpoint called %d times after a 401, want the token replaced once", got)
+	}
+}
+
+func TestSoundCloudClientOpensCircuitBreaker(t *testing.T) {
+	fake, client := newFakeSoundCloudWithBreaker(t, 2, time.Hour)
+	if _, err := client.FetchArtistById(context.Background(), "1001"); err != nil {
+		t.Fatalf("FetchArtistById returned error: %v", err)
+	}
+
+	fake.SetMode(fakesoundcloud.ModeServerError)
+	_, _ = client.FetchArtistById(context.Background(), "1001")
+	requests := fake.Requests(fakesoundcloud.EndpointUsers)
+
+	_, err := client.FetchArtistById(context.Background(), "1001")
+	var unavailable *provider.UnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("expected the breaker to be open, got %v", err)
+	}
+	if got := fake.Requests(fakesoundcloud.EndpointUsers); got != requests {
+		t.Errorf("open breaker still sent %d requests", got-requests)
+	}
+}
+
+func TestSoundCloudClientReleasesCancelledProbe(t *testing.T) {
+	fake, client := newFakeSoundCloudWithBreaker(t, 1, 20*time.Millisecond)
+	if _, err := client.FetchArtistById(context.Background(), "1001"); err != nil {
+		t.Fatalf("FetchArtistById returned error: %v", err)
+	}
+	fake.SetMode(fakesoundcloud.ModeServerError)
+	_, _ = client.FetchArtistById(context.Background(), "1001")
+	time.Sleep(30 * time.Millisecond)
+
+	// The half-open probe is cancelled while waiting for a slow response
+	fake.Delay = 200 * time.Millisecond
+	fake.SetMode(fakesoundcloud.ModeSlow)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.FetchArtistById(ctx, "1001"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the probe to time out, got %v", err)
+	}
+
+	fake.SetMode(fakesoundcloud.ModeOK)
+	if _, err := client.FetchArtistById(context.Background(), "1001"); err != nil {
+		t.Fatalf("expected the next call to probe again, got %v", err)
+	}
+}
+
+func TestSoundCloudConfigRejectsPlaceholderCredentials(t *testing.T) {
+	config := artistApi.Config{ClientID: "your_client_id", ClientSecret: "your_client_secret"}
+	if err := config.Validate(); err == nil {
+		t.Fatal("expected the .env placeholders to be rejected")
+	}
+
+	config = artistApi.Config{ClientID: "client", ClientSecret: "secret"}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("expected real credentials to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/api/connect"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi/fakesoundcloud"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	testRedirectURL   = "https://blnto.test/connect/soundcloud/callback"
+	fixturePrivateSet = "https://soundcloud.com/fixture-artist/unreleased-set/s-fixtureSecret"
+)
+
+type soundCloudLink struct {
+	scID      int
+	permalink string
+}
+
+type memoryArtistLinker struct {
+	mu    sync.Mutex
+	links map[uuid.UUID]soundCloudLink
+	// err fails every link, e.g. because another artist has the SoundCloud ID
+	err error
+}
+
+func (l *memoryArtistLinker) LinkSoundCloudAccount(ctx context.Context, artistID uuid.UUID, scID int, permalink string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	l.links[artistID] = soundCloudLink{scID: scID, permalink: permalink}
+	return nil
+}
+
+func newAccountConnector(t *testing.T) (*fakesoundcloud.Server, *artistApi.SoundCloudClient, *artistApi.AccountConnector, *memoryArtistLinker) {
+	t.Helper()
+	fake, client := newFakeSoundCloud(t)
+	linker := &memoryArtistLinker{links: make(map[uuid.UUID]soundCloudLink)}
+	return fake, client, artistApi.NewAccountConnector(client, artistApi.NewMemoryAccountStore(), linker), linker
+}
+
+// authorize plays the browser on the authorization page and returns the query SoundCloud redirects back with.
+func authorize(t *testing.T, fake *fakesoundcloud.Server, authorizationURL string) url.Values {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	fake.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, authorizationURL, nil))
+	if recorder.Code != http.StatusFound {
+		t.Fatalf("authorization page answered %d", recorder.Code)
+	}
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("redirect location: %v", err)
+	}
+	if !strings.HasPrefix(location.String(), testRedirectURL) {
+		t.Fatalf("redirected to %s, want the redirect URL", location)
+	}
+	return location.Query()
+}
+
+func connectAccount(t *testing.T, fake *fakesoundcloud.Server, connector *artistApi.AccountConnector, artistID uuid.UUID) (*artistApi.AccountToken, error) {
+	t.Helper()
+	authorizationURL, err := connector.Start(context.Background(), artistID)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	callback := authorize(t, fake, authorizationURL)
+	return connector.Complete(context.Background(), callback.Get("state"), callback.Get("code"))
+}
+
+func TestAccountConnectorLinksVerifiedAccount(t *testing.T) {
+	fake, _, connector, linker := newAccountConnector(t)
+	fake.SignIn(1001)
+	artistID := uuid.New()
+
+	authorizationURL, err := connector.Start(context.Background(), artistID)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	query, _ := url.Parse(authorizationURL)
+	if query.Query().Get("code_challenge_method") != "S256" || query.Query().Get("code_challenge") == "" {
+		t.Errorf("authorization URL %s has no S256 code challenge", authorizationURL)
+	}
+
+	callback := authorize(t, fake, authorizationURL)
+	account, err := connector.Complete(context.Background(), callback.Get("state"), callback.Get("code"))
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if account.UserID != 1001 || account.Permalink != "fixture-artist" || account.ArtistID != artistID {
+		t.Errorf("account = %+v, want fixture-artist connected to the artist", account)
+	}
+	if link := linker.links[artistID]; link.scID != 1001 || link.permalink != "fixture-artist" {
+		t.Errorf("link = %+v, want the verified id and permalink", link)
+	}
+
+	if _, err := connector.Complete(context.Background(), callback.Get("state"), callback.Get("code")); !errors.Is(err, artistApi.ErrInvalidState) {
+		t.Errorf("replayed callback error = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestAccountConnectorReportsDeclinedAuthorization(t *testing.T) {
+	fake, _, connector, _ := newAccountConnector(t)
+	fake.SignIn(0)
+
+	authorizationURL, err := connector.Start(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if callback := authorize(t, fake, authorizationURL); callback.Get("error") != "access_denied" || callback.Get("code") != "" {
+		t.Errorf("callback = %v, want access_denied without a code", callback)
+	}
+}
+
+func TestAccountConnectorRejectsAccountOfAnotherArtist(t *testing.T) {
+	fake, _, connector, _ := newAccountConnector(t)
+	fake.SignIn(1001)
+
+	if _, err := connectAccount(t, fake, connector, uuid.New()); err != nil {
+		t.Fatalf("first connection: %v", err)
+	}
+	if _, err := connectAccount(t, fake, connector, uuid.New()); !errors.Is(err, artistApi.ErrAccountTaken) {
+		t.Errorf("second connection error = %v, want ErrAccountTaken", err)
+	}
+}
+
+func TestAccountConnectorKeepsNoAccountWhenLinkingFails(t *testing.T) {
+	fake, _, connector, linker := newAccountConnector(t)
+	fake.SignIn(1001)
+	linker.err = apperror.Conflict("soundcloud account is already linked to another artist")
+	artistID := uuid.New()
+
+	if _, err := connectAccount(t, fake, connector, artistID); !errors.Is(err, apperror.ErrConflict) {
+		t.Fatalf("error = %v, want the linker's conflict", err)
+	}
+	if account, err := connector.Account(context.Background(), artistID); err != nil || account != nil {
+		t.Errorf("account = %+v, %v, want none after the failed link", account, err)
+	}
+}
+
+func TestAccountConnectorRefreshesExpiringToken(t *testing.T) {
+	fake, _, connector, _ := newAccountConnector(t)
+	fake.SignIn(1001)
+	// Tokens this short-lived are within the expiry skew right away
+	fake.TokenTTL = 10 * time.Second
+	artistID := uuid.New()
+
+	account, err := connectAccount(t, fake, connector, artistID)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	token, err := connector.AccessToken(context.Background(), artistID)
+	if err != nil {
+		t.Fatalf("AccessToken: %v", err)
+	}
+	if token == "" || token == account.AccessToken {
+		t.Errorf("AccessToken returned %q, want a refreshed token", token)
+	}
+
+	if token, err := connector.AccessToken(context.Background(), uuid.New()); token != "" || err != nil {
+		t.Errorf("AccessToken of an artist without account = %q, %v", token, err)
+	}
+}
+
+func TestResolveEmbedForUsesConnectedAccount(t *testing.T) {
+	fake, client, connector, _ := newAccountConnector(t)
+	fake.SignIn(1001)
+	embedder := artistApi.NewSoundCloudProvider(client, artistApi.SoundCloudPriority)
+	artistID := uuid.New()
+	if _, err := connectAccount(t, fake, connector, artistID); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	embed, err := provider.ResolveEmbedFor(context.Background(), embedder, connector, artistID, fixturePrivateSet)
+	if err != nil {
+		t.Fatalf("ResolveEmbedFor: %v", err)
+	}
+	if embed.URL != fixturePrivateSet || embed.Duration != 2*time.Hour {
+		t.Errorf("embed = %+v, want the private set", embed)
+	}
+
+	_, err = provider.ResolveEmbedFor(context.Background(), embedder, connector, uuid.New(), fixturePrivateSet)
+	if !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("private set without account error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStartLinksAuthorizeTheirArtistOnly(t *testing.T) {
+	links := connect.NewStartLinks("test-connect-secret")
+	artistID := uuid.New()
+	signed := links.Sign(artistID, time.Now().Add(time.Hour))
+	if got, err := links.Verify(signed); err != nil || got != artistID {
+		t.Fatalf("Verify = %s, %v, want the signed artist", got, err)
+	}
+
+	tampered := url.Values{"artistId": {uuid.NewString()}, "expires": signed["expires"], "signature": signed["signature"]}
+	unauthorized := map[string]url.Values{
+		"unsigned":       {"artistId": {artistID.String()}},
+		"other artist":   tampered,
+		"expired":        links.Sign(artistID, time.Now().Add(-time.Minute)),
+		"without secret": connect.NewStartLinks("").Sign(artistID, time.Now().Add(time.Hour)),
+	}
+	for name, query := range unauthorized {
+		if _, err := links.Verify(query); !errors.Is(err, connect.ErrUnauthorizedLink) {
+			t.Errorf("%s: error = %v, want ErrUnauthorizedLink", name, err)
+		}
+	}
+	if _, err := connect.NewStartLinks("").Verify(signed); !errors.Is(err, connect.ErrUnauthorizedLink) {
+		t.Errorf("verified a link without a secret configured: %v", err)
+	}
+
+	// Unsigned starts are refused before a connection is begun
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/connect/soundcloud/start", connect.NewSoundCloudHandler(nil, links, "", zap.NewNop()).Start)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/connect/soundcloud/start?artistId="+artistID.String(), nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("unsigned start answered %d, want 403", rec.Code)
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi"
+	"github.com/blnto/blnto_service/internal/infrastructure/api/artistApi/fakesoundcloud"
+)
+
+type countingIssuer struct {
+	requests  int32
+	refreshes int32
+}
+
+func (i *countingIssuer) RequestAccessToken(ctx context.Context) (*artistApi.AccessTokenResponse, error) {
+	atomic.AddInt32(&i.requests, 1)
+	time.Sleep(20 * time.Millisecond)
+	return &artistApi.AccessTokenResponse{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 3600}, nil
+}
+
+func (i *countingIssuer) RefreshAccessToken(ctx context.Context, refreshToken string) (*artistApi.AccessTokenResponse, error) {
+	atomic.AddInt32(&i.refreshes, 1)
+	return &artistApi.AccessTokenResponse{AccessToken: "refreshed", ExpiresIn: 3600}, nil
+}
+
+func TestTokenManagerSharesConcurrentRefresh(t *testing.T) {
+	issuer := &countingIssuer{}
+	manager := artistApi.NewTokenManager(artistApi.NewMemoryTokenStore(), issuer, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if token, err := manager.Token(context.Background()); err != nil || token.AccessToken != "access" {
+				t.Errorf("Token() = %v, %v", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&issuer.requests); got != 1 {
+		t.Errorf("token requested %d times, want 1", got)
+	}
+}
+
+func TestTokenManagerFallsBackWhenRefreshTokenIsRejected(t *testing.T) {
+	fake, client := newFakeSoundCloud(t)
+	if _, err := client.FetchArtistById(context.Background(), "1001"); err != nil {
+		t.Fatalf("FetchArtistById returned error: %v", err)
+	}
+
+	// The server forgets every token, the refresh grant fails and client credentials must be used
+	fake.ExpireTokens()
+	fake.RevokeRefreshTokens()
+	if _, err := client.FetchArtistById(context.Background(), "1001"); err != nil {
+		t.Fatalf("expected the client to recover with a new token, got %v", err)
+	}
+	if got := fake.Requests(fakesoundcloud.EndpointToken); got != 3 {
+		t.Errorf("token endpoint called %d times, want 3 (initial, rejected refresh, client credentials)", got)
+	}
+}
+
+func TestTokenManagerRunStopsWithContext(t *testing.T) {
+	manager := artistApi.NewTokenManager(artistApi.NewMemoryTokenStore(), &countingIssuer{}, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		manager.Run(ctx, time.Millisecond)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after the context was cancelled")
+	}
+}
@@ -0,0 +1,156 @@
+package test
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blnto/blnto_service/internal/application/service"
+	"github.com/blnto/blnto_service/internal/domain/apperror"
+	"github.com/blnto/blnto_service/internal/domain/artist"
+	"github.com/blnto/blnto_service/internal/domain/provider"
+	"github.com/blnto/blnto_service/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+func TestTrackPagesBreakPublishDateTiesByID(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewTrackRepository(db)
+	cursor := uuid.New()
+	published := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+	// Both tracks were published at the same moment as the cursor's track, the IDs order them
+	second, third := uuid.MustParse("00000000-0000-0000-0000-000000000002"), uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	fake.returns(`SELECT count(*) FROM "artist_tracks"`, []string{"count"}, []driver.Value{int64(1)})
+	fake.returns(`FROM "artist_tracks" WHERE (artist_id`, []string{"id", "published_at"},
+		[]driver.Value{second.String(), published}, []driver.Value{third.String(), published})
+
+	tracks, nextCursor, err := repo.FindByArtistID(context.Background(), uuid.New(), cursor.String(), 2)
+	if err != nil {
+		t.Fatalf("FindByArtistID: %v", err)
+	}
+	if len(tracks) != 2 || nextCursor != third.String() {
+		t.Errorf("page = %d tracks and cursor %s, want 2 tracks ending with %s", len(tracks), nextCursor, third)
+	}
+
+	page, args, _ := fake.find(`FROM "artist_tracks" WHERE (artist_id`)
+	for _, want := range []string{
+		"(COALESCE(published_at, '-infinity'), id) < (SELECT COALESCE(published_at, '-infinity'), id FROM artist_tracks WHERE id = $2)",
+		"ORDER BY COALESCE(published_at, '-infinity') DESC,id DESC",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page query lacks %q: %s", want, page)
+		}
+	}
+	if len(args) < 2 || args[1] != cursor {
+		t.Errorf("page arguments = %v, want the cursor %s", args, cursor)
+	}
+}
+
+func TestTrackPagesValidateCursor(t *testing.T) {
+	db, fake := newFakeDB(t)
+	repo := repository.NewTrackRepository(db)
+
+	if _, _, err := repo.FindByArtistID(context.Background(), uuid.New(), "42", 10); apperror.KindOf(err) != apperror.KindValidation {
+		t.Errorf("malformed cursor: err = %v, want a validation error", err)
+	}
+	fake.returns(`SELECT count(*) FROM "artist_tracks"`, []string{"count"}, []driver.Value{int64(0)})
+	if _, _, err := repo.FindByArtistID(context.Background(), uuid.New(), uuid.NewString(), 10); apperror.KindOf(err) != apperror.KindNotFound {
+		t.Errorf("unknown cursor: err = %v, want not found instead of an empty page", err)
+	}
+}
+
+// stubProvider serves a fixed profile and media list for every URL
+type stubProvider struct {
+	media    []provider.Media
+	mediaErr error
+}
+
+func (p *stubProvider) Name() provider.Name         { return "stub" }
+func (p *stubProvider) Priority() int               { return 1 }
+func (p *stubProvider) Supports(rawURL string) bool { return true }
+func (p *stubProvider) ResolveURL(ctx context.Context, rawURL string) (string, error) {
+	return "stub-artist", nil
+}
+func (p *stubProvider) FetchProfile(ctx context.Context, externalID string) (*provider.Profile, error) {
+	return &provider.Profile{ExternalID: externalID, Username: "someartist", Followers: 10}, nil
+}
+func (p *stubProvider) FetchMedia(ctx context.Context, externalID string) ([]provider.Media, error) {
+	return p.media, p.mediaErr
+}
+
+func enrichWithStub(t *testing.T, p *stubProvider) (*fakeDB, *artist.Artist) {
+	db, fake := newFakeDB(t)
+	enrichment := service.NewEnrichmentService(provider.NewRegistry(p), repository.NewArtistRepository(db),
+		repository.NewExternalProfileRepository(db), repository.NewTrackRepository(db), repository.NewPopularityRepository(db))
+	a := &artist.Artist{ID: uuid.New(), SocialMediaLinks: []artist.SocialMediaLink{{Link: "https://example.com/someartist"}}}
+	if err := enrichment.EnrichArtist(context.Background(), a); err != nil {
+		t.Fatalf("EnrichArtist: %v", err)
+	}
+	return fake, a
+}
+
+func TestSyncTracksMarksTracksMissingSinceTheSync(t *testing.T) {
+	fake, a := enrichWithStub(t, &stubProvider{media: []provider.Media{
+		{Kind: provider.Track, ExternalID: "1", Title: "Night Drive"},
+		{Kind: provider.Playlist, ExternalID: "2", Title: "Live Sets"},
+		{Kind: provider.Track, Title: "Without an ID"},
+	}})
+
+	upsert, values, ok := fake.find(`INSERT INTO "artist_tracks"`)
+	if !ok {
+		t.Fatalf("tracks not stored: %v", fake.executed())
+	}
+	columns := strings.Split(upsert[strings.Index(upsert, "(")+1:strings.Index(upsert, ")")], ",")
+	if rows := len(values) / len(columns); rows != 1 {
+		t.Errorf("stored %d tracks, want only the track with an ID", rows)
+	}
+	if !strings.Contains(upsert, `ON CONFLICT ("provider","external_id") DO UPDATE`) || !strings.Contains(upsert, `"removed_at"="excluded"."removed_at"`) {
+		t.Errorf("tracks are not upserted and restored: %s", upsert)
+	}
+	var seenAt time.Time
+	for i, column := range columns {
+		if strings.TrimSpace(column) == `"last_seen_at"` {
+			seenAt, _ = values[i].(time.Time)
+		}
+	}
+
+	// Every track of the artist that this sync did not see is marked removed
+	mark, args, ok := fake.find(`UPDATE "artist_tracks" SET "removed_at"`)
+	if !ok {
+		t.Fatalf("missing tracks not marked: %v", fake.executed())
+	}
+	if !strings.Contains(mark, "artist_id = $3 AND provider = $4 AND last_seen_at < $5 AND removed_at IS NULL") {
+		t.Errorf("mark query = %s", mark)
+	}
+	if len(args) < 5 || args[2] != a.ID || args[3] != provider.Name("stub") {
+		t.Errorf("mark arguments = %v, want tracks of %s from stub", args, a.ID)
+	}
+	// Tracks stored by this sync were seen at its start, so only tracks from earlier syncs are marked
+	if since, _ := args[4].(time.Time); seenAt.IsZero() || !since.Equal(seenAt) {
+		t.Errorf("marked tracks not seen since %v, want since the sync start %v", args[4], seenAt)
+	}
+	if strings.Index(strings.Join(fake.executed(), "\n"), upsert) > strings.Index(strings.Join(fake.executed(), "\n"), mark) {
+		t.Error("marked missing tracks before storing the tracks seen")
+	}
+}
+
+func TestSyncTracksMarksAllTracksMissingWhenNoneAreListed(t *testing.T) {
+	fake, _ := enrichWithStub(t, &stubProvider{})
+
+	if _, _, ok := fake.find(`INSERT INTO "artist_tracks"`); ok {
+		t.Error("stored tracks although the provider listed none")
+	}
+	if _, _, ok := fake.find(`UPDATE "artist_tracks" SET "removed_at"`); !ok {
+		t.Errorf("tracks the provider no longer lists were kept: %v", fake.executed())
+	}
+}
+
+func TestSyncTracksKeepsTracksOfProvidersWithoutMedia(t *testing.T) {
+	fake, _ := enrichWithStub(t, &stubProvider{mediaErr: provider.ErrNotSupported})
+
+	if _, _, ok := fake.find(`UPDATE "artist_tracks"`); ok {
+		t.Error("marked tracks removed for a provider that does not list media")
+	}
+}